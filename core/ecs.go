@@ -145,6 +145,24 @@ func (ecs *ECSManager) GetEntitiesWithComponents(requiredTypes []reflect.Type) [
 	return entities
 }
 
+// AddSystem registers a system to be updated every frame
+func (m *ECSManager) AddSystem(system System) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.systems = append(m.systems, system)
+}
+
+// AddRenderSystem registers a render system, which is updated with the other
+// systems and then rendered every frame
+func (m *ECSManager) AddRenderSystem(system RenderSystem) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.systems = append(m.systems, system)
+	m.renderSystems = append(m.renderSystems, system)
+}
+
 // UpdateSystems runs all systems with the given delta time
 func (m *ECSManager) UpdateSystems(dt float64) {
 	for _, system := range m.systems {
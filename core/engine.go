@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"time"
 
+	"2d_game_engine/physics"
+
 	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/sdl"
 )
@@ -37,7 +39,7 @@ type GameEngine struct {
 	ECS   *ECSManager
 	Render  *Renderer
 	Scenes  *SceneManager
-	// Physics *PhysicsSystem
+	Physics *physics.World
 	// Audio   *AudioManager
 }
 
@@ -88,6 +90,7 @@ func NewGameEngine(title string, width, height int32, targetFPS int) (*GameEngin
 		ECS:             NewECSManager(),   // Initialize the inputManager
 		Render:          NewRenderer(renderer,sdl.Color{R: 0, G: 0, B: 0, A: 255}),   // Black background
 		Scenes:          NewSceneManager(),
+		Physics:         physics.NewWorld(),
 	}
 
 	return engine, nil
@@ -178,6 +181,9 @@ func (ge *GameEngine) updatePhysics(fixedDeltaTime float64) {
 	// Update physics systems with fixed timestep
 	// This ensures consistent physics regardless of frame rate
 
+	// Step the physics world (integration of every body)
+	ge.Physics.Step(fixedDeltaTime)
+
 	// Update physics in scene manager
 	ge.Scenes.UpdatePhysics(fixedDeltaTime)
 
@@ -219,6 +225,9 @@ func (ge *GameEngine) render(interpolation float64) {
 	// Interpolation allows rendering positions between physics steps
 	ge.Scenes.Render(interpolation)
 
+	// ECS render systems, such as particles, draw over the scene
+	ge.ECS.RenderSystems(ge.renderer)
+
 	// Present the frame
 	ge.Render.EndFrame()
 }
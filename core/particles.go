@@ -0,0 +1,373 @@
+package core
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"time"
+
+	"2d_game_engine/physics/geometry"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type Vector2D = geometry.Vector2D
+
+// -----------------------------------------------------------------------------
+// Particle effects: sparks, smoke, dust. Particles are purely visual and do not
+// touch the physics world. Each emitter keeps a fixed pool of particles, and
+// the system draws every emitter's particles as one batch of textured quads, so
+// tens of thousands of particles stay cheap.
+// -----------------------------------------------------------------------------
+
+// Range is a span of values that particles pick from at random.
+type Range struct {
+	Min, Max float64
+}
+
+// Fixed returns a range holding a single value.
+func Fixed(value float64) Range {
+	return Range{Min: value, Max: value}
+}
+
+func (r Range) random(rng *rand.Rand) float64 {
+	return r.Min + (r.Max-r.Min)*rng.Float64()
+}
+
+// CurveKey is a value at a point in a particle's life, from 0 (born) to 1
+// (dead).
+type CurveKey struct {
+	Time  float64
+	Value float64
+}
+
+// Curve maps a particle's life to a value by interpolating between keys in
+// time order. An empty curve is 1 throughout.
+type Curve []CurveKey
+
+// ConstantCurve returns a curve that is always value.
+func ConstantCurve(value float64) Curve {
+	return Curve{{Time: 0, Value: value}}
+}
+
+// LinearCurve returns a curve that goes from one value at birth to another
+// at death.
+func LinearCurve(from, to float64) Curve {
+	return Curve{{Time: 0, Value: from}, {Time: 1, Value: to}}
+}
+
+// Evaluate returns the curve's value at life t.
+func (c Curve) Evaluate(t float64) float64 {
+	if len(c) == 0 {
+		return 1
+	}
+	if t <= c[0].Time {
+		return c[0].Value
+	}
+	for i := 1; i < len(c); i++ {
+		if t <= c[i].Time {
+			a, b := c[i-1], c[i]
+			if b.Time == a.Time {
+				return b.Value
+			}
+			return a.Value + (b.Value-a.Value)*(t-a.Time)/(b.Time-a.Time)
+		}
+	}
+	return c[len(c)-1].Value
+}
+
+// ColorKey is a colour at a point in a particle's life.
+type ColorKey struct {
+	Time  float64
+	Color sdl.Color
+}
+
+// ColorGradient maps a particle's life to a colour by interpolating between
+// keys in time order. An empty gradient is white throughout.
+type ColorGradient []ColorKey
+
+// Evaluate returns the gradient's colour at life t.
+func (g ColorGradient) Evaluate(t float64) sdl.Color {
+	if len(g) == 0 {
+		return sdl.Color{R: 255, G: 255, B: 255, A: 255}
+	}
+	if t <= g[0].Time {
+		return g[0].Color
+	}
+	for i := 1; i < len(g); i++ {
+		if t <= g[i].Time {
+			a, b := g[i-1], g[i]
+			if b.Time == a.Time {
+				return b.Color
+			}
+			f := (t - a.Time) / (b.Time - a.Time)
+			return sdl.Color{
+				R: lerpByte(a.Color.R, b.Color.R, f),
+				G: lerpByte(a.Color.G, b.Color.G, f),
+				B: lerpByte(a.Color.B, b.Color.B, f),
+				A: lerpByte(a.Color.A, b.Color.A, f),
+			}
+		}
+	}
+	return g[len(g)-1].Color
+}
+
+func lerpByte(a, b uint8, t float64) uint8 {
+	return uint8(math.Round(float64(a) + (float64(b)-float64(a))*t))
+}
+
+// -----------------------------------------------------------------------------
+// Emitter
+// -----------------------------------------------------------------------------
+
+// particle is one live particle in an emitter's pool.
+type particle struct {
+	position Vector2D
+	velocity Vector2D
+	age      float64
+	lifetime float64
+	size     float64 // Size at birth, scaled by SizeOverLife
+}
+
+// ParticleEmitter is a component that spawns particles at its position. Set
+// its fields directly; the particle system reads them every frame.
+type ParticleEmitter struct {
+	Position Vector2D
+	Emitting bool    // Whether Rate emission is on; bursts happen regardless
+	Rate     float64 // Particles per second
+	// MaxParticles caps the pool. New particles are dropped while it is full.
+	MaxParticles int
+
+	Lifetime Range // Seconds
+	Speed    Range // Pixels per second
+	// Angle is the direction of travel in degrees from the X axis, turning
+	// clockwise on screen as Y points down. 0 to 360 sprays in every direction.
+	Angle   Range
+	Size    Range    // Width and height in pixels at birth
+	Gravity Vector2D // Pixels per second²
+	Drag    float64  // Fraction of velocity lost per second
+
+	ColorOverLife ColorGradient
+	SizeOverLife  Curve // Multiplies the size at birth
+	AlphaOverLife Curve // Multiplies the colour's alpha
+
+	Blend   sdl.BlendMode
+	Texture *sdl.Texture // Stretched over each particle; nil for squares
+
+	particles   []particle // Pool; the first alive are live
+	alive       int
+	burst       int
+	accumulator float64 // Fractional particles owed by Rate
+}
+
+// NewParticleEmitter creates an emitting emitter with a small white spray
+// that fades out.
+func NewParticleEmitter(position Vector2D, maxParticles int) *ParticleEmitter {
+	return &ParticleEmitter{
+		Position:      position,
+		Emitting:      true,
+		Rate:          100,
+		MaxParticles:  maxParticles,
+		Lifetime:      Range{Min: 0.5, Max: 1},
+		Speed:         Range{Min: 50, Max: 100},
+		Angle:         Range{Min: 0, Max: 360},
+		Size:          Fixed(4),
+		AlphaOverLife: LinearCurve(1, 0),
+		Blend:         sdl.BLENDMODE_BLEND,
+		particles:     make([]particle, 0, maxParticles),
+	}
+}
+
+func (e *ParticleEmitter) GetType() string {
+	return "ParticleEmitter"
+}
+
+// Burst emits count particles at once on the next update.
+func (e *ParticleEmitter) Burst(count int) {
+	e.burst += count
+}
+
+// GetParticleCount returns the number of live particles.
+func (e *ParticleEmitter) GetParticleCount() int {
+	return e.alive
+}
+
+// Clear removes every live particle.
+func (e *ParticleEmitter) Clear() {
+	e.alive = 0
+	e.burst = 0
+	e.accumulator = 0
+}
+
+// update spawns new particles and advances the live ones.
+func (e *ParticleEmitter) update(dt float64, rng *rand.Rand) {
+	count := e.burst
+	e.burst = 0
+	if e.Emitting && e.Rate > 0 {
+		e.accumulator += e.Rate * dt
+		whole := math.Floor(e.accumulator)
+		e.accumulator -= whole
+		count += int(whole)
+	}
+	for i := 0; i < count && e.alive < e.MaxParticles; i++ {
+		e.spawn(rng)
+	}
+
+	damping := 1.0
+	if e.Drag > 0 {
+		damping = math.Max(0, 1-e.Drag*dt)
+	}
+	gravity := e.Gravity.Multiply(dt)
+	for i := 0; i < e.alive; {
+		p := &e.particles[i]
+		p.age += dt
+		if p.age >= p.lifetime {
+			// Swap the last live particle into the gap.
+			e.alive--
+			e.particles[i] = e.particles[e.alive]
+			continue
+		}
+		p.velocity = p.velocity.Add(gravity).Multiply(damping)
+		p.position = p.position.Add(p.velocity.Multiply(dt))
+		i++
+	}
+}
+
+// spawn takes a particle from the pool, growing it up to MaxParticles.
+func (e *ParticleEmitter) spawn(rng *rand.Rand) {
+	if e.alive == len(e.particles) {
+		e.particles = append(e.particles, particle{})
+	}
+	direction := geometry.FromAngle(geometry.Radians(e.Angle.random(rng)))
+	e.particles[e.alive] = particle{
+		position: e.Position,
+		velocity: direction.Multiply(e.Speed.random(rng)),
+		lifetime: math.Max(e.Lifetime.random(rng), 1e-6),
+		size:     e.Size.random(rng),
+	}
+	e.alive++
+}
+
+// -----------------------------------------------------------------------------
+// System
+// -----------------------------------------------------------------------------
+
+// particleLookupSize is the number of samples taken from each over-life curve
+// per frame, so particles look them up instead of interpolating.
+const particleLookupSize = 256
+
+// ParticleSystem updates and draws every entity with a ParticleEmitter.
+// Register it with ECSManager.AddRenderSystem.
+type ParticleSystem struct {
+	rng *rand.Rand
+
+	// Emitters found by the last Update, in entity order, for Render to draw.
+	emitters []*ParticleEmitter
+	order    []Entity
+
+	// Buffers reused between frames.
+	vertices []sdl.Vertex
+	indices  []int32
+	colors   [particleLookupSize]sdl.Color
+	sizes    [particleLookupSize]float64
+}
+
+// NewParticleSystem creates a particle system.
+func NewParticleSystem() *ParticleSystem {
+	return &ParticleSystem{
+		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// SetSeed makes the random spread of particles repeatable.
+func (ps *ParticleSystem) SetSeed(seed int64) {
+	ps.rng.Seed(seed)
+}
+
+func (ps *ParticleSystem) GetRequiredComponents() []reflect.Type {
+	return []reflect.Type{particleEmitterType}
+}
+
+// Update spawns and moves the particles of every emitter.
+func (ps *ParticleSystem) Update(dt float64, entities []Entity, manager *ECSManager) {
+	ps.findEmitters(entities, manager)
+	for _, emitter := range ps.emitters {
+		emitter.update(dt, ps.rng)
+	}
+}
+
+// Render draws each emitter's particles in one batch, in entity order. It
+// draws the emitters found by the last Update, which the ECS runs every frame
+// before rendering.
+func (ps *ParticleSystem) Render(renderer *sdl.Renderer, entities []Entity, manager *ECSManager) {
+	for _, emitter := range ps.emitters {
+		if emitter.alive == 0 {
+			continue
+		}
+		ps.buildLookup(emitter)
+		ps.buildQuads(emitter)
+
+		if emitter.Texture != nil {
+			emitter.Texture.SetBlendMode(emitter.Blend)
+		}
+		renderer.SetDrawBlendMode(emitter.Blend)
+		renderer.RenderGeometry(emitter.Texture, ps.vertices, ps.indices[:6*emitter.alive])
+	}
+}
+
+// particleEmitterType is the component type the system looks up.
+var particleEmitterType = reflect.TypeOf(&ParticleEmitter{})
+
+// findEmitters collects the entities' emitters, sorted by entity so they draw
+// in a stable order, into buffers reused between frames.
+func (ps *ParticleSystem) findEmitters(entities []Entity, manager *ECSManager) {
+	ps.order = append(ps.order[:0], entities...)
+	sort.Slice(ps.order, func(i, j int) bool { return ps.order[i] < ps.order[j] })
+
+	ps.emitters = ps.emitters[:0]
+	for _, entity := range ps.order {
+		if component, ok := manager.GetComponent(entity, particleEmitterType); ok {
+			ps.emitters = append(ps.emitters, component.(*ParticleEmitter))
+		}
+	}
+}
+
+// buildLookup samples the emitter's over-life curves.
+func (ps *ParticleSystem) buildLookup(e *ParticleEmitter) {
+	for i := 0; i < particleLookupSize; i++ {
+		t := float64(i) / (particleLookupSize - 1)
+		color := e.ColorOverLife.Evaluate(t)
+		alpha := math.Max(0, math.Min(e.AlphaOverLife.Evaluate(t), 1))
+		color.A = uint8(math.Round(float64(color.A) * alpha))
+		ps.colors[i] = color
+		ps.sizes[i] = e.SizeOverLife.Evaluate(t)
+	}
+}
+
+// buildQuads fills the vertex and index buffers with a quad per live
+// particle.
+func (ps *ParticleSystem) buildQuads(e *ParticleEmitter) {
+	if cap(ps.vertices) < 4*e.alive {
+		ps.vertices = make([]sdl.Vertex, 4*e.alive)
+	}
+	ps.vertices = ps.vertices[:4*e.alive]
+	for quad := len(ps.indices) / 6; quad < e.alive; quad++ {
+		first := int32(4 * quad)
+		ps.indices = append(ps.indices, first, first+1, first+2, first, first+2, first+3)
+	}
+
+	for i := 0; i < e.alive; i++ {
+		p := &e.particles[i]
+		sample := int(p.age / p.lifetime * (particleLookupSize - 1))
+		color := ps.colors[sample]
+		half := float32(p.size * ps.sizes[sample] / 2)
+		x, y := float32(p.position.X), float32(p.position.Y)
+
+		v := ps.vertices[4*i : 4*i+4]
+		v[0] = sdl.Vertex{Position: sdl.FPoint{X: x - half, Y: y - half}, Color: color, TexCoord: sdl.FPoint{X: 0, Y: 0}}
+		v[1] = sdl.Vertex{Position: sdl.FPoint{X: x + half, Y: y - half}, Color: color, TexCoord: sdl.FPoint{X: 1, Y: 0}}
+		v[2] = sdl.Vertex{Position: sdl.FPoint{X: x + half, Y: y + half}, Color: color, TexCoord: sdl.FPoint{X: 1, Y: 1}}
+		v[3] = sdl.Vertex{Position: sdl.FPoint{X: x - half, Y: y + half}, Color: color, TexCoord: sdl.FPoint{X: 0, Y: 1}}
+	}
+}
@@ -1,7 +1,12 @@
 package body
 
 import (
+	"math"
+
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/fixed"
 	"2d_game_engine/physics/geometry"
+	"2d_game_engine/physics/material"
 )
 
 type Vector2D = geometry.Vector2D
@@ -10,6 +15,13 @@ type Body struct {
 	id                int
 	angle             float64 // Angle in degrees 📐
 	vertices          []Vector2D
+	circleRadius      float64 // Non-zero for bodies created by FromCircle
+	shape             geometry.Shape
+	worldShape        geometry.Shape
+	worldVertices     []Vector2D
+	shapeDirty        bool
+	fixedShape        collision.FixedShape
+	fixedShapeDirty   bool
 	position          Vector2D
 	velocity          Vector2D
 	acceleration      Vector2D
@@ -20,9 +32,10 @@ type Body struct {
 		linear  Vector2D
 		angular float64
 	}
-	speed  float64
-	motion float64
-	render struct {
+	speed        float64
+	motion       float64 // Smoothed motion energy per unit mass
+	sleepCounter float64 // Seconds spent below the sleep threshold
+	render       struct {
 		visible bool
 		opacity float64
 		color   struct{ r, g, b, a uint8 }
@@ -33,54 +46,64 @@ type Body struct {
 			yOffset float64
 		}
 	}
-	restitution     int32
-	friction        float64
-	frictionStatic  float64
-	frictionAir     float64
-	slop            float64
-	totalContacts   int
-	angularSpeed    float64
-	angularVelocity float64
-	isSensor        bool
-	isStatic        bool
-	isSleeping      bool
-	mass            float64
-	inertia         float64
-	density         float64
-	deltaTime       float64
-	area            float64
-	inverseInertia  float64
-	inverseMass     float64
+	restitution        float64
+	friction           float64
+	frictionStatic     float64
+	frictionAir        float64
+	material           string // Name of the material last applied
+	frictionCombine    material.CombineRule
+	restitutionCombine material.CombineRule
+	slop               float64
+	totalContacts      int
+	angularSpeed       float64
+	angularVelocity    float64 // Radians per second
+	isSensor           bool
+	isBullet           bool // Swept against other bodies to prevent tunnelling
+	collisionFilter    collision.Filter
+	isStatic           bool
+	isSleeping         bool
+	mass               float64
+	inertia            float64
+	density            float64
+	deltaTime          float64
+	area               float64
+	inverseInertia     float64
+	inverseMass        float64
 }
 
 func NewBody() *Body {
 	return &Body{
-		id:              0,
-		angle:           0,
-		isSensor:        false,
-		isStatic:        false,
-		isSleeping:      false,
-		area:            0,
-		mass:            0,
-		inertia:         0,
-		deltaTime:       1000 / 60,
-		friction:        0.1,
-		frictionStatic:  0.5,
-		frictionAir:     0.01,
-		restitution:     0,
-		slop:            0.05,
-		totalContacts:   0,
-		angularSpeed:    0,
-		angularVelocity: 0,
-		density:         0.001,
-		vertices:        []Vector2D{},
-		position:        Vector2D{X: 0, Y: 0},
-		velocity:        Vector2D{X: 0, Y: 0},
-		acceleration:    Vector2D{X: 0, Y: 0},
-		force:           Vector2D{X: 0, Y: 0},
-		torque:          0,
-		positionImpulse: Vector2D{X: 0, Y: 0},
-		speed:           0,
+		id:                 0,
+		angle:              0,
+		isSensor:           false,
+		isBullet:           false,
+		collisionFilter:    collision.DefaultFilter,
+		isStatic:           false,
+		isSleeping:         false,
+		area:               0,
+		mass:               0,
+		inertia:            0,
+		deltaTime:          1000 / 60,
+		friction:           material.Default.Friction,
+		frictionStatic:     material.Default.FrictionStatic,
+		frictionAir:        material.Default.FrictionAir,
+		restitution:        material.Default.Restitution,
+		material:           material.Default.Name,
+		frictionCombine:    material.Default.FrictionCombine,
+		restitutionCombine: material.Default.RestitutionCombine,
+		slop:               0.05,
+		totalContacts:      0,
+		angularSpeed:       0,
+		angularVelocity:    0,
+		density:            material.Default.Density,
+		vertices:           []Vector2D{},
+		position:           Vector2D{X: 0, Y: 0},
+		velocity:           Vector2D{X: 0, Y: 0},
+		acceleration:       Vector2D{X: 0, Y: 0},
+		force:              Vector2D{X: 0, Y: 0},
+		torque:             0,
+		positionImpulse:    Vector2D{X: 0, Y: 0},
+		speed:              0,
 		constraintImpulse: struct {
 			linear  Vector2D
 			angular float64
@@ -132,6 +155,10 @@ func (b *Body) GetVertices() []Vector2D {
 	return b.vertices
 }
 
+func (b *Body) GetCircleRadius() float64 {
+	return b.circleRadius
+}
+
 func (b *Body) GetPosition() Vector2D {
 	return b.position
 }
@@ -185,7 +212,19 @@ func (b *Body) GetRender() struct {
 	return b.render
 }
 
-func (b *Body) GetRestitution() int32 {
+func (b *Body) GetVisible() bool {
+	return b.render.visible
+}
+
+func (b *Body) GetOpacity() float64 {
+	return b.render.opacity
+}
+
+func (b *Body) GetColor() (uint8, uint8, uint8, uint8) {
+	return b.render.color.r, b.render.color.g, b.render.color.b, b.render.color.a
+}
+
+func (b *Body) GetRestitution() float64 {
 	return b.restitution
 }
 
@@ -201,6 +240,29 @@ func (b *Body) GetFrictionAir() float64 {
 	return b.frictionAir
 }
 
+// GetMaterial returns the body's current surface and mass properties as a
+// material, named after the material last applied with SetMaterial.
+func (b *Body) GetMaterial() material.Material {
+	return material.Material{
+		Name:               b.material,
+		Density:            b.density,
+		Friction:           b.friction,
+		FrictionStatic:     b.frictionStatic,
+		FrictionAir:        b.frictionAir,
+		Restitution:        b.restitution,
+		FrictionCombine:    b.frictionCombine,
+		RestitutionCombine: b.restitutionCombine,
+	}
+}
+
+func (b *Body) GetFrictionCombine() material.CombineRule {
+	return b.frictionCombine
+}
+
+func (b *Body) GetRestitutionCombine() material.CombineRule {
+	return b.restitutionCombine
+}
+
 func (b *Body) GetSlop() float64 {
 	return b.slop
 }
@@ -221,6 +283,14 @@ func (b *Body) GetIsSensor() bool {
 	return b.isSensor
 }
 
+func (b *Body) GetIsBullet() bool {
+	return b.isBullet
+}
+
+func (b *Body) GetCollisionFilter() collision.Filter {
+	return b.collisionFilter
+}
+
 func (b *Body) GetIsStatic() bool {
 	return b.isStatic
 }
@@ -257,14 +327,31 @@ func (b *Body) SetID(id int) {
 
 func (b *Body) SetAngle(angle float64) {
 	b.angle = angle
+	b.invalidateShape()
 }
 
+// SetVertices replaces the body's outline. The vertices are recentred about
+// their centroid, which becomes the body's position origin, and the area,
+// mass and inertia are recomputed from the current density.
 func (b *Body) SetVertices(vertices []Vector2D) {
-	b.vertices = vertices
+	centroid := geometry.Centroid(vertices)
+	b.vertices = make([]Vector2D, len(vertices))
+	for i, v := range vertices {
+		b.vertices[i] = v.Subtract(centroid)
+	}
+	b.circleRadius = 0
+	b.shape = &geometry.Polygon{Vertices: b.vertices}
+	b.invalidateShape()
+
+	b.area = geometry.Area(b.vertices)
+	b.mass = b.density * b.area
+	b.inertia = geometry.MomentOfInertia(b.vertices, b.mass)
+	b.updateInverseMass()
 }
 
 func (b *Body) SetPosition(position Vector2D) {
 	b.position = position
+	b.invalidateShape()
 }
 
 func (b *Body) SetVelocity(velocity Vector2D) {
@@ -322,7 +409,7 @@ func (b *Body) SetSprite(xScale, yScale, xOffset, yOffset float64) {
 	b.render.sprite.yOffset = yOffset
 }
 
-func (b *Body) SetRestitution(restitution int32) {
+func (b *Body) SetRestitution(restitution float64) {
 	b.restitution = restitution
 }
 
@@ -338,6 +425,31 @@ func (b *Body) SetFrictionAir(frictionAir float64) {
 	b.frictionAir = frictionAir
 }
 
+// SetMaterial applies a material's friction, restitution and combine rules,
+// and its density, recomputing the mass and inertia.
+func (b *Body) SetMaterial(m material.Material) {
+	b.material = m.Name
+	b.friction = m.Friction
+	b.frictionStatic = m.FrictionStatic
+	b.frictionAir = m.FrictionAir
+	b.restitution = m.Restitution
+	b.frictionCombine = m.FrictionCombine
+	b.restitutionCombine = m.RestitutionCombine
+	b.SetDensity(m.Density)
+}
+
+// SetFrictionCombine sets how the body's friction combines with that of the
+// bodies it touches.
+func (b *Body) SetFrictionCombine(rule material.CombineRule) {
+	b.frictionCombine = rule
+}
+
+// SetRestitutionCombine sets how the body's restitution combines with that of
+// the bodies it touches.
+func (b *Body) SetRestitutionCombine(rule material.CombineRule) {
+	b.restitutionCombine = rule
+}
+
 func (b *Body) SetSlop(slop float64) {
 	b.slop = slop
 }
@@ -358,27 +470,59 @@ func (b *Body) SetIsSensor(isSensor bool) {
 	b.isSensor = isSensor
 }
 
+// SetIsBullet enables continuous collision detection for a fast-moving body,
+// so it cannot pass through thin bodies between steps.
+func (b *Body) SetIsBullet(isBullet bool) {
+	b.isBullet = isBullet
+}
+
+func (b *Body) SetCollisionFilter(filter collision.Filter) {
+	b.collisionFilter = filter
+}
+
+// SetIsStatic makes the body immovable. Static bodies have zero inverse mass
+// and inertia; the mass and inertia are kept so they can be made dynamic again.
 func (b *Body) SetIsStatic(isStatic bool) {
 	b.isStatic = isStatic
+	if isStatic {
+		b.velocity = Vector2D{X: 0, Y: 0}
+		b.angularVelocity = 0
+	}
+	b.updateInverseMass()
 }
 
+// SetIsSleeping puts the body to sleep, stopping it, or wakes it up.
 func (b *Body) SetIsSleeping(isSleeping bool) {
 	b.isSleeping = isSleeping
+	b.sleepCounter = 0
+	if isSleeping {
+		b.velocity = Vector2D{X: 0, Y: 0}
+		b.angularVelocity = 0
+		b.speed = 0
+		b.angularSpeed = 0
+		b.motion = 0
+	}
 }
 
+// SetMass changes the body's mass, scaling its inertia to match. A body whose
+// shape gave it no mass, such as a compound of capsules, takes the inertia of
+// its outline at the new mass instead.
 func (b *Body) SetMass(mass float64) {
-	var moment = b.inertia / (b.mass / 6)
-	b.inertia = moment * (mass / 6)
-	b.inverseInertia = 1 / b.inertia
-
+	if b.mass > 0 {
+		b.inertia *= mass / b.mass
+	} else {
+		b.inertia = geometry.MomentOfInertia(b.outline(), mass)
+	}
 	b.mass = mass
-	b.inverseMass = 1 / b.mass
-	b.density = b.mass / b.area
+	if b.area > 0 {
+		b.density = b.mass / b.area
+	}
+	b.updateInverseMass()
 }
 
 func (b *Body) SetInertia(inertia float64) {
 	b.inertia = inertia
-	b.inverseInertia = 1 / b.inertia
+	b.updateInverseMass()
 }
 
 func (b *Body) SetDensity(density float64) {
@@ -393,3 +537,144 @@ func (b *Body) SetDeltaTime(deltaTime float64) {
 func (b *Body) SetArea(area float64) {
 	b.area = area
 }
+
+// updateInverseMass keeps the inverse mass and inertia consistent with the
+// mass, inertia and static flag. Zero mass or inertia means immovable.
+func (b *Body) updateInverseMass() {
+	b.inverseMass = 0
+	b.inverseInertia = 0
+	if b.isStatic {
+		return
+	}
+	if b.mass > 0 {
+		b.inverseMass = 1 / b.mass
+	}
+	if b.inertia > 0 {
+		b.inverseInertia = 1 / b.inertia
+	}
+}
+
+func (b *Body) GetInverseMass() float64 {
+	return b.inverseMass
+}
+
+func (b *Body) GetInverseInertia() float64 {
+	return b.inverseInertia
+}
+
+// Dynamics
+
+// ApplyForce accumulates a force acting at a world-space point. A force that
+// does not pass through the body's position also produces torque. Applying a
+// force wakes a sleeping body.
+func (b *Body) ApplyForce(force Vector2D, point Vector2D) {
+	if b.isSleeping {
+		b.SetIsSleeping(false)
+	}
+	b.force = b.force.Add(force)
+	b.torque += point.Subtract(b.position).Cross(force)
+}
+
+// ApplyTorque accumulates a torque. Like ApplyForce, it wakes a sleeping body.
+func (b *Body) ApplyTorque(torque float64) {
+	if b.isSleeping {
+		b.SetIsSleeping(false)
+	}
+	b.torque += torque
+}
+
+// ClearForces resets the accumulated force and torque, ready for the next step.
+func (b *Body) ClearForces() {
+	b.force = Vector2D{X: 0, Y: 0}
+	b.torque = 0
+}
+
+// ApplyImpulse changes the body's velocity instantly by an impulse applied at
+// a world-space point.
+func (b *Body) ApplyImpulse(impulse Vector2D, point Vector2D) {
+	if b.isStatic {
+		return
+	}
+	b.velocity = b.velocity.Add(impulse.Multiply(b.inverseMass))
+	b.angularVelocity += point.Subtract(b.position).Cross(impulse) * b.inverseInertia
+}
+
+// ApplyConstraintImpulse applies an impulse from a joint. The angular part is
+// the full angular impulse about the body's position. The impulse is also
+// accumulated in constraintImpulse, which the world resets every step.
+func (b *Body) ApplyConstraintImpulse(linear Vector2D, angular float64) {
+	if b.isStatic {
+		return
+	}
+	b.velocity = b.velocity.Add(linear.Multiply(b.inverseMass))
+	b.angularVelocity += angular * b.inverseInertia
+	b.constraintImpulse.linear = b.constraintImpulse.linear.Add(linear)
+	b.constraintImpulse.angular += angular
+}
+
+// Update integrates the body forward by deltaTime seconds using semi-implicit
+// Euler. Gravity is applied as an acceleration so every body falls at the same
+// rate regardless of its mass. Angular velocity is in radians per second.
+func (b *Body) Update(deltaTime float64, gravity Vector2D) {
+	b.IntegrateVelocity(deltaTime, gravity)
+	b.IntegratePosition(deltaTime)
+}
+
+// IntegrateVelocity applies accumulated forces, gravity and air friction to
+// the body's velocity.
+func (b *Body) IntegrateVelocity(deltaTime float64, gravity Vector2D) {
+	if b.isStatic || b.isSleeping {
+		return
+	}
+	if fixed.Enabled {
+		b.integrateVelocityFixed(deltaTime, gravity)
+		return
+	}
+
+	// frictionAir is expressed per 60 Hz tick; scale it to the actual step.
+	damping := math.Pow(1-b.frictionAir, deltaTime*60)
+
+	b.acceleration = b.force.Multiply(b.inverseMass).Add(gravity)
+	b.velocity = b.velocity.Add(b.acceleration.Multiply(deltaTime)).Multiply(damping)
+	b.angularVelocity = (b.angularVelocity + b.torque*b.inverseInertia*deltaTime) * damping
+}
+
+// IntegratePosition moves and rotates the body according to its velocity.
+func (b *Body) IntegratePosition(deltaTime float64) {
+	if b.isStatic || b.isSleeping {
+		return
+	}
+	if fixed.Enabled {
+		b.integratePositionFixed(deltaTime)
+		return
+	}
+
+	b.position = b.position.Add(b.velocity.Multiply(deltaTime))
+	b.angle += geometry.Degrees(b.angularVelocity * deltaTime)
+	b.invalidateShape()
+
+	b.speed = b.velocity.Length()
+	b.angularSpeed = math.Abs(b.angularVelocity)
+}
+
+// UpdateMotion updates the body's smoothed motion energy and returns how many
+// seconds it has stayed below threshold. Motion is the kinetic energy per unit
+// mass (times two), so linear and angular movement are comparable.
+func (b *Body) UpdateMotion(deltaTime, threshold float64) float64 {
+	if fixed.Enabled {
+		b.updateMotionFixed()
+	} else {
+		motion := b.velocity.LengthSaqured() + b.angularVelocity*b.angularVelocity*b.inertia*b.inverseMass
+
+		// Bias towards the lower value so short jitters do not keep bodies awake.
+		const bias = 0.9
+		b.motion = bias*math.Min(b.motion, motion) + (1-bias)*math.Max(b.motion, motion)
+	}
+
+	if b.motion < threshold {
+		b.sleepCounter += deltaTime
+	} else {
+		b.sleepCounter = 0
+	}
+	return b.sleepCounter
+}
@@ -0,0 +1,109 @@
+package body
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics/geometry"
+	"2d_game_engine/physics/material"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+func TestFromRectangleMass(t *testing.T) {
+	b := FromRectangle(Vector2D{X: 5, Y: 5}, 40, 10)
+	density := material.Default.Density
+	if !near(b.GetArea(), 400) {
+		t.Errorf("area = %v, want 400", b.GetArea())
+	}
+	if !near(b.GetMass(), 400*density) {
+		t.Errorf("mass = %v, want %v", b.GetMass(), 400*density)
+	}
+	if want := b.GetMass() * (40*40 + 10*10) / 12; !near(b.GetInertia(), want) {
+		t.Errorf("inertia = %v, want %v", b.GetInertia(), want)
+	}
+	if !near(b.GetInverseMass(), 1/b.GetMass()) || !near(b.GetInverseInertia(), 1/b.GetInertia()) {
+		t.Errorf("inverse mass %v and inertia %v do not match", b.GetInverseMass(), b.GetInverseInertia())
+	}
+}
+
+func TestFromCircleMass(t *testing.T) {
+	b := FromCircle(Vector2D{}, 10)
+	if !near(b.GetArea(), math.Pi*100) {
+		t.Errorf("area = %v, want that of a true circle", b.GetArea())
+	}
+	if want := b.GetMass() * 100 / 2; !near(b.GetInertia(), want) {
+		t.Errorf("inertia = %v, want %v", b.GetInertia(), want)
+	}
+}
+
+func TestSetVerticesRecentres(t *testing.T) {
+	b := NewBody()
+	b.SetVertices([]Vector2D{{X: 10, Y: 0}, {X: 50, Y: 0}, {X: 50, Y: 10}, {X: 10, Y: 10}})
+	for _, v := range b.GetVertices() {
+		if math.Abs(v.X) != 20 || math.Abs(v.Y) != 5 {
+			t.Fatalf("vertices %v are not centred on the centroid", b.GetVertices())
+		}
+	}
+	// Inertia is about the centroid, not the original origin.
+	if want := b.GetMass() * (40*40 + 10*10) / 12; !near(b.GetInertia(), want) {
+		t.Errorf("inertia = %v, want %v", b.GetInertia(), want)
+	}
+}
+
+func TestSetMassAndDensity(t *testing.T) {
+	b := FromRectangle(Vector2D{}, 20, 20)
+	inertia := b.GetInertia()
+	b.SetMass(b.GetMass() * 2)
+	if !near(b.GetInertia(), 2*inertia) {
+		t.Errorf("doubling the mass gave inertia %v, want %v", b.GetInertia(), 2*inertia)
+	}
+	if !near(b.GetDensity(), 2*material.Default.Density) {
+		t.Errorf("density = %v after doubling the mass", b.GetDensity())
+	}
+
+	b.SetDensity(0.01)
+	if !near(b.GetMass(), 4) || b.GetDensity() != 0.01 {
+		t.Errorf("SetDensity(0.01) gave mass %v and density %v, want 4 and 0.01", b.GetMass(), b.GetDensity())
+	}
+	if want := 4.0 * (20*20 + 20*20) / 12; !near(b.GetInertia(), want) {
+		t.Errorf("inertia = %v, want %v", b.GetInertia(), want)
+	}
+}
+
+func TestSetMassOnMasslessBody(t *testing.T) {
+	// Capsules have no area, so the compound starts without mass or inertia.
+	b := FromCompound(Vector2D{}, &geometry.Compound{Children: []geometry.CompoundChild{
+		{Shape: &geometry.Capsule{Length: 40, Radius: 5}},
+	}})
+	if b.GetMass() != 0 || b.GetInertia() != 0 {
+		t.Fatalf("capsule compound has mass %v and inertia %v, want zero", b.GetMass(), b.GetInertia())
+	}
+
+	b.SetMass(3)
+	// About that of the 50x10 box around the capsule.
+	if inertia, box := b.GetInertia(), 3.0*(50*50+10*10)/12; inertia < box*0.8 || inertia > box {
+		t.Errorf("SetMass(3) gave inertia %v, want a little under %v", inertia, box)
+	}
+	if b.GetInverseInertia() == 0 {
+		t.Errorf("body still cannot rotate after SetMass()")
+	}
+}
+
+func TestStaticBodyHasNoInverseMass(t *testing.T) {
+	b := FromRectangle(Vector2D{}, 20, 20)
+	b.SetIsStatic(true)
+	if b.GetInverseMass() != 0 || b.GetInverseInertia() != 0 {
+		t.Errorf("static body has inverse mass %v and inertia %v", b.GetInverseMass(), b.GetInverseInertia())
+	}
+	if b.GetMass() == 0 {
+		t.Errorf("making a body static lost its mass")
+	}
+
+	b.SetIsStatic(false)
+	if !near(b.GetInverseMass(), 1/b.GetMass()) {
+		t.Errorf("inverse mass %v after making the body dynamic again", b.GetInverseMass())
+	}
+}
@@ -0,0 +1,76 @@
+package body
+
+import (
+	"math"
+
+	"2d_game_engine/physics/geometry"
+)
+
+// circleSides is the number of vertices used to outline a circular body.
+const circleSides = 24
+
+// FromPolygon creates a dynamic body from a vertex list. The vertices are
+// recentred about their centroid, which is placed at position, and the mass
+// and inertia are derived from the default density.
+func FromPolygon(position Vector2D, vertices []Vector2D) *Body {
+	b := NewBody()
+	b.SetVertices(vertices)
+	b.SetPosition(position)
+	return b
+}
+
+// FromRectangle creates a rectangular body centred on position.
+func FromRectangle(position Vector2D, width, height float64) *Body {
+	halfWidth := width / 2
+	halfHeight := height / 2
+	return FromPolygon(position, []Vector2D{
+		{X: -halfWidth, Y: -halfHeight},
+		{X: halfWidth, Y: -halfHeight},
+		{X: halfWidth, Y: halfHeight},
+		{X: -halfWidth, Y: halfHeight},
+	})
+}
+
+// FromCircle creates a circular body centred on position. Its outline is a
+// regular polygon but its area and inertia are those of a true circle.
+func FromCircle(position Vector2D, radius float64) *Body {
+	vertices := make([]Vector2D, circleSides)
+	for i := range vertices {
+		theta := 2 * math.Pi * float64(i) / circleSides
+		vertices[i] = Vector2D{X: radius * math.Cos(theta), Y: radius * math.Sin(theta)}
+	}
+
+	b := NewBody()
+	b.SetVertices(vertices)
+	b.SetPosition(position)
+
+	b.circleRadius = radius
+	b.shape = &geometry.Circle{Radius: radius}
+	b.invalidateShape()
+	b.area = math.Pi * radius * radius
+	b.mass = b.density * b.area
+	b.inertia = b.mass * radius * radius / 2
+	b.updateInverseMass()
+	return b
+}
+
+// FromCompound creates a dynamic body from a compound shape. The compound is
+// recentred about its centre of mass, which is placed at position.
+func FromCompound(position Vector2D, compound *geometry.Compound) *Body {
+	b := NewBody()
+	b.SetCompound(compound)
+	b.SetPosition(position)
+	return b
+}
+
+// FromConcavePolygon creates a dynamic body from a simple polygon that may be
+// concave, by decomposing it into convex pieces. Its centre of mass is placed
+// at position. Outlines that are not simple polygons, such as ones that cross
+// themselves, return the error from geometry.Decompose.
+func FromConcavePolygon(position Vector2D, vertices []Vector2D) (*Body, error) {
+	pieces, err := geometry.Decompose(vertices)
+	if err != nil {
+		return nil, err
+	}
+	return FromCompound(position, geometry.NewCompound(pieces)), nil
+}
@@ -0,0 +1,84 @@
+package body
+
+import (
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/fixed"
+	"2d_game_engine/physics/geometry"
+)
+
+//-----------------------------------------------------------------------------
+// Fixed-point integration, used instead of the float64 code when the engine
+// is built with -tags fixedpoint. Each kernel reads the body's state as Q32.32
+// numbers, does all of its arithmetic in the fixed package and writes the
+// results back. Converting between the grid and float64 is exact, so the
+// results are the same on every architecture.
+//-----------------------------------------------------------------------------
+
+// sixty is the tick rate frictionAir is expressed in.
+var sixty = fixed.FromInt(60)
+
+// integrateVelocityFixed is IntegrateVelocity in fixed point.
+func (b *Body) integrateVelocityFixed(deltaTime float64, gravity Vector2D) {
+	dt := fixed.FromFloat(deltaTime)
+	damping := fixed.Pow(fixed.One.Sub(fixed.FromFloat(b.frictionAir)), dt.Mul(sixty))
+
+	inverseMass := fixed.FromFloat(b.inverseMass)
+	acceleration := fixed.VectorFromFloat(b.force).Multiply(inverseMass).Add(fixed.VectorFromFloat(gravity))
+	velocity := fixed.VectorFromFloat(b.velocity).Add(acceleration.Multiply(dt)).Multiply(damping)
+	torque := fixed.FromFloat(b.torque).Mul(fixed.FromFloat(b.inverseInertia)).Mul(dt)
+	angularVelocity := fixed.FromFloat(b.angularVelocity).Add(torque).Mul(damping)
+
+	b.acceleration = acceleration.Float()
+	b.velocity = velocity.Float()
+	b.angularVelocity = angularVelocity.Float()
+}
+
+// integratePositionFixed is IntegratePosition in fixed point.
+func (b *Body) integratePositionFixed(deltaTime float64) {
+	dt := fixed.FromFloat(deltaTime)
+	velocity := fixed.VectorFromFloat(b.velocity)
+	angularVelocity := fixed.FromFloat(b.angularVelocity)
+
+	b.position = fixed.VectorFromFloat(b.position).Add(velocity.Multiply(dt)).Float()
+	b.angle = fixed.FromFloat(b.angle).Add(fixed.Degrees(angularVelocity.Mul(dt))).Float()
+	b.invalidateShape()
+
+	b.speed = velocity.Length().Float()
+	b.angularSpeed = angularVelocity.Abs().Float()
+}
+
+// updateMotionFixed is the motion energy update of UpdateMotion in fixed
+// point.
+func (b *Body) updateMotionFixed() {
+	angularVelocity := fixed.FromFloat(b.angularVelocity)
+	inertiaPerMass := fixed.FromFloat(b.inertia).Mul(fixed.FromFloat(b.inverseMass))
+	motion := fixed.VectorFromFloat(b.velocity).LengthSquared().
+		Add(angularVelocity.Mul(angularVelocity).Mul(inertiaPerMass))
+
+	bias := fixed.FromRatio(9, 10)
+	previous := fixed.FromFloat(b.motion)
+	b.motion = bias.Mul(previous.Min(motion)).Add(fixed.One.Sub(bias).Mul(previous.Max(motion))).Float()
+}
+
+// GetFixedWorldShape returns the body's shape placed at its current position
+// and angle in fixed point, for collision.DetectCollisionFixed. Like
+// GetWorldShape, it is cached until the body moves or rotates, and it is nil
+// until the body has vertices.
+func (b *Body) GetFixedWorldShape() collision.FixedShape {
+	if b.fixedShapeDirty {
+		b.fixedShapeDirty = false
+		b.fixedShape = nil
+		if b.shape != nil {
+			b.fixedShape = collision.PlaceFixed(b.shape, fixed.VectorFromFloat(b.position), fixed.FromFloat(b.angle))
+		}
+	}
+	return b.fixedShape
+}
+
+// FixedBounds is Bounds worked out from the fixed-point world shape.
+func (b *Body) FixedBounds() geometry.AABB {
+	if shape := b.GetFixedWorldShape(); shape != nil {
+		return collision.FixedBounds(shape)
+	}
+	return geometry.AABB{Min: b.position, Max: b.position}
+}
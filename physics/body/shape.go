@@ -0,0 +1,172 @@
+package body
+
+import (
+	"math"
+
+	"2d_game_engine/physics/geometry"
+)
+
+// GetShape returns the body's shape in local space, centred on the body's
+// position origin. It is nil until the body has vertices.
+func (b *Body) GetShape() geometry.Shape {
+	return b.shape
+}
+
+// GetTransform returns the body's placement: its position and angle in degrees.
+func (b *Body) GetTransform() geometry.Transform {
+	return geometry.NewTransform(b.position, b.angle)
+}
+
+// SetTransform moves and rotates the body to a placement.
+func (b *Body) SetTransform(transform geometry.Transform) {
+	b.position = transform.Position
+	b.angle = transform.Rotation
+	b.invalidateShape()
+}
+
+// GetWorldShape returns the body's shape placed at its current position and
+// angle. The result is cached until the body moves or rotates, and must not
+// be modified.
+func (b *Body) GetWorldShape() geometry.Shape {
+	b.updateWorldShape()
+	return b.worldShape
+}
+
+// WorldVertices returns the body's vertices in world space. Like the world
+// shape, they are cached until the body moves or rotates.
+func (b *Body) WorldVertices() []Vector2D {
+	b.updateWorldShape()
+	return b.worldVertices
+}
+
+// Support makes Body a geometry.Shape so it can be passed directly to
+// collision functions such as GJKDetectCollision.
+func (b *Body) Support(direction Vector2D) Vector2D {
+	if shape := b.GetWorldShape(); shape != nil {
+		return shape.Support(direction)
+	}
+	return b.position
+}
+
+// Bounds returns the bounding box of the body's world shape. A body without a
+// shape is a point at its position.
+func (b *Body) Bounds() geometry.AABB {
+	if shape := b.GetWorldShape(); shape != nil {
+		return geometry.ShapeBounds(shape)
+	}
+	return geometry.AABB{Min: b.position, Max: b.position}
+}
+
+// SetCompound makes the body a group of convex children, such as the pieces
+// of geometry.Decompose. The compound is recentred about its centre of mass,
+// which becomes the body's position origin, and the area, mass and inertia
+// are recomputed from the current density. Polygon, triangle, rectangle and
+// circle children contribute mass; the vertices become the convex hull of
+// the children for anything that needs a single outline.
+func (b *Body) SetCompound(compound *geometry.Compound) {
+	// Fold the compound's own placement into its children.
+	placement := geometry.NewTransform(compound.Position, compound.Rotation)
+	local := &geometry.Compound{Children: make([]geometry.CompoundChild, len(compound.Children))}
+	for i, child := range compound.Children {
+		child.Position = placement.Apply(child.Position)
+		child.Rotation += compound.Rotation
+		local.Children[i] = child
+	}
+
+	area := 0.0
+	var weighted Vector2D
+	secondMoment := 0.0 // Per unit density, about the compound's origin
+	outline := make([]Vector2D, 0)
+	for _, child := range local.WorldChildren() {
+		switch s := child.(type) {
+		case *geometry.Circle:
+			childArea := math.Pi * s.Radius * s.Radius
+			area += childArea
+			weighted = weighted.Add(s.Center.Multiply(childArea))
+			secondMoment += childArea * (s.Radius*s.Radius/2 + s.Center.LengthSaqured())
+			for i := 0; i < circleSides; i++ {
+				theta := 2 * math.Pi * float64(i) / circleSides
+				outline = append(outline, s.Center.Add(Vector2D{X: s.Radius * math.Cos(theta), Y: s.Radius * math.Sin(theta)}))
+			}
+		case interface{ WorldVertices() []Vector2D }:
+			vertices := s.WorldVertices()
+			childArea := geometry.Area(vertices)
+			area += childArea
+			weighted = weighted.Add(geometry.Centroid(vertices).Multiply(childArea))
+			secondMoment += geometry.MomentOfInertia(vertices, childArea)
+			outline = append(outline, vertices...)
+		}
+	}
+
+	centroid := Vector2D{}
+	if area > 0 {
+		centroid = weighted.Divide(area)
+	}
+	for i := range local.Children {
+		local.Children[i].Position = local.Children[i].Position.Subtract(centroid)
+	}
+
+	hull := geometry.ConvexHull(outline)
+	b.vertices = make([]Vector2D, len(hull))
+	for i, v := range hull {
+		b.vertices[i] = v.Subtract(centroid)
+	}
+	b.circleRadius = 0
+	b.shape = local
+	b.invalidateShape()
+
+	b.area = area
+	b.mass = b.density * b.area
+	// Move the second moment from the compound's origin to the centroid.
+	b.inertia = b.density * (secondMoment - area*centroid.LengthSaqured())
+	b.updateInverseMass()
+}
+
+// outline returns the body's vertices, or for shapes without an outline of
+// their own, such as capsule children of a compound, the convex hull of the
+// shape's support points around a full turn.
+func (b *Body) outline() []Vector2D {
+	if len(b.vertices) >= 3 || b.shape == nil {
+		return b.vertices
+	}
+	points := make([]Vector2D, circleSides)
+	for i := range points {
+		theta := 2 * math.Pi * float64(i) / circleSides
+		points[i] = b.shape.Support(Vector2D{X: math.Cos(theta), Y: math.Sin(theta)})
+	}
+	return geometry.ConvexHull(points)
+}
+
+// invalidateShape marks the cached world-space shapes as stale.
+func (b *Body) invalidateShape() {
+	b.shapeDirty = true
+	b.fixedShapeDirty = true
+}
+
+func (b *Body) updateWorldShape() {
+	if !b.shapeDirty {
+		return
+	}
+	b.shapeDirty = false
+
+	transform := b.GetTransform()
+	rotation := transform.Matrix()
+
+	if len(b.worldVertices) != len(b.vertices) {
+		b.worldVertices = make([]Vector2D, len(b.vertices))
+	}
+	for i, v := range b.vertices {
+		b.worldVertices[i] = rotation.MulVector(v).Add(b.position)
+	}
+
+	switch shape := b.shape.(type) {
+	case *geometry.Circle:
+		b.worldShape = &geometry.Circle{Center: transform.Apply(shape.Center), Radius: shape.Radius}
+	case *geometry.Polygon:
+		b.worldShape = &geometry.Polygon{Vertices: b.worldVertices}
+	case *geometry.Compound:
+		b.worldShape = geometry.TransformShape(shape, transform)
+	default:
+		b.worldShape = nil
+	}
+}
@@ -0,0 +1,114 @@
+package body
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/geometry"
+)
+
+func nearVector(a, b Vector2D) bool {
+	return math.Abs(a.X-b.X) <= 1e-9 && math.Abs(a.Y-b.Y) <= 1e-9
+}
+
+func TestWorldShapeFollowsBody(t *testing.T) {
+	b := FromRectangle(Vector2D{X: 100, Y: 50}, 40, 20)
+	if got := b.WorldVertices()[0]; !nearVector(got, Vector2D{X: 80, Y: 40}) {
+		t.Errorf("first corner at %v, want {80 40}", got)
+	}
+
+	// The cached shape is refreshed when the body moves or turns.
+	b.SetPosition(Vector2D{X: 0, Y: 0})
+	b.SetAngle(90)
+	if got := b.WorldVertices()[0]; !nearVector(got, Vector2D{X: 10, Y: -20}) {
+		t.Errorf("first corner at %v after turning, want {10 -20}", got)
+	}
+	if got := b.Support(Vector2D{X: 1, Y: 0}); !nearVector(got, Vector2D{X: 10, Y: -20}) && !nearVector(got, Vector2D{X: 10, Y: 20}) {
+		t.Errorf("Support(right) = %v, want a corner at x=10", got)
+	}
+
+	b.SetTransform(geometry.NewTransform(Vector2D{X: 5, Y: 5}, 0))
+	bounds := b.Bounds()
+	if !nearVector(bounds.Min, Vector2D{X: -15, Y: -5}) || !nearVector(bounds.Max, Vector2D{X: 25, Y: 15}) {
+		t.Errorf("Bounds() = %v, want {-15 -5} to {25 15}", bounds)
+	}
+}
+
+func TestCircleWorldShape(t *testing.T) {
+	b := FromCircle(Vector2D{X: 30, Y: 40}, 10)
+	b.SetAngle(33)
+	circle, ok := b.GetWorldShape().(*geometry.Circle)
+	if !ok {
+		t.Fatalf("world shape is %T, want a circle", b.GetWorldShape())
+	}
+	if circle.Center != (Vector2D{X: 30, Y: 40}) || circle.Radius != 10 {
+		t.Errorf("world circle = %+v", circle)
+	}
+	bounds := b.Bounds()
+	if !nearVector(bounds.Min, Vector2D{X: 20, Y: 30}) || !nearVector(bounds.Max, Vector2D{X: 40, Y: 50}) {
+		t.Errorf("Bounds() = %v, want the circle's box", bounds)
+	}
+}
+
+func TestBodyWithoutShape(t *testing.T) {
+	b := NewBody()
+	b.SetPosition(Vector2D{X: 3, Y: 4})
+	if b.GetShape() != nil || b.GetWorldShape() != nil {
+		t.Errorf("a body without vertices has a shape")
+	}
+	if got := b.Support(Vector2D{X: 1, Y: 0}); got != (Vector2D{X: 3, Y: 4}) {
+		t.Errorf("Support() = %v, want the body's position", got)
+	}
+	if bounds := b.Bounds(); bounds.Min != bounds.Max {
+		t.Errorf("Bounds() = %v, want a point", bounds)
+	}
+}
+
+func TestBodiesCollideDirectly(t *testing.T) {
+	a := FromRectangle(Vector2D{X: 0, Y: 0}, 20, 20)
+	b := FromCircle(Vector2D{X: 18, Y: 0}, 10)
+	manifold, ok := collision.DetectCollision(a.GetWorldShape(), b.GetWorldShape())
+	if !ok {
+		t.Fatalf("DetectCollision() found no collision")
+	}
+	if math.Abs(manifold.Depth-2) > 1e-3 || math.Abs(manifold.Normal.X-1) > 1e-3 {
+		t.Errorf("normal %v depth %v, want {1 0} and 2", manifold.Normal, manifold.Depth)
+	}
+
+	// Bodies are shapes themselves.
+	if result := collision.GJKDetectCollision(a, b); !result.Collision {
+		t.Errorf("GJKDetectCollision(a, b) found no collision")
+	}
+}
+
+func TestFromCompound(t *testing.T) {
+	// Two 10x10 squares, one twice as far from the origin: the centre of
+	// mass is halfway between them.
+	compound := geometry.NewCompound([]geometry.Polygon{
+		{Vertices: []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}},
+		{Vertices: []Vector2D{{X: 30, Y: 0}, {X: 40, Y: 0}, {X: 40, Y: 10}, {X: 30, Y: 10}}},
+	})
+	b := FromCompound(Vector2D{X: 100, Y: 100}, compound)
+
+	if !near(b.GetArea(), 200) {
+		t.Errorf("area = %v, want 200", b.GetArea())
+	}
+	// Each square is 15 from the centre: I = 2·(m/2)·((10²+10²)/12 + 15²).
+	if want := b.GetMass() * ((100+100)/12.0 + 225); !near(b.GetInertia(), want) {
+		t.Errorf("inertia = %v, want %v", b.GetInertia(), want)
+	}
+	bounds := b.Bounds()
+	if !nearVector(bounds.Min, Vector2D{X: 80, Y: 95}) || !nearVector(bounds.Max, Vector2D{X: 120, Y: 105}) {
+		t.Errorf("Bounds() = %v, want {80 95} to {120 105}", bounds)
+	}
+	if len(b.GetVertices()) != 4 {
+		t.Errorf("outline has %d vertices, want the hull's 4", len(b.GetVertices()))
+	}
+
+	// The gap between the squares is empty.
+	gap := &geometry.Circle{Center: Vector2D{X: 100, Y: 100}, Radius: 2}
+	if _, ok := collision.DetectCollision(b.GetWorldShape(), gap); ok {
+		t.Errorf("a circle in the gap between the children collided")
+	}
+}
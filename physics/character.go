@@ -0,0 +1,470 @@
+package physics
+
+import (
+	"math"
+
+	"2d_game_engine/physics/body"
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/geometry"
+)
+
+//-----------------------------------------------------------------------------
+// Kinematic character controller. The character is moved directly rather
+// than by forces: each update it shape-casts its motion through the world and
+// slides along whatever it hits, steps up small ledges, sticks to walkable
+// slopes and rides the body it stands on. Overlaps left by other bodies moving
+// into it are pushed out with EPA. Its body is static, so dynamic bodies
+// collide with it but cannot push it.
+//-----------------------------------------------------------------------------
+
+// CharacterInput is the part of core.InputManager the controller reads.
+type CharacterInput interface {
+	IsActionDown(action string) bool
+	IsActionPressed(action string) bool
+}
+
+const (
+	// characterSlideIterations limits how many surfaces one update can
+	// slide along.
+	characterSlideIterations = 4
+	// characterDepenetrationIterations limits how many times overlaps are
+	// pushed out per update.
+	characterDepenetrationIterations = 4
+	// characterGroundProbe is how far below its feet (pixels) the character
+	// looks for ground when it did not land on any this update.
+	characterGroundProbe = 1.0
+	// characterCeilingCosine is how closely a surface normal must face down
+	// to count as a ceiling.
+	characterCeilingCosine = 0.7
+	// characterSkin is the gap (pixels) left between the character and
+	// anything it is pushed out of. Shape casts ignore bodies they start
+	// overlapping, so the character must never be left exactly touching.
+	characterSkin = 0.5
+)
+
+// CharacterController moves a shape through a world like a platformer hero.
+type CharacterController struct {
+	world *World
+	body  *Body
+	shape geometry.Shape // Local to the character's position
+	up    Vector2D
+
+	velocity Vector2D
+
+	// Tuning, in pixels and seconds.
+	moveSpeed     float64
+	acceleration  float64
+	airControl    float64 // Fraction of acceleration available in the air
+	jumpSpeed     float64
+	maxSlopeAngle float64 // Degrees from up
+	stepHeight    float64
+	snapDistance  float64
+	coyoteTime    float64 // Seconds after leaving the ground a jump is allowed
+
+	leftAction, rightAction, jumpAction string
+
+	// State from the last update.
+	grounded     bool
+	onCeiling    bool
+	onWall       bool
+	groundNormal Vector2D
+	wallNormal   Vector2D
+	groundBody   *Body
+	airTime      float64
+	jumping      bool
+}
+
+// NewCharacterController adds a character with the given shape, in local
+// coordinates around its position, to the world.
+func NewCharacterController(world *World, shape geometry.Shape, position Vector2D) *CharacterController {
+	b := body.FromCompound(position, &geometry.Compound{
+		Children: []geometry.CompoundChild{{Shape: shape}},
+	})
+	b.SetIsStatic(true)
+	b.SetFriction(0)
+	world.AddBody(b)
+
+	return &CharacterController{
+		world:         world,
+		body:          b,
+		shape:         shape,
+		up:            Vector2D{X: 0, Y: -1},
+		moveSpeed:     250,
+		acceleration:  2500,
+		airControl:    0.5,
+		jumpSpeed:     550,
+		maxSlopeAngle: 50,
+		stepHeight:    12,
+		snapDistance:  8,
+		coyoteTime:    0.1,
+		leftAction:    "left",
+		rightAction:   "right",
+		jumpAction:    "jump",
+		groundNormal:  Vector2D{X: 0, Y: -1},
+	}
+}
+
+// Getters
+
+func (c *CharacterController) GetBody() *Body {
+	return c.body
+}
+
+func (c *CharacterController) GetPosition() Vector2D {
+	return c.body.GetPosition()
+}
+
+func (c *CharacterController) GetVelocity() Vector2D {
+	return c.velocity
+}
+
+// IsGrounded reports whether the character is standing on a walkable surface.
+func (c *CharacterController) IsGrounded() bool {
+	return c.grounded
+}
+
+// IsOnCeiling reports whether the character hit something above it during
+// the last update.
+func (c *CharacterController) IsOnCeiling() bool {
+	return c.onCeiling
+}
+
+// IsOnWall reports whether the character hit a surface too steep to walk on
+// during the last update.
+func (c *CharacterController) IsOnWall() bool {
+	return c.onWall
+}
+
+// GetGroundNormal returns the normal of the ground, or up in the air.
+func (c *CharacterController) GetGroundNormal() Vector2D {
+	return c.groundNormal
+}
+
+// GetWallNormal returns the normal of the last wall touched.
+func (c *CharacterController) GetWallNormal() Vector2D {
+	return c.wallNormal
+}
+
+// GetGroundBody returns the body the character stands on, or nil.
+func (c *CharacterController) GetGroundBody() *Body {
+	return c.groundBody
+}
+
+func (c *CharacterController) GetMoveSpeed() float64 {
+	return c.moveSpeed
+}
+
+func (c *CharacterController) GetJumpSpeed() float64 {
+	return c.jumpSpeed
+}
+
+func (c *CharacterController) GetMaxSlopeAngle() float64 {
+	return c.maxSlopeAngle
+}
+
+func (c *CharacterController) GetStepHeight() float64 {
+	return c.stepHeight
+}
+
+func (c *CharacterController) GetSnapDistance() float64 {
+	return c.snapDistance
+}
+
+// Setters
+
+// SetPosition teleports the character.
+func (c *CharacterController) SetPosition(position Vector2D) {
+	c.body.SetPosition(position)
+}
+
+func (c *CharacterController) SetVelocity(velocity Vector2D) {
+	c.velocity = velocity
+}
+
+func (c *CharacterController) SetMoveSpeed(speed float64) {
+	c.moveSpeed = speed
+}
+
+// SetAcceleration sets how quickly (pixels per second²) the character reaches
+// its move speed on the ground, and the fraction of that it has in the air.
+func (c *CharacterController) SetAcceleration(acceleration, airControl float64) {
+	c.acceleration = acceleration
+	c.airControl = airControl
+}
+
+func (c *CharacterController) SetJumpSpeed(speed float64) {
+	c.jumpSpeed = speed
+}
+
+// SetMaxSlopeAngle sets the steepest slope, in degrees, the character can
+// stand and walk on.
+func (c *CharacterController) SetMaxSlopeAngle(degrees float64) {
+	c.maxSlopeAngle = degrees
+}
+
+// SetStepHeight sets the tallest ledge the character walks up without
+// jumping.
+func (c *CharacterController) SetStepHeight(height float64) {
+	c.stepHeight = height
+}
+
+// SetSnapDistance sets how far the character is pulled down to stay on the
+// ground when walking down slopes and steps.
+func (c *CharacterController) SetSnapDistance(distance float64) {
+	c.snapDistance = distance
+}
+
+func (c *CharacterController) SetCoyoteTime(seconds float64) {
+	c.coyoteTime = seconds
+}
+
+// SetActions sets the input actions read for moving and jumping. The
+// defaults match core.InputManager's bindings: "left", "right" and "jump".
+func (c *CharacterController) SetActions(left, right, jump string) {
+	c.leftAction, c.rightAction, c.jumpAction = left, right, jump
+}
+
+//-----------------------------------------------------------------------------
+// Update
+//-----------------------------------------------------------------------------
+
+// Update reads input and moves the character by one step. Call it once per
+// physics step, before World.Step, with the same deltaTime. Input may be nil
+// to move the character only by its current velocity and gravity.
+func (c *CharacterController) Update(deltaTime float64, input CharacterInput) {
+	if deltaTime <= 0 {
+		return
+	}
+	if gravity := c.world.GetGravity(); !gravity.IsZero() {
+		c.up = gravity.Normalize().Negate()
+	}
+	wasGrounded := c.grounded
+	if wasGrounded {
+		c.airTime = 0
+	} else {
+		c.airTime += deltaTime
+	}
+
+	c.applyInput(deltaTime, input)
+	c.velocity = c.velocity.Add(c.world.GetGravity().Multiply(deltaTime))
+
+	start := c.body.GetPosition()
+	position := c.depenetrate(start)
+
+	// Ride the body we stood on, then move and slide.
+	displacement := c.velocity.Multiply(deltaTime)
+	if wasGrounded && c.groundBody != nil {
+		displacement = displacement.Add(c.platformVelocity(position).Multiply(deltaTime))
+	}
+	c.grounded, c.onCeiling, c.onWall = false, false, false
+	c.groundBody = nil
+	position = c.moveAndSlide(position, displacement)
+
+	// Stay on the ground over bumps and down slopes, unless jumping.
+	falling := c.velocity.Dot(c.up) <= 0
+	if !c.grounded && falling {
+		snap := characterGroundProbe
+		if wasGrounded {
+			snap = c.snapDistance
+		}
+		position = c.snapToGround(position, snap)
+	}
+	if c.grounded {
+		c.jumping = false
+		c.velocity = c.velocity.Reject(c.up)
+	} else {
+		c.groundNormal = c.up
+	}
+
+	position = c.depenetrate(position)
+	c.body.SetPosition(position)
+	c.body.SetVelocity(position.Subtract(start).Divide(deltaTime))
+}
+
+// applyInput accelerates towards the wanted horizontal speed and jumps.
+func (c *CharacterController) applyInput(deltaTime float64, input CharacterInput) {
+	if input == nil {
+		return
+	}
+
+	direction := 0.0
+	if input.IsActionDown(c.leftAction) {
+		direction--
+	}
+	if input.IsActionDown(c.rightAction) {
+		direction++
+	}
+
+	// Horizontal is across the up direction, positive to the right.
+	right := Vector2D{X: -c.up.Y, Y: c.up.X}
+	speed := c.velocity.Dot(right)
+	acceleration := c.acceleration
+	if !c.grounded {
+		acceleration *= c.airControl
+	}
+	change := direction*c.moveSpeed - speed
+	maxChange := acceleration * deltaTime
+	change = math.Max(-maxChange, math.Min(change, maxChange))
+	c.velocity = c.velocity.Add(right.Multiply(change))
+
+	canJump := (c.grounded || c.airTime <= c.coyoteTime) && !c.jumping
+	if canJump && input.IsActionPressed(c.jumpAction) {
+		c.velocity = c.velocity.Reject(c.up).Add(c.up.Multiply(c.jumpSpeed))
+		if c.groundBody != nil {
+			// Keep the platform's momentum.
+			c.velocity = c.velocity.Add(c.platformVelocity(c.body.GetPosition()))
+		}
+		c.grounded = false
+		c.jumping = true
+	}
+}
+
+// moveAndSlide moves from position by displacement, sliding along surfaces.
+func (c *CharacterController) moveAndSlide(position, displacement Vector2D) Vector2D {
+	for i := 0; i < characterSlideIterations && displacement.LengthSaqured() > 1e-12; i++ {
+		hit, ok := c.cast(position, displacement)
+		if !ok {
+			return position.Add(displacement)
+		}
+		position = position.Add(displacement.Multiply(hit.Fraction))
+		remaining := displacement.Multiply(1 - hit.Fraction)
+
+		switch {
+		case c.isWalkable(hit.Normal):
+			c.land(hit)
+			// Keep the sideways motion along the slope rather than sliding
+			// down it.
+			remaining = c.alongGround(remaining.Reject(c.up), hit.Normal)
+		case hit.Normal.Dot(c.up) <= -characterCeilingCosine:
+			c.onCeiling = true
+			remaining = remaining.Reject(hit.Normal)
+			if c.velocity.Dot(c.up) > 0 {
+				c.velocity = c.velocity.Reject(c.up)
+			}
+		default:
+			if stepped, ok := c.stepUp(position, remaining); ok {
+				// The step used up the rest of the move.
+				return stepped
+			}
+			c.onWall = true
+			c.wallNormal = hit.Normal
+			remaining = remaining.Reject(hit.Normal)
+			if into := c.velocity.Dot(hit.Normal); into < 0 {
+				c.velocity = c.velocity.Subtract(hit.Normal.Multiply(into))
+			}
+		}
+		displacement = remaining
+	}
+	return position
+}
+
+// stepUp tries to climb a ledge: up by the step height, across, then back
+// down onto walkable ground.
+func (c *CharacterController) stepUp(position, remaining Vector2D) (Vector2D, bool) {
+	across := remaining.Reject(c.up)
+	if c.stepHeight <= 0 || (!c.grounded && c.airTime > c.coyoteTime) || across.LengthSaqured() < 1e-12 {
+		return position, false
+	}
+
+	rise := c.up.Multiply(c.stepHeight)
+	if hit, ok := c.cast(position, rise); ok {
+		rise = rise.Multiply(hit.Fraction)
+	}
+	raised := position.Add(rise)
+	// Always advance at least a little so the step's edge is cleared.
+	if across.Length() < 1 {
+		across = across.Normalize()
+	}
+	if _, ok := c.cast(raised, across); ok {
+		return position, false
+	}
+	moved := raised.Add(across)
+
+	drop := rise.Negate()
+	hit, ok := c.cast(moved, drop)
+	if !ok || !c.isWalkable(hit.Normal) {
+		return position, false
+	}
+	c.land(hit)
+	return moved.Add(drop.Multiply(hit.Fraction)), true
+}
+
+// snapToGround pulls the character down by up to distance onto walkable
+// ground.
+func (c *CharacterController) snapToGround(position Vector2D, distance float64) Vector2D {
+	drop := c.up.Multiply(-distance)
+	hit, ok := c.cast(position, drop)
+	if !ok || !c.isWalkable(hit.Normal) {
+		return position
+	}
+	c.land(hit)
+	return position.Add(drop.Multiply(hit.Fraction))
+}
+
+// land records walkable ground under the character.
+func (c *CharacterController) land(hit ShapeCastHit) {
+	c.grounded = true
+	c.groundNormal = hit.Normal
+	c.groundBody = hit.Body
+}
+
+// depenetrate pushes the character out of anything overlapping or touching
+// it, such as a body that moved into it, to the skin distance away.
+func (c *CharacterController) depenetrate(position Vector2D) Vector2D {
+	for i := 0; i < characterDepenetrationIterations; i++ {
+		shape := c.shapeAt(position)
+		moved := false
+		for _, other := range c.world.QueryShape(shape, c.body.GetCollisionFilter()) {
+			if other == c.body || other.GetIsSensor() {
+				continue
+			}
+			manifold, ok := collision.DetectCollision(shape, other.GetWorldShape())
+			if !ok || manifold.Depth < 0 {
+				continue
+			}
+			// The normal points from the character into the other body.
+			position = position.Subtract(manifold.Normal.Multiply(manifold.Depth + characterSkin))
+			shape = c.shapeAt(position)
+			moved = true
+		}
+		if !moved {
+			break
+		}
+	}
+	return position
+}
+
+// cast shape-casts the character from position, ignoring its own body.
+func (c *CharacterController) cast(position, displacement Vector2D) (ShapeCastHit, bool) {
+	return c.world.shapeCast(c.shapeAt(position), displacement, c.body.GetCollisionFilter(), c.body)
+}
+
+func (c *CharacterController) shapeAt(position Vector2D) geometry.Shape {
+	return geometry.TransformShape(c.shape, geometry.NewTransform(position, 0))
+}
+
+// isWalkable reports whether a surface normal is within the max slope of up.
+func (c *CharacterController) isWalkable(normal Vector2D) bool {
+	return normal.Dot(c.up) >= math.Cos(geometry.Radians(c.maxSlopeAngle))
+}
+
+// alongGround turns sideways motion into motion along a slope with the same
+// sideways distance.
+func (c *CharacterController) alongGround(sideways, normal Vector2D) Vector2D {
+	tangent := normal.Perp()
+	across := tangent.Dot(sideways.Normalize())
+	if math.Abs(across) < 1e-9 {
+		return Vector2D{}
+	}
+	return tangent.Multiply(sideways.Length() / across)
+}
+
+// platformVelocity returns the velocity of the ground body at a point.
+func (c *CharacterController) platformVelocity(point Vector2D) Vector2D {
+	ground := c.groundBody
+	if ground == nil {
+		return Vector2D{}
+	}
+	offset := point.Subtract(ground.GetPosition())
+	return ground.GetVelocity().Add(crossScalar(ground.GetAngularVelocity(), offset))
+}
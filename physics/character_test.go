@@ -0,0 +1,182 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics/body"
+	"2d_game_engine/physics/geometry"
+)
+
+// testInput holds actions down, and reports them pressed on the first frame.
+type testInput struct {
+	down    map[string]bool
+	pressed map[string]bool
+}
+
+func newTestInput() *testInput {
+	return &testInput{down: map[string]bool{}, pressed: map[string]bool{}}
+}
+
+func (i *testInput) IsActionDown(action string) bool {
+	return i.down[action]
+}
+
+func (i *testInput) IsActionPressed(action string) bool {
+	return i.pressed[action]
+}
+
+func (i *testInput) press(action string) {
+	i.down[action] = true
+	i.pressed[action] = true
+}
+
+func (i *testInput) nextFrame() {
+	i.pressed = map[string]bool{}
+}
+
+// newCharacterScene returns a world with a floor whose top is at y = 0 and a
+// 20x20 character standing exactly on it at x = 0.
+func newCharacterScene() (*World, *CharacterController) {
+	world := NewWorld()
+	floor := body.FromRectangle(Vector2D{X: 0, Y: 50}, 2000, 100)
+	floor.SetIsStatic(true)
+	world.AddBody(floor)
+
+	box := &geometry.Polygon{Vertices: []Vector2D{{X: -10, Y: -10}, {X: 10, Y: -10}, {X: 10, Y: 10}, {X: -10, Y: 10}}}
+	character := NewCharacterController(world, box, Vector2D{X: 0, Y: -10})
+	return world, character
+}
+
+const characterTestStep = 1.0 / 60
+
+func TestCharacterStandsOnGround(t *testing.T) {
+	world, character := newCharacterScene()
+	for i := 0; i < 40; i++ {
+		character.Update(characterTestStep, nil)
+		world.Step(characterTestStep)
+		if i > 0 && !character.IsGrounded() {
+			t.Fatalf("frame %d: not grounded at %v, velocity %v", i, character.GetPosition(), character.GetVelocity())
+		}
+	}
+	if y := character.GetPosition().Y; math.Abs(y+10) > 1 {
+		t.Errorf("character at y = %v, want resting on the floor at -10", y)
+	}
+	if v := character.GetVelocity(); v.Length() > 1e-6 {
+		t.Errorf("resting velocity = %v, want zero", v)
+	}
+}
+
+func TestCharacterJumps(t *testing.T) {
+	world, character := newCharacterScene()
+	input := newTestInput()
+	for i := 0; i < 5; i++ {
+		character.Update(characterTestStep, input)
+		world.Step(characterTestStep)
+	}
+	if !character.IsGrounded() {
+		t.Fatalf("not grounded before jumping")
+	}
+
+	input.press("jump")
+	character.Update(characterTestStep, input)
+	world.Step(characterTestStep)
+	input.nextFrame()
+	if character.IsGrounded() || character.GetVelocity().Y >= 0 {
+		t.Fatalf("did not jump: grounded %v, velocity %v", character.IsGrounded(), character.GetVelocity())
+	}
+
+	highest := character.GetPosition().Y
+	landed := false
+	for i := 0; i < 120; i++ {
+		character.Update(characterTestStep, input)
+		world.Step(characterTestStep)
+		highest = math.Min(highest, character.GetPosition().Y)
+		if character.IsGrounded() {
+			landed = true
+			break
+		}
+	}
+	// v² / 2g with the default jump speed and gravity is about 154 pixels.
+	if rise := -10 - highest; rise < 140 || rise > 160 {
+		t.Errorf("jump rose %v pixels, want about 154", rise)
+	}
+	if !landed {
+		t.Errorf("never landed, at %v", character.GetPosition())
+	}
+}
+
+func TestCharacterWalksIntoWall(t *testing.T) {
+	world, character := newCharacterScene()
+	wall := body.FromRectangle(Vector2D{X: 60, Y: -100}, 20, 200)
+	wall.SetIsStatic(true)
+	world.AddBody(wall)
+
+	input := newTestInput()
+	input.down["right"] = true
+	for i := 0; i < 60; i++ {
+		character.Update(characterTestStep, input)
+		world.Step(characterTestStep)
+	}
+	if !character.IsOnWall() || !character.IsGrounded() {
+		t.Errorf("on wall %v, grounded %v, want both", character.IsOnWall(), character.IsGrounded())
+	}
+	if x := character.GetPosition().X; x > 40 || x < 38 {
+		t.Errorf("character at x = %v, want stopped against the wall at 40", x)
+	}
+}
+
+func TestCharacterClimbsStep(t *testing.T) {
+	world, character := newCharacterScene()
+	ledge := body.FromRectangle(Vector2D{X: 130, Y: -5}, 200, 10)
+	ledge.SetIsStatic(true)
+	world.AddBody(ledge)
+
+	character.SetMoveSpeed(600)
+	character.SetAcceleration(1e6, 1)
+	input := newTestInput()
+	input.down["right"] = true
+
+	climbed := false
+	for i := 0; i < 15; i++ {
+		before := character.GetPosition()
+		character.Update(characterTestStep, input)
+		world.Step(characterTestStep)
+		after := character.GetPosition()
+
+		// 600 pixels per second is 10 pixels a frame, step or not, plus up to
+		// the pixel a step advances to clear the ledge's edge.
+		if moved := after.X - before.X; moved > 11 {
+			t.Fatalf("frame %d: moved %v pixels across, want about 10", i, moved)
+		}
+		if after.Y < -19 {
+			climbed = true
+		}
+	}
+	if !climbed || !character.IsGrounded() {
+		t.Errorf("did not climb the step: at %v, grounded %v", character.GetPosition(), character.IsGrounded())
+	}
+}
+
+func TestCharacterWalksUpSlope(t *testing.T) {
+	world, character := newCharacterScene()
+	// A 30 degree ramp rising to the right from x = 50.
+	rise := 200 * math.Tan(geometry.Radians(30))
+	vertices := []Vector2D{{X: 50, Y: 0}, {X: 250, Y: -rise}, {X: 250, Y: 0}}
+	ramp := body.FromPolygon(geometry.Centroid(vertices), vertices)
+	ramp.SetIsStatic(true)
+	world.AddBody(ramp)
+
+	input := newTestInput()
+	input.down["right"] = true
+	for i := 0; i < 40; i++ {
+		character.Update(characterTestStep, input)
+		world.Step(characterTestStep)
+	}
+	if !character.IsGrounded() {
+		t.Fatalf("not grounded on the slope at %v", character.GetPosition())
+	}
+	if y := character.GetPosition().Y; y > -40 {
+		t.Errorf("character at %v did not climb the slope", character.GetPosition())
+	}
+}
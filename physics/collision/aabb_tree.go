@@ -0,0 +1,352 @@
+package collision
+
+//-----------------------------------------------------------------------------
+// AABBTree is a dynamic bounding volume hierarchy. Leaves store "fat" boxes
+// enlarged by a margin so small movements do not require restructuring the
+// tree. It handles bodies of very different sizes well.
+//-----------------------------------------------------------------------------
+
+const nullNode = -1
+
+type treeNode struct {
+	bounds AABB
+	tight  AABB // A leaf's proxy bounds, before the margin is added
+	parent int
+	child1 int
+	child2 int
+	height int // Leaves have height 0
+	id     int
+	static bool
+	filter Filter
+}
+
+func (n *treeNode) isLeaf() bool {
+	return n.child1 == nullNode
+}
+
+type AABBTree struct {
+	nodes    []treeNode
+	freeList []int
+	root     int
+	leaves   map[int]int // Proxy ID to node index
+	margin   float64
+}
+
+// NewAABBTree creates an empty tree. margin is how far (pixels) a proxy may
+// move before its leaf has to be reinserted.
+func NewAABBTree(margin float64) *AABBTree {
+	return &AABBTree{
+		nodes:    make([]treeNode, 0),
+		freeList: make([]int, 0),
+		root:     nullNode,
+		leaves:   make(map[int]int),
+		margin:   margin,
+	}
+}
+
+func (t *AABBTree) Insert(id int, bounds AABB, static bool, filter Filter) {
+	leaf := t.allocateNode()
+	t.nodes[leaf].bounds = bounds.Expand(t.margin)
+	t.nodes[leaf].tight = bounds
+	t.nodes[leaf].id = id
+	t.nodes[leaf].static = static
+	t.nodes[leaf].filter = filter
+	t.leaves[id] = leaf
+	t.insertLeaf(leaf)
+}
+
+func (t *AABBTree) Move(id int, bounds AABB) {
+	leaf, ok := t.leaves[id]
+	if !ok {
+		return
+	}
+
+	// The fat box still encloses the proxy: nothing to do.
+	t.nodes[leaf].tight = bounds
+	if t.nodes[leaf].bounds.Contains(bounds) {
+		return
+	}
+
+	t.removeLeaf(leaf)
+	t.nodes[leaf].bounds = bounds.Expand(t.margin)
+	t.insertLeaf(leaf)
+}
+
+func (t *AABBTree) Remove(id int) {
+	leaf, ok := t.leaves[id]
+	if !ok {
+		return
+	}
+	t.removeLeaf(leaf)
+	t.freeNode(leaf)
+	delete(t.leaves, id)
+}
+
+func (t *AABBTree) Pairs() []Pair {
+	pairs := make([]Pair, 0)
+	for id, leaf := range t.leaves {
+		node := t.nodes[leaf]
+		if node.static {
+			continue
+		}
+		t.Query(node.tight, func(other int) bool {
+			// Report pairs of non-static proxies from the lower ID only.
+			otherNode := t.nodes[t.leaves[other]]
+			if other != id && (otherNode.static || other > id) && ShouldCollide(node.filter, otherNode.filter) {
+				pairs = append(pairs, newPair(id, other))
+			}
+			return true
+		})
+	}
+	sortPairs(pairs)
+	return pairs
+}
+
+func (t *AABBTree) Query(bounds AABB, callback func(id int) bool) {
+	if t.root == nullNode {
+		return
+	}
+
+	stack := []int{t.root}
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := &t.nodes[index]
+		if !node.bounds.Overlaps(bounds) {
+			continue
+		}
+		if node.isLeaf() {
+			// The fat box overlapping is not enough for a leaf.
+			if node.tight.Overlaps(bounds) && !callback(node.id) {
+				return
+			}
+		} else {
+			stack = append(stack, node.child1, node.child2)
+		}
+	}
+}
+
+// RayCast walks the tree, skipping subtrees the segment misses or only
+// reaches beyond the current maxFraction.
+func (t *AABBTree) RayCast(from, to Vector2D, callback func(id int, maxFraction float64) float64) {
+	if t.root == nullNode {
+		return
+	}
+
+	maxFraction := 1.0
+	stack := []int{t.root}
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := &t.nodes[index]
+		if !rayHitsBounds(node.bounds, from, to, maxFraction) {
+			continue
+		}
+		if node.isLeaf() {
+			if !rayHitsBounds(node.tight, from, to, maxFraction) {
+				continue
+			}
+			maxFraction = callback(node.id, maxFraction)
+			if maxFraction <= 0 {
+				return
+			}
+		} else {
+			stack = append(stack, node.child1, node.child2)
+		}
+	}
+}
+
+func (t *AABBTree) allocateNode() int {
+	node := treeNode{parent: nullNode, child1: nullNode, child2: nullNode}
+	if len(t.freeList) > 0 {
+		index := t.freeList[len(t.freeList)-1]
+		t.freeList = t.freeList[:len(t.freeList)-1]
+		t.nodes[index] = node
+		return index
+	}
+	t.nodes = append(t.nodes, node)
+	return len(t.nodes) - 1
+}
+
+func (t *AABBTree) freeNode(index int) {
+	t.nodes[index].height = -1
+	t.freeList = append(t.freeList, index)
+}
+
+// insertLeaf finds the cheapest sibling for the leaf using the surface area
+// heuristic and inserts a new parent above it.
+func (t *AABBTree) insertLeaf(leaf int) {
+	if t.root == nullNode {
+		t.root = leaf
+		t.nodes[leaf].parent = nullNode
+		return
+	}
+
+	leafBounds := t.nodes[leaf].bounds
+	index := t.root
+	for !t.nodes[index].isLeaf() {
+		node := t.nodes[index]
+		perimeter := node.bounds.Perimeter()
+		combinedPerimeter := node.bounds.Union(leafBounds).Perimeter()
+
+		// Cost of creating a new parent for this node and the new leaf.
+		cost := 2 * combinedPerimeter
+		// Minimum cost of pushing the leaf further down the tree.
+		inheritanceCost := 2 * (combinedPerimeter - perimeter)
+
+		cost1 := t.descendCost(node.child1, leafBounds) + inheritanceCost
+		cost2 := t.descendCost(node.child2, leafBounds) + inheritanceCost
+
+		if cost < cost1 && cost < cost2 {
+			break
+		}
+		if cost1 < cost2 {
+			index = node.child1
+		} else {
+			index = node.child2
+		}
+	}
+	sibling := index
+
+	// Create a new parent for the sibling and the leaf.
+	oldParent := t.nodes[sibling].parent
+	newParent := t.allocateNode()
+	t.nodes[newParent].parent = oldParent
+	t.nodes[newParent].bounds = leafBounds.Union(t.nodes[sibling].bounds)
+	t.nodes[newParent].height = t.nodes[sibling].height + 1
+	t.nodes[newParent].child1 = sibling
+	t.nodes[newParent].child2 = leaf
+	t.nodes[sibling].parent = newParent
+	t.nodes[leaf].parent = newParent
+
+	if oldParent == nullNode {
+		t.root = newParent
+	} else if t.nodes[oldParent].child1 == sibling {
+		t.nodes[oldParent].child1 = newParent
+	} else {
+		t.nodes[oldParent].child2 = newParent
+	}
+
+	t.refit(t.nodes[leaf].parent)
+}
+
+// descendCost estimates the cost of inserting a box below the given child.
+func (t *AABBTree) descendCost(child int, bounds AABB) float64 {
+	node := t.nodes[child]
+	combined := bounds.Union(node.bounds).Perimeter()
+	if node.isLeaf() {
+		return combined
+	}
+	return combined - node.bounds.Perimeter()
+}
+
+func (t *AABBTree) removeLeaf(leaf int) {
+	if leaf == t.root {
+		t.root = nullNode
+		return
+	}
+
+	parent := t.nodes[leaf].parent
+	grandParent := t.nodes[parent].parent
+	sibling := t.nodes[parent].child1
+	if sibling == leaf {
+		sibling = t.nodes[parent].child2
+	}
+
+	// Replace the parent with the sibling.
+	if grandParent == nullNode {
+		t.root = sibling
+		t.nodes[sibling].parent = nullNode
+		t.freeNode(parent)
+		return
+	}
+
+	if t.nodes[grandParent].child1 == parent {
+		t.nodes[grandParent].child1 = sibling
+	} else {
+		t.nodes[grandParent].child2 = sibling
+	}
+	t.nodes[sibling].parent = grandParent
+	t.freeNode(parent)
+
+	t.refit(grandParent)
+}
+
+// refit walks up from index, rebalancing and recomputing bounds and heights.
+func (t *AABBTree) refit(index int) {
+	for index != nullNode {
+		index = t.balance(index)
+
+		node := &t.nodes[index]
+		child1 := t.nodes[node.child1]
+		child2 := t.nodes[node.child2]
+		node.height = 1 + max(child1.height, child2.height)
+		node.bounds = child1.bounds.Union(child2.bounds)
+
+		index = node.parent
+	}
+}
+
+// balance performs a left or right rotation if node a is imbalanced and
+// returns the index of the node now at a's position.
+func (t *AABBTree) balance(a int) int {
+	nodeA := &t.nodes[a]
+	if nodeA.isLeaf() || nodeA.height < 2 {
+		return a
+	}
+
+	b := nodeA.child1
+	c := nodeA.child2
+	difference := t.nodes[c].height - t.nodes[b].height
+
+	if difference > 1 {
+		return t.rotate(a, c, b)
+	}
+	if difference < -1 {
+		return t.rotate(a, b, c)
+	}
+	return a
+}
+
+// rotate promotes child up (the taller child of a), keeping other under a.
+func (t *AABBTree) rotate(a, up, other int) int {
+	f := t.nodes[up].child1
+	g := t.nodes[up].child2
+
+	// Swap a and up.
+	t.nodes[up].child1 = a
+	t.nodes[up].parent = t.nodes[a].parent
+	t.nodes[a].parent = up
+
+	// a's old parent should point to up.
+	if parent := t.nodes[up].parent; parent != nullNode {
+		if t.nodes[parent].child1 == a {
+			t.nodes[parent].child1 = up
+		} else {
+			t.nodes[parent].child2 = up
+		}
+	} else {
+		t.root = up
+	}
+
+	// The taller grandchild stays under up, the shorter one moves to a.
+	if t.nodes[f].height > t.nodes[g].height {
+		f, g = g, f
+	}
+	t.nodes[up].child2 = g
+	if t.nodes[a].child1 == up {
+		t.nodes[a].child1 = f
+	} else {
+		t.nodes[a].child2 = f
+	}
+	t.nodes[f].parent = a
+
+	t.nodes[a].bounds = t.nodes[other].bounds.Union(t.nodes[f].bounds)
+	t.nodes[a].height = 1 + max(t.nodes[other].height, t.nodes[f].height)
+	t.nodes[up].bounds = t.nodes[a].bounds.Union(t.nodes[g].bounds)
+	t.nodes[up].height = 1 + max(t.nodes[a].height, t.nodes[g].height)
+
+	return up
+}
@@ -0,0 +1,146 @@
+package collision
+
+import (
+	"sort"
+
+	"2d_game_engine/physics/geometry"
+)
+
+type AABB = geometry.AABB
+
+//-----------------------------------------------------------------------------
+// Broad Phase: Candidate Pair Generation
+//-----------------------------------------------------------------------------
+
+// Pair is a candidate collision between two proxies. A is always lower than B.
+type Pair struct {
+	A, B int
+}
+
+func newPair(a, b int) Pair {
+	if a > b {
+		return Pair{A: b, B: a}
+	}
+	return Pair{A: a, B: b}
+}
+
+// BroadPhase tracks the bounds of objects by ID and reports pairs whose bounds
+// overlap, so the narrow phase only runs GJK on likely collisions. Static
+// proxies never generate pairs with each other, and neither do proxies whose
+// filters keep them apart.
+type BroadPhase interface {
+	// Insert adds a proxy with the given bounds and collision filter.
+	Insert(id int, bounds AABB, static bool, filter Filter)
+	// Move updates the bounds of an existing proxy.
+	Move(id int, bounds AABB)
+	// Remove deletes a proxy.
+	Remove(id int)
+	// Pairs returns every overlapping pair involving at least one non-static
+	// proxy whose filters allow a collision, sorted by A then B.
+	Pairs() []Pair
+	// Query calls callback for each proxy whose bounds overlap the given box
+	// until the callback returns false.
+	Query(bounds AABB, callback func(id int) bool)
+	// RayCast calls callback for each proxy whose bounds the segment from ->
+	// to crosses before maxFraction. The callback receives the current
+	// maxFraction and returns the new one, clipping the rest of the search;
+	// returning 0 stops it. Proxies are not visited in any particular order.
+	RayCast(from, to Vector2D, callback func(id int, maxFraction float64) float64)
+}
+
+// sortPairs orders pairs so the narrow phase runs deterministically.
+func sortPairs(pairs []Pair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+}
+
+//-----------------------------------------------------------------------------
+// BruteForce tests every proxy against every other. It is the reference
+// implementation and is fine for a few dozen bodies.
+//-----------------------------------------------------------------------------
+
+type bruteForceProxy struct {
+	id     int
+	bounds AABB
+	static bool
+	filter Filter
+}
+
+type BruteForce struct {
+	proxies []bruteForceProxy
+}
+
+func NewBruteForce() *BruteForce {
+	return &BruteForce{proxies: make([]bruteForceProxy, 0)}
+}
+
+func (bf *BruteForce) Insert(id int, bounds AABB, static bool, filter Filter) {
+	bf.proxies = append(bf.proxies, bruteForceProxy{id: id, bounds: bounds, static: static, filter: filter})
+}
+
+func (bf *BruteForce) Move(id int, bounds AABB) {
+	for i := range bf.proxies {
+		if bf.proxies[i].id == id {
+			bf.proxies[i].bounds = bounds
+			return
+		}
+	}
+}
+
+func (bf *BruteForce) Remove(id int) {
+	for i := range bf.proxies {
+		if bf.proxies[i].id == id {
+			bf.proxies = append(bf.proxies[:i], bf.proxies[i+1:]...)
+			return
+		}
+	}
+}
+
+func (bf *BruteForce) Pairs() []Pair {
+	pairs := make([]Pair, 0)
+	for i := 0; i < len(bf.proxies); i++ {
+		for j := i + 1; j < len(bf.proxies); j++ {
+			a, b := bf.proxies[i], bf.proxies[j]
+			if a.static && b.static || !ShouldCollide(a.filter, b.filter) {
+				continue
+			}
+			if a.bounds.Overlaps(b.bounds) {
+				pairs = append(pairs, newPair(a.id, b.id))
+			}
+		}
+	}
+	sortPairs(pairs)
+	return pairs
+}
+
+func (bf *BruteForce) Query(bounds AABB, callback func(id int) bool) {
+	for _, proxy := range bf.proxies {
+		if proxy.bounds.Overlaps(bounds) && !callback(proxy.id) {
+			return
+		}
+	}
+}
+
+func (bf *BruteForce) RayCast(from, to Vector2D, callback func(id int, maxFraction float64) float64) {
+	maxFraction := 1.0
+	for _, proxy := range bf.proxies {
+		if !rayHitsBounds(proxy.bounds, from, to, maxFraction) {
+			continue
+		}
+		maxFraction = callback(proxy.id, maxFraction)
+		if maxFraction <= 0 {
+			return
+		}
+	}
+}
+
+// rayHitsBounds reports whether the segment from -> to enters bounds before
+// maxFraction.
+func rayHitsBounds(bounds AABB, from, to Vector2D, maxFraction float64) bool {
+	fraction, ok := bounds.RayCast(from, to)
+	return ok && fraction <= maxFraction
+}
@@ -0,0 +1,231 @@
+package collision
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// movingBoxes is a set of boxes wandering around a square area, some static
+// and some filtered, for driving broad phases the same way.
+type movingBoxes struct {
+	bounds     []AABB
+	velocities []Vector2D
+	static     []bool
+	filters    []Filter
+	area       float64
+}
+
+// boxFilters gives a mix of filters: a group that never collides with
+// itself, a category that only collides with the default one, and the rest
+// defaults.
+var boxFilters = []Filter{
+	{Category: 0x0001, Mask: 0xFFFFFFFF, Group: -1},
+	{Category: 0x0002, Mask: 0x0001},
+	DefaultFilter,
+	DefaultFilter,
+}
+
+func newMovingBoxes(count int, area float64, seed int64) *movingBoxes {
+	random := rand.New(rand.NewSource(seed))
+	boxes := &movingBoxes{area: area}
+	for i := 0; i < count; i++ {
+		position := Vector2D{X: random.Float64() * area, Y: random.Float64() * area}
+		size := Vector2D{X: 5 + random.Float64()*30, Y: 5 + random.Float64()*30}
+		boxes.bounds = append(boxes.bounds, AABB{Min: position, Max: position.Add(size)})
+		boxes.velocities = append(boxes.velocities, Vector2D{X: random.Float64()*6 - 3, Y: random.Float64()*6 - 3})
+		boxes.static = append(boxes.static, i%10 == 0)
+		boxes.filters = append(boxes.filters, boxFilters[i%len(boxFilters)])
+	}
+	return boxes
+}
+
+func (m *movingBoxes) insert(broadPhase BroadPhase) {
+	for id, bounds := range m.bounds {
+		broadPhase.Insert(id, bounds, m.static[id], m.filters[id])
+	}
+}
+
+// step moves every dynamic box, bouncing off the edges of the area, and
+// updates the broad phases.
+func (m *movingBoxes) step(broadPhases ...BroadPhase) {
+	for id := range m.bounds {
+		if m.static[id] {
+			continue
+		}
+		bounds := m.bounds[id].Translate(m.velocities[id])
+		if bounds.Min.X < 0 || bounds.Max.X > m.area {
+			m.velocities[id].X = -m.velocities[id].X
+		}
+		if bounds.Min.Y < 0 || bounds.Max.Y > m.area {
+			m.velocities[id].Y = -m.velocities[id].Y
+		}
+		m.bounds[id] = bounds
+		for _, broadPhase := range broadPhases {
+			broadPhase.Move(id, bounds)
+		}
+	}
+}
+
+func queryIDs(broadPhase BroadPhase, bounds AABB) []int {
+	ids := make([]int, 0)
+	broadPhase.Query(bounds, func(id int) bool {
+		ids = append(ids, id)
+		return true
+	})
+	sort.Ints(ids)
+	return ids
+}
+
+func TestBroadPhasesAgree(t *testing.T) {
+	boxes := newMovingBoxes(300, 500, 1)
+	reference := NewBruteForce()
+	others := map[string]BroadPhase{
+		"SpatialHash": NewSpatialHash(40),
+		"AABBTree":    NewAABBTree(4),
+	}
+	boxes.insert(reference)
+	for _, broadPhase := range others {
+		boxes.insert(broadPhase)
+	}
+
+	queries := []AABB{
+		{Min: Vector2D{X: 0, Y: 0}, Max: Vector2D{X: 100, Y: 100}},
+		{Min: Vector2D{X: 240, Y: 10}, Max: Vector2D{X: 260, Y: 490}},
+		{Min: Vector2D{X: 250, Y: 250}, Max: Vector2D{X: 250, Y: 250}},
+		{Min: Vector2D{X: -1000, Y: -1000}, Max: Vector2D{X: 2000, Y: 2000}},
+	}
+
+	for step := 0; step < 50; step++ {
+		if step == 25 {
+			// Removing proxies must also remove their pairs.
+			for id := 0; id < len(boxes.bounds); id += 7 {
+				reference.Remove(id)
+				for _, broadPhase := range others {
+					broadPhase.Remove(id)
+				}
+			}
+		}
+
+		want := reference.Pairs()
+		if step == 0 && len(want) == 0 {
+			t.Fatalf("scene has no overlapping pairs to compare")
+		}
+		for name, broadPhase := range others {
+			if got := broadPhase.Pairs(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("step %d: %s found %d pairs, brute force %d", step, name, len(got), len(want))
+			}
+			for _, query := range queries {
+				if got, want := queryIDs(broadPhase, query), queryIDs(reference, query); !reflect.DeepEqual(got, want) {
+					t.Fatalf("step %d: %s Query(%v) = %v, brute force %v", step, name, query, got, want)
+				}
+			}
+		}
+
+		boxes.step(append([]BroadPhase{reference}, others["SpatialHash"], others["AABBTree"])...)
+	}
+}
+
+func TestBroadPhasesApplyFilters(t *testing.T) {
+	player := Filter{Category: 0x0002, Mask: 0x0001, Group: 0}
+	wall := DefaultFilter
+	ghosts := Filter{Category: 0x0004, Mask: 0xFFFFFFFF, Group: -3}
+	bounds := AABB{Min: Vector2D{X: 0, Y: 0}, Max: Vector2D{X: 10, Y: 10}}
+
+	for name, broadPhase := range map[string]BroadPhase{
+		"BruteForce":  NewBruteForce(),
+		"SpatialHash": NewSpatialHash(40),
+		"AABBTree":    NewAABBTree(4),
+	} {
+		t.Run(name, func(t *testing.T) {
+			broadPhase.Insert(1, bounds, false, player)
+			broadPhase.Insert(2, bounds, false, player)
+			broadPhase.Insert(3, bounds, true, wall)
+			broadPhase.Insert(4, bounds, false, ghosts)
+			broadPhase.Insert(5, bounds, false, ghosts)
+
+			// Players hit walls but not each other or ghosts, and ghosts
+			// share a negative group.
+			want := []Pair{{A: 1, B: 3}, {A: 2, B: 3}, {A: 3, B: 4}, {A: 3, B: 5}}
+			if got := broadPhase.Pairs(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Pairs() = %v, want %v", got, want)
+			}
+			if got := queryIDs(broadPhase, bounds); len(got) != 5 {
+				t.Errorf("Query() = %v, want every proxy regardless of filter", got)
+			}
+		})
+	}
+}
+
+func TestNewSpatialHashRejectsBadCellSize(t *testing.T) {
+	for _, cellSize := range []float64{0, -10, math.NaN(), math.Inf(1)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewSpatialHash(%v) did not panic", cellSize)
+				}
+			}()
+			NewSpatialHash(cellSize)
+		}()
+	}
+}
+
+func TestSpatialHashHugeBounds(t *testing.T) {
+	hash := NewSpatialHash(1)
+	huge := AABB{Min: Vector2D{X: -1e12, Y: -10}, Max: Vector2D{X: 1e12, Y: 10}}
+	infinite := AABB{Min: Vector2D{X: math.Inf(-1), Y: 0}, Max: Vector2D{X: math.Inf(1), Y: 1}}
+	small := AABB{Min: Vector2D{X: 0, Y: 0}, Max: Vector2D{X: 2, Y: 2}}
+	hash.Insert(1, huge, true, DefaultFilter)
+	hash.Insert(2, infinite, false, DefaultFilter)
+	hash.Insert(3, small, false, DefaultFilter)
+
+	want := []Pair{{A: 1, B: 2}, {A: 1, B: 3}, {A: 2, B: 3}}
+	if got := hash.Pairs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Pairs() = %v, want %v", got, want)
+	}
+	if got := queryIDs(hash, AABB{Min: Vector2D{X: 1, Y: 1}, Max: Vector2D{X: 1, Y: 1}}); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Query around the small box = %v, want all three", got)
+	}
+	if got := queryIDs(hash, infinite); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Query with infinite bounds = %v, want all three", got)
+	}
+
+	// Shrinking a proxy moves it back into the grid.
+	hash.Move(2, AABB{Min: Vector2D{X: 100, Y: 100}, Max: Vector2D{X: 101, Y: 101}})
+	if got := hash.Pairs(); !reflect.DeepEqual(got, []Pair{{A: 1, B: 3}}) {
+		t.Errorf("Pairs() after moving = %v, want [{1 3}]", got)
+	}
+}
+
+//-----------------------------------------------------------------------------
+// Benchmarks: a step of moving every box and collecting pairs.
+//-----------------------------------------------------------------------------
+
+const (
+	benchmarkBoxes = 3000
+	benchmarkArea  = 3000
+)
+
+func benchmarkBroadPhase(b *testing.B, broadPhase BroadPhase) {
+	boxes := newMovingBoxes(benchmarkBoxes, benchmarkArea, 1)
+	boxes.insert(broadPhase)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		boxes.step(broadPhase)
+		broadPhase.Pairs()
+	}
+}
+
+func BenchmarkBruteForce(b *testing.B) {
+	benchmarkBroadPhase(b, NewBruteForce())
+}
+
+func BenchmarkSpatialHash(b *testing.B) {
+	benchmarkBroadPhase(b, NewSpatialHash(40))
+}
+
+func BenchmarkAABBTree(b *testing.B) {
+	benchmarkBroadPhase(b, NewAABBTree(4))
+}
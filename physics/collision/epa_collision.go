@@ -15,12 +15,27 @@ type Edge struct {
 	Index    int
 }
 
-// EPA finds the penetration vector and depth.
+// EPA finds the penetration vector and depth. The returned normal points from
+// shapeA towards shapeB.
 func EPA(simplex []Vector2D, shapeA, shapeB Shape) (Vector2D, float64) {
 	const maxIterations = 50
+	if len(simplex) < 3 {
+		return Vector2D{}, 0.0
+	}
+
+	// Work on a copy wound counter-clockwise so edge normals point outwards.
+	polytope := make([]Vector2D, len(simplex))
+	copy(polytope, simplex)
+	if signedArea(polytope) < 0 {
+		for i, j := 0, len(polytope)-1; i < j; i, j = i+1, j-1 {
+			polytope[i], polytope[j] = polytope[j], polytope[i]
+		}
+	}
+
+	var closestEdge Edge
 	for i := 0; i < maxIterations; i++ {
 		// Find the edge closest to the origin.
-		closestEdge := getClosestEdge(simplex)
+		closestEdge = getClosestEdge(polytope)
 		normal := closestEdge.Normal
 		distance := closestEdge.Distance
 
@@ -34,15 +49,15 @@ func EPA(simplex []Vector2D, shapeA, shapeB Shape) (Vector2D, float64) {
 			return normal, distance
 		} else {
 			// Otherwise, add the new point to the simplex to refine the shape.
-			simplex = insertPoint(simplex, support, closestEdge.Index+1)
+			polytope = insertPoint(polytope, support, closestEdge.Index+1)
 		}
 	}
 
-	// Fallback in case the algorithm doesn't converge.
-	return Vector2D{}, 0.0
+	// Fall back to the best estimate if the algorithm doesn't converge.
+	return closestEdge.Normal, closestEdge.Distance
 }
 
-// getClosestEdge finds the edge of the simplex closest to the origin.
+// getClosestEdge finds the edge of a counter-clockwise polytope closest to the origin.
 func getClosestEdge(simplex []Vector2D) Edge {
 	closest := Edge{
 		Distance: math.Inf(1),
@@ -55,22 +70,13 @@ func getClosestEdge(simplex []Vector2D) Edge {
 		p2 := simplex[(i+1)%len(simplex)]
 
 		edge := p2.Subtract(p1)
-		// Vector from p1 to the origin.
-		p1ToOrigin := p1.Multiply(-1)
-
-		// The vector to the closest point on the line segment from the origin.
-		// Projection of p1ToOrigin onto the edge vector.
-		t := p1ToOrigin.Dot(edge) / edge.Dot(edge)
-
-		if t < 0 {
-			t = 0
-		} else if t > 1 {
-			t = 1
+		if edge.LengthSaqured() == 0 {
+			continue
 		}
 
-		closestPoint := p1.Add(edge.Multiply(t))
-		normal := closestPoint.Normalize()
-		distance := closestPoint.Length()
+		// Outward normal of a counter-clockwise edge.
+		normal := Vector2D{X: edge.Y, Y: -edge.X}.Normalize()
+		distance := normal.Dot(p1)
 
 		if distance < closest.Distance {
 			closest.Distance = distance
@@ -82,6 +88,16 @@ func getClosestEdge(simplex []Vector2D) Edge {
 	return closest
 }
 
+// signedArea returns twice the signed area of a polygon; positive when the
+// vertices are wound counter-clockwise.
+func signedArea(points []Vector2D) float64 {
+	area := 0.0
+	for i := 0; i < len(points); i++ {
+		area += points[i].Cross(points[(i+1)%len(points)])
+	}
+	return area
+}
+
 // insertPoint inserts a new point into the simplex at a specified index.
 func insertPoint(simplex []Vector2D, point Vector2D, index int) []Vector2D {
 	newSimplex := make([]Vector2D, len(simplex)+1)
@@ -89,4 +105,4 @@ func insertPoint(simplex []Vector2D, point Vector2D, index int) []Vector2D {
 	newSimplex[index] = point
 	copy(newSimplex[index+1:], simplex[index:])
 	return newSimplex
-}
\ No newline at end of file
+}
@@ -0,0 +1,28 @@
+package collision
+
+//-----------------------------------------------------------------------------
+// Collision Filtering
+//-----------------------------------------------------------------------------
+
+// Filter decides which objects may collide, following Box2D's rules.
+//
+// Category is the bit (or bits) the object belongs to and Mask the categories
+// it collides with. Group overrides both: objects sharing a positive group
+// always collide, objects sharing a negative group never collide, and group
+// zero means no group.
+type Filter struct {
+	Category uint32
+	Mask     uint32
+	Group    int
+}
+
+// DefaultFilter belongs to the first category and collides with everything.
+var DefaultFilter = Filter{Category: 0x0001, Mask: 0xFFFFFFFF, Group: 0}
+
+// ShouldCollide reports whether two filters allow a collision.
+func ShouldCollide(filterA, filterB Filter) bool {
+	if filterA.Group == filterB.Group && filterA.Group != 0 {
+		return filterA.Group > 0
+	}
+	return filterA.Mask&filterB.Category != 0 && filterB.Mask&filterA.Category != 0
+}
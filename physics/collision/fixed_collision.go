@@ -0,0 +1,495 @@
+package collision
+
+import (
+	"fmt"
+
+	"2d_game_engine/physics/fixed"
+	"2d_game_engine/physics/geometry"
+)
+
+//-----------------------------------------------------------------------------
+// Fixed-point narrow phase: the GJK, EPA and clipping pipeline of
+// DetectCollision run in Q32.32 arithmetic, so it finds the same contacts on
+// every architecture. The world uses it when built with -tags fixedpoint.
+// Shapes are placed in world space with fixed-point rotations, and every
+// direction passed to a support function is a unit vector, so intermediate
+// values stay far from the fixed-point range for worlds up to a few tens of
+// thousands of pixels across.
+//-----------------------------------------------------------------------------
+
+const (
+	// fixedEPATolerance is how close (pixels) a support point must be to the
+	// closest polytope edge for EPA to stop.
+	fixedEPATolerance = 1e-6
+)
+
+// FixedShape is a convex shape in world space with fixed-point coordinates.
+// Directions passed to Support do not need to be normalized.
+type FixedShape interface {
+	Support(direction fixed.Vector) fixed.Vector
+}
+
+// FixedPolygon is a convex polygon, or a segment when it has two vertices.
+type FixedPolygon struct {
+	Vertices []fixed.Vector
+}
+
+// Support for a FixedPolygon is its vertex furthest along the direction.
+func (p *FixedPolygon) Support(direction fixed.Vector) fixed.Vector {
+	support := p.Vertices[0]
+	maxDot := support.Dot(direction)
+	for _, v := range p.Vertices[1:] {
+		if dot := v.Dot(direction); dot > maxDot {
+			maxDot = dot
+			support = v
+		}
+	}
+	return support
+}
+
+// FixedCircle is a circle.
+type FixedCircle struct {
+	Center fixed.Vector
+	Radius fixed.Fixed
+}
+
+// Support for a FixedCircle is the centre pushed out by the radius.
+func (c *FixedCircle) Support(direction fixed.Vector) fixed.Vector {
+	return c.Center.Add(direction.Normalize().Multiply(c.Radius))
+}
+
+// FixedCapsule is the segment from Start to End rounded by a radius.
+type FixedCapsule struct {
+	Start, End fixed.Vector
+	Radius     fixed.Fixed
+}
+
+// Support for a FixedCapsule is the support of its core segment pushed out by
+// the radius.
+func (c *FixedCapsule) Support(direction fixed.Vector) fixed.Vector {
+	core := c.Start
+	if c.End.Dot(direction) > c.Start.Dot(direction) {
+		core = c.End
+	}
+	return core.Add(direction.Normalize().Multiply(c.Radius))
+}
+
+// FixedEllipse has radii along its own X and Y axes, which are turned by
+// Rotation.
+type FixedEllipse struct {
+	Center           fixed.Vector
+	RadiusX, RadiusY fixed.Fixed
+	Rotation         fixed.Rotation
+}
+
+// Support for a FixedEllipse is (a²dx, b²dy) / sqrt(a²dx² + b²dy²) in the
+// ellipse's own frame, like geometry.Ellipse.
+func (e *FixedEllipse) Support(direction fixed.Vector) fixed.Vector {
+	local := e.Rotation.ApplyInverse(direction.Normalize())
+	x := e.RadiusX.Mul(e.RadiusX).Mul(local.X)
+	y := e.RadiusY.Mul(e.RadiusY).Mul(local.Y)
+	length := x.Mul(local.X).Add(y.Mul(local.Y)).Sqrt()
+	if length == 0 {
+		return e.Center
+	}
+	return e.Center.Add(e.Rotation.Apply(fixed.Vector{X: x.Div(length), Y: y.Div(length)}))
+}
+
+// FixedCompound is a group of convex children already placed in world space.
+type FixedCompound struct {
+	Children []FixedShape
+}
+
+// Support for a FixedCompound is the furthest support point of any child.
+func (c *FixedCompound) Support(direction fixed.Vector) fixed.Vector {
+	var support fixed.Vector
+	for i, child := range c.Children {
+		point := child.Support(direction)
+		if i == 0 || point.Dot(direction) > support.Dot(direction) {
+			support = point
+		}
+	}
+	return support
+}
+
+// fixedPlacement is a position and an angle in degrees, with the rotation
+// for the angle worked out once.
+type fixedPlacement struct {
+	position fixed.Vector
+	angle    fixed.Fixed
+	rotation fixed.Rotation
+}
+
+func newFixedPlacement(position fixed.Vector, angle fixed.Fixed) fixedPlacement {
+	return fixedPlacement{position: position, angle: angle, rotation: fixed.NewRotation(angle)}
+}
+
+// apply places a local point in world space.
+func (p fixedPlacement) apply(v Vector2D) fixed.Vector {
+	return p.position.Add(p.rotation.Apply(fixed.VectorFromFloat(v)))
+}
+
+// then returns the placement of a shape placed by position and rotation
+// within this one.
+func (p fixedPlacement) then(position Vector2D, rotation float64) fixedPlacement {
+	if position == (Vector2D{}) && rotation == 0 {
+		return p
+	}
+	return newFixedPlacement(p.apply(position), p.angle.Add(fixed.FromFloat(rotation)))
+}
+
+func (p fixedPlacement) applyAll(vertices []Vector2D) []fixed.Vector {
+	placed := make([]fixed.Vector, len(vertices))
+	for i, v := range vertices {
+		placed[i] = p.apply(v)
+	}
+	return placed
+}
+
+// PlaceFixed places a shape given in local space at a position and an angle
+// in degrees, converting it to fixed point. It accepts the shapes of the
+// geometry package, including compounds and transformed shapes, and panics
+// on any other type.
+func PlaceFixed(shape Shape, position fixed.Vector, angle fixed.Fixed) FixedShape {
+	return placeFixed(shape, newFixedPlacement(position, angle))
+}
+
+func placeFixed(shape Shape, placement fixedPlacement) FixedShape {
+	switch s := shape.(type) {
+	case *geometry.Circle:
+		return &FixedCircle{Center: placement.apply(s.Center), Radius: fixed.FromFloat(s.Radius)}
+	case *geometry.Polygon:
+		return &FixedPolygon{Vertices: placement.then(s.Position, s.Rotation).applyAll(s.Vertices)}
+	case *geometry.Triangle:
+		return &FixedPolygon{Vertices: placement.then(s.Position, s.Rotation).applyAll(s.Vertices)}
+	case *geometry.Rectangle:
+		return &FixedPolygon{Vertices: placement.then(s.Position, s.Rotation).applyAll(s.Vertices())}
+	case *geometry.Segment:
+		return &FixedPolygon{Vertices: placement.applyAll([]Vector2D{s.Start, s.End})}
+	case *geometry.Capsule:
+		local := placement.then(s.Position, s.Rotation)
+		return &FixedCapsule{
+			Start:  local.apply(Vector2D{X: -s.Length / 2}),
+			End:    local.apply(Vector2D{X: s.Length / 2}),
+			Radius: fixed.FromFloat(s.Radius),
+		}
+	case *geometry.Ellipse:
+		local := placement.then(s.Position, s.Rotation)
+		return &FixedEllipse{
+			Center:   local.position,
+			RadiusX:  fixed.FromFloat(s.RadiusX),
+			RadiusY:  fixed.FromFloat(s.RadiusY),
+			Rotation: local.rotation,
+		}
+	case *geometry.TransformedShape:
+		return placeFixed(s.Shape, placement.then(s.Position, s.Rotation))
+	case *geometry.Compound:
+		local := placement.then(s.Position, s.Rotation)
+		compound := &FixedCompound{Children: make([]FixedShape, len(s.Children))}
+		for i, child := range s.Children {
+			compound.Children[i] = placeFixed(child.Shape, local.then(child.Position, child.Rotation))
+		}
+		return compound
+	}
+	panic(fmt.Sprintf("collision: cannot place %T in fixed point", shape))
+}
+
+// FixedBounds returns the bounding box of a fixed-point shape.
+func FixedBounds(shape FixedShape) geometry.AABB {
+	right := shape.Support(fixed.Vector{X: fixed.One}).X
+	left := shape.Support(fixed.Vector{X: -fixed.One}).X
+	bottom := shape.Support(fixed.Vector{Y: fixed.One}).Y
+	top := shape.Support(fixed.Vector{Y: -fixed.One}).Y
+	return geometry.AABB{
+		Min: Vector2D{X: left.Float(), Y: top.Float()},
+		Max: Vector2D{X: right.Float(), Y: bottom.Float()},
+	}
+}
+
+// DetectCollisionFixed is DetectCollision for fixed-point shapes. The
+// manifold is converted to float64 exactly. Unlike DetectCollision, shapes
+// that only touch do not collide.
+func DetectCollisionFixed(shapeA, shapeB FixedShape) (Manifold, bool) {
+	if compound, ok := shapeA.(*FixedCompound); ok {
+		return deepestFixedManifold(compound.Children, func(child FixedShape) (Manifold, bool) {
+			return DetectCollisionFixed(child, shapeB)
+		})
+	}
+	if compound, ok := shapeB.(*FixedCompound); ok {
+		return deepestFixedManifold(compound.Children, func(child FixedShape) (Manifold, bool) {
+			return DetectCollisionFixed(shapeA, child)
+		})
+	}
+
+	simplex, ok := gjkFixed(shapeA, shapeB)
+	if !ok {
+		return Manifold{}, false
+	}
+	normal, depth := epaFixed(simplex, shapeA, shapeB)
+	if depth <= 0 || normal == (fixed.Vector{}) {
+		return Manifold{}, false
+	}
+	return buildFixedManifold(shapeA, shapeB, normal, depth), true
+}
+
+// deepestFixedManifold collides each child and keeps the deepest manifold.
+func deepestFixedManifold(children []FixedShape, detect func(child FixedShape) (Manifold, bool)) (Manifold, bool) {
+	var deepest Manifold
+	found := false
+	for _, child := range children {
+		manifold, ok := detect(child)
+		if ok && (!found || manifold.Depth > deepest.Depth) {
+			deepest = manifold
+			found = true
+		}
+	}
+	return deepest, found
+}
+
+// supportFixed returns the support point of the Minkowski difference A - B.
+func supportFixed(shapeA, shapeB FixedShape, direction fixed.Vector) fixed.Vector {
+	return shapeA.Support(direction).Subtract(shapeB.Support(direction.Negate()))
+}
+
+// gjkFixed reports whether two shapes overlap, and if they do returns a
+// triangle of their Minkowski difference that encloses the origin. It only
+// answers the overlap question, so unlike GJKDetectCollision it has no
+// distance mode.
+func gjkFixed(shapeA, shapeB FixedShape) ([]fixed.Vector, bool) {
+	simplex := make([]fixed.Vector, 0, 3)
+	simplex = append(simplex, supportFixed(shapeA, shapeB, fixed.Vector{X: fixed.One}))
+	direction := simplex[0].Negate()
+
+	for i := 0; i < gjkMaxIterations; i++ {
+		direction = direction.Normalize()
+		if direction == (fixed.Vector{}) {
+			// The origin lies on the simplex: the shapes only touch.
+			return nil, false
+		}
+		point := supportFixed(shapeA, shapeB, direction)
+		if point.Dot(direction) <= 0 {
+			// The difference does not reach past the origin.
+			return nil, false
+		}
+		simplex = append(simplex, point)
+
+		if len(simplex) == 3 {
+			// The newest point is a; keep the edge facing the origin or stop
+			// if the origin is inside the triangle.
+			a, b, c := simplex[2], simplex[1], simplex[0]
+			ab, ac, ao := b.Subtract(a), c.Subtract(a), a.Negate()
+			abPerp := ab.Perp()
+			if abPerp.Dot(ac) == 0 {
+				// Degenerate (collinear) triangle: keep the newest edge.
+				simplex = append(simplex[:0], b, a)
+			} else {
+				if abPerp.Dot(ac) > 0 {
+					abPerp = abPerp.Negate()
+				}
+				acPerp := ac.Perp()
+				if acPerp.Dot(ab) > 0 {
+					acPerp = acPerp.Negate()
+				}
+				switch {
+				case abPerp.Dot(ao) > 0:
+					simplex = append(simplex[:0], b, a)
+					direction = abPerp
+					continue
+				case acPerp.Dot(ao) > 0:
+					simplex = append(simplex[:0], c, a)
+					direction = acPerp
+					continue
+				}
+				return simplex, true
+			}
+		}
+
+		// Search from the edge towards the origin.
+		a, b := simplex[1], simplex[0]
+		direction = b.Subtract(a).Perp()
+		if direction.Dot(a.Negate()) < 0 {
+			direction = direction.Negate()
+		}
+	}
+	return nil, false
+}
+
+// epaFixed is EPA in fixed point. The returned normal points from shapeA
+// towards shapeB.
+func epaFixed(simplex []fixed.Vector, shapeA, shapeB FixedShape) (fixed.Vector, fixed.Fixed) {
+	const maxIterations = 50
+	tolerance := fixed.FromFloat(fixedEPATolerance)
+
+	// Work on a copy wound counter-clockwise so edge normals point outwards.
+	polytope := make([]fixed.Vector, len(simplex))
+	copy(polytope, simplex)
+	area := fixed.Fixed(0)
+	for i := range polytope {
+		area = area.Add(polytope[i].Cross(polytope[(i+1)%len(polytope)]))
+	}
+	if area < 0 {
+		for i, j := 0, len(polytope)-1; i < j; i, j = i+1, j-1 {
+			polytope[i], polytope[j] = polytope[j], polytope[i]
+		}
+	}
+
+	var normal fixed.Vector
+	var distance fixed.Fixed
+	for i := 0; i < maxIterations; i++ {
+		// Find the edge closest to the origin.
+		index := -1
+		for j := range polytope {
+			edge := polytope[(j+1)%len(polytope)].Subtract(polytope[j])
+			edgeNormal := fixed.Vector{X: edge.Y, Y: edge.X.Neg()}.Normalize()
+			if edgeNormal == (fixed.Vector{}) {
+				continue
+			}
+			if edgeDistance := edgeNormal.Dot(polytope[j]); index < 0 || edgeDistance < distance {
+				index, normal, distance = j, edgeNormal, edgeDistance
+			}
+		}
+		if index < 0 {
+			return fixed.Vector{}, 0
+		}
+
+		support := supportFixed(shapeA, shapeB, normal)
+		if support.Dot(normal).Sub(distance).Abs() < tolerance {
+			return normal, distance
+		}
+		polytope = append(polytope[:index+1], append([]fixed.Vector{support}, polytope[index+1:]...)...)
+	}
+	return normal, distance
+}
+
+// buildFixedManifold is BuildManifold for fixed-point shapes.
+func buildFixedManifold(shapeA, shapeB FixedShape, normal fixed.Vector, depth fixed.Fixed) Manifold {
+	manifold := Manifold{Normal: normal.Float(), Depth: depth.Float()}
+	contact := func(point fixed.Vector) []ContactPoint {
+		return []ContactPoint{{Point: point.Float(), Depth: depth.Float()}}
+	}
+
+	// Circles touch at the point on their surface deepest inside the other shape.
+	if circle, ok := shapeB.(*FixedCircle); ok {
+		manifold.Contacts = contact(circle.Center.Subtract(normal.Multiply(circle.Radius)))
+		return manifold
+	}
+	if circle, ok := shapeA.(*FixedCircle); ok {
+		manifold.Contacts = contact(circle.Center.Add(normal.Multiply(circle.Radius)))
+		return manifold
+	}
+
+	polygonA, okA := shapeA.(*FixedPolygon)
+	polygonB, okB := shapeB.(*FixedPolygon)
+	if okA && okB {
+		if contacts := clipFixedContacts(polygonA.Vertices, polygonB.Vertices, normal); len(contacts) > 0 {
+			manifold.Contacts = contacts
+			return manifold
+		}
+	}
+
+	// Fall back to the deepest point of shape B for any other shape.
+	manifold.Contacts = contact(shapeB.Support(normal.Negate()))
+	return manifold
+}
+
+// fixedFeature is the edge of a polygon that best faces a direction.
+type fixedFeature struct {
+	deepest    fixed.Vector
+	start, end fixed.Vector
+}
+
+func (f fixedFeature) direction() fixed.Vector {
+	return f.end.Subtract(f.start)
+}
+
+// clipFixedContacts is clipContacts in fixed point.
+func clipFixedContacts(verticesA, verticesB []fixed.Vector, normal fixed.Vector) []ContactPoint {
+	if len(verticesA) < 2 || len(verticesB) < 2 {
+		return nil
+	}
+
+	edgeA := bestFixedEdge(verticesA, normal)
+	edgeB := bestFixedEdge(verticesB, normal.Negate())
+
+	// The reference edge is the one most perpendicular to the normal.
+	reference, incident := edgeA, edgeB
+	referenceNormal := normal
+	if edgeA.direction().Normalize().Dot(normal).Abs() > edgeB.direction().Normalize().Dot(normal).Abs() {
+		reference, incident = edgeB, edgeA
+		referenceNormal = normal.Negate()
+	}
+
+	tangent := reference.direction().Normalize()
+
+	// Clip the incident edge against both ends of the reference edge.
+	points := clipFixed(incident.start, incident.end, tangent, tangent.Dot(reference.start))
+	if len(points) < 2 {
+		return nil
+	}
+	points = clipFixed(points[0], points[1], tangent.Negate(), tangent.Dot(reference.end).Neg())
+	if len(points) < 2 {
+		return nil
+	}
+
+	// Use the reference edge's face normal, pointing out of the reference shape.
+	faceNormal := tangent.Perp()
+	if faceNormal.Dot(referenceNormal) < 0 {
+		faceNormal = faceNormal.Negate()
+	}
+	faceOffset := faceNormal.Dot(reference.deepest)
+
+	// Keep only the points that lie behind the reference face.
+	contacts := make([]ContactPoint, 0, 2)
+	for _, point := range points {
+		if depth := faceOffset.Sub(faceNormal.Dot(point)); depth >= 0 {
+			contacts = append(contacts, ContactPoint{Point: point.Float(), Depth: depth.Float()})
+		}
+	}
+	return contacts
+}
+
+// bestFixedEdge is bestEdge in fixed point.
+func bestFixedEdge(vertices []fixed.Vector, direction fixed.Vector) fixedFeature {
+	index := 0
+	maxDot := vertices[0].Dot(direction)
+	for i, v := range vertices {
+		if dot := v.Dot(direction); dot > maxDot {
+			maxDot = dot
+			index = i
+		}
+	}
+
+	count := len(vertices)
+	v := vertices[index]
+	next := vertices[(index+1)%count]
+	prev := vertices[(index+count-1)%count]
+
+	toNext := next.Subtract(v).Normalize()
+	toPrev := prev.Subtract(v).Normalize()
+
+	if toPrev.Dot(direction).Abs() <= toNext.Dot(direction).Abs() {
+		return fixedFeature{deepest: v, start: prev, end: v}
+	}
+	return fixedFeature{deepest: v, start: v, end: next}
+}
+
+// clipFixed is clip in fixed point.
+func clipFixed(p1, p2 fixed.Vector, direction fixed.Vector, offset fixed.Fixed) []fixed.Vector {
+	points := make([]fixed.Vector, 0, 2)
+	d1 := direction.Dot(p1).Sub(offset)
+	d2 := direction.Dot(p2).Sub(offset)
+
+	if d1 >= 0 {
+		points = append(points, p1)
+	}
+	if d2 >= 0 {
+		points = append(points, p2)
+	}
+
+	// The points are on opposite sides: add the intersection point.
+	if (d1 < 0 && d2 > 0) || (d1 > 0 && d2 < 0) {
+		t := d1.Div(d1.Sub(d2))
+		points = append(points, p1.Add(p2.Subtract(p1).Multiply(t)))
+	}
+	return points
+}
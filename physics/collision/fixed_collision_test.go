@@ -0,0 +1,100 @@
+package collision
+
+import (
+	"testing"
+
+	"2d_game_engine/physics/fixed"
+	"2d_game_engine/physics/geometry"
+)
+
+// placeAtOrigin converts a shape that is already in world space.
+func placeAtOrigin(shape Shape) FixedShape {
+	return PlaceFixed(shape, fixed.Vector{}, 0)
+}
+
+func TestDetectCollisionFixedMatchesFloat(t *testing.T) {
+	floor := &geometry.Rectangle{Position: Vector2D{X: 0, Y: 10}, Width: 100, Height: 20}
+	tests := []struct {
+		name           string
+		shapeA, shapeB Shape
+	}{
+		{"box on box", &geometry.Rectangle{Position: Vector2D{X: 5, Y: -8}, Width: 20, Height: 20}, floor},
+		{"turned box", &geometry.Rectangle{Position: Vector2D{X: -20, Y: -12}, Width: 20, Height: 20, Rotation: 30}, floor},
+		{"plank on pillar",
+			&geometry.Rectangle{Position: Vector2D{X: 0, Y: 20}, Width: 10, Height: 40},
+			&geometry.Rectangle{Position: Vector2D{X: 20, Y: -4}, Width: 60, Height: 10}},
+		{"circle on box", &geometry.Circle{Center: Vector2D{X: 10, Y: -4}, Radius: 5}, floor},
+		{"box on circle", floor, &geometry.Circle{Center: Vector2D{X: -30, Y: -3}, Radius: 5}},
+		{"capsule on box", &geometry.Capsule{Position: Vector2D{X: 0, Y: -3}, Length: 30, Radius: 5, Rotation: 20}, floor},
+		{"ellipse on box", &geometry.Ellipse{Position: Vector2D{X: 0, Y: -8}, RadiusX: 20, RadiusY: 10, Rotation: 45}, floor},
+		{"compound on box", &geometry.Compound{
+			Position: Vector2D{X: 10, Y: -10},
+			Rotation: 90,
+			Children: []geometry.CompoundChild{
+				{Shape: &geometry.Capsule{Length: 20, Radius: 4}},
+				{Shape: &geometry.Circle{Radius: 6}, Position: Vector2D{X: 9, Y: 0}},
+			},
+		}, floor},
+		{"apart", &geometry.Circle{Center: Vector2D{X: 0, Y: -20}, Radius: 5}, floor},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want, wantOK := DetectCollision(test.shapeA, test.shapeB)
+			got, ok := DetectCollisionFixed(placeAtOrigin(test.shapeA), placeAtOrigin(test.shapeB))
+			if ok != wantOK {
+				t.Fatalf("DetectCollisionFixed() collides %v, DetectCollision() %v", ok, wantOK)
+			}
+			if !ok {
+				return
+			}
+			if !nearVector(got.Normal, want.Normal, 1e-6) || !near(got.Depth, want.Depth, 1e-6) {
+				t.Errorf("normal %v depth %v, want %v and %v", got.Normal, got.Depth, want.Normal, want.Depth)
+			}
+			gotContacts, wantContacts := sortedContacts(got.Contacts), sortedContacts(want.Contacts)
+			if len(gotContacts) != len(wantContacts) {
+				t.Fatalf("got %d contacts, want %d", len(gotContacts), len(wantContacts))
+			}
+			for i := range gotContacts {
+				if !nearVector(gotContacts[i].Point, wantContacts[i].Point, 1e-6) || !near(gotContacts[i].Depth, wantContacts[i].Depth, 1e-6) {
+					t.Errorf("contact %d = %+v, want %+v", i, gotContacts[i], wantContacts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDetectCollisionFixedTouching(t *testing.T) {
+	// Exactly touching shapes are not overlapping.
+	a := &geometry.Rectangle{Position: Vector2D{X: 0, Y: 0}, Width: 20, Height: 20}
+	b := &geometry.Rectangle{Position: Vector2D{X: 20, Y: 0}, Width: 20, Height: 20}
+	if manifold, ok := DetectCollisionFixed(placeAtOrigin(a), placeAtOrigin(b)); ok {
+		t.Errorf("touching boxes collide with %+v", manifold)
+	}
+}
+
+func TestPlaceFixed(t *testing.T) {
+	shapes := []Shape{
+		&geometry.Polygon{Vertices: []Vector2D{{X: -10, Y: -5}, {X: 15, Y: -5}, {X: 0, Y: 12}}},
+		&geometry.Rectangle{Position: Vector2D{X: 3, Y: 1}, Width: 20, Height: 10, Rotation: 15},
+		&geometry.Capsule{Length: 30, Radius: 4},
+		&geometry.Ellipse{RadiusX: 12, RadiusY: 5, Rotation: 10},
+		&geometry.Compound{Children: []geometry.CompoundChild{
+			{Shape: &geometry.Circle{Radius: 3}, Position: Vector2D{X: 10, Y: 0}},
+			{Shape: &geometry.Segment{Start: Vector2D{X: -5, Y: 0}, End: Vector2D{X: 5, Y: 2}}, Rotation: 40},
+		}},
+	}
+	transform := geometry.NewTransform(Vector2D{X: 120, Y: -40}, 65)
+	for _, shape := range shapes {
+		placed := PlaceFixed(shape, fixed.VectorFromFloat(transform.Position), fixed.FromFloat(transform.Rotation))
+		want := geometry.TransformShape(shape, transform)
+		for _, direction := range []Vector2D{{X: 1, Y: 0}, {X: -3, Y: 2}, {X: 0, Y: -1}} {
+			got := placed.Support(fixed.VectorFromFloat(direction)).Float()
+			if !near(got.Dot(direction), want.Support(direction).Dot(direction), 1e-6) {
+				t.Errorf("%T: Support(%v) = %v, want %v", shape, direction, got, want.Support(direction))
+			}
+		}
+		if got, want := FixedBounds(placed), geometry.ShapeBounds(want); !nearVector(got.Min, want.Min, 1e-6) || !nearVector(got.Max, want.Max, 1e-6) {
+			t.Errorf("%T: FixedBounds() = %v, want %v", shape, got, want)
+		}
+	}
+}
@@ -1,13 +1,24 @@
 package collision
 
 import (
+	"math"
+
 	"2d_game_engine/physics/geometry"
 )
 
-
 type Vector2D = geometry.Vector2D
 type Shape = geometry.Shape
 
+const (
+	// gjkMaxIterations caps the number of refinement steps. Polygons converge
+	// in a handful of iterations, curved shapes need a few more.
+	gjkMaxIterations = 64
+	// gjkTolerance is the relative progress below which GJK stops refining.
+	gjkTolerance = 1e-9
+	// gjkTouching is the distance under which two shapes are considered touching.
+	gjkTouching = 1e-9
+)
+
 // SupportMinkowskiDifference finds the support point for the Minkowski difference
 // of two shapes in a given direction.
 func SupportMinkowskiDifference(shapeA, shapeB Shape, direction Vector2D) Vector2D {
@@ -20,60 +31,276 @@ func SupportMinkowskiDifference(shapeA, shapeB Shape, direction Vector2D) Vector
 // GJKResult stores the result of the GJK collision check.
 type GJKResult struct {
 	Collision bool
-	Simplex   []geometry.Vector2D
+	// Simplex is a triangle enclosing the origin when Collision is true. It
+	// can be passed straight to EPA.
+	Simplex []geometry.Vector2D
+	// Distance is the separation between the shapes when they do not collide.
+	Distance float64
+	// PointA and PointB are the closest points on shapeA and shapeB when the
+	// shapes do not collide.
+	PointA Vector2D
+	PointB Vector2D
 }
 
+// simplexVertex is a point of the Minkowski difference together with the
+// support points on each shape that produced it.
+type simplexVertex struct {
+	a, b  Vector2D // Support points on shape A and shape B
+	point Vector2D // a - b
+	u     float64  // Barycentric weight of the closest point
+}
+
+type simplex struct {
+	vertices [3]simplexVertex
+	count    int
+}
 
-// GJKDetectCollision checks for collision and returns the simplex if one is found.
+func newSimplexVertex(shapeA, shapeB Shape, direction Vector2D) simplexVertex {
+	a := shapeA.Support(direction)
+	b := shapeB.Support(direction.Multiply(-1))
+	return simplexVertex{a: a, b: b, point: a.Subtract(b), u: 1}
+}
+
+// GJKDetectCollision runs the Gilbert–Johnson–Keerthi algorithm on two convex
+// shapes. When they overlap it returns a simplex enclosing the origin of the
+// Minkowski difference, otherwise it reports the separation distance and the
+// closest points between the shapes.
 func GJKDetectCollision(shapeA, shapeB Shape) GJKResult {
-	// Initial search direction: vector from B to A's centers.
-	direction := shapeA.Support(Vector2D{X: 1, Y: 0}).Subtract(shapeB.Support(Vector2D{X: -1, Y: 0}))
-	if direction.Length() == 0 {
-		direction.X = 1
-	}
+	var s simplex
+	s.vertices[0] = newSimplexVertex(shapeA, shapeB, Vector2D{X: 1, Y: 0})
+	s.count = 1
 
-	simplex := make([]Vector2D, 0, 3)
-	simplex = append(simplex, SupportMinkowskiDifference(shapeA, shapeB, direction))
-	direction = simplex[0].Multiply(-1)
+	for i := 0; i < gjkMaxIterations; i++ {
+		// Reduce the simplex to the feature closest to the origin.
+		switch s.count {
+		case 2:
+			s.solve2()
+		case 3:
+			s.solve3()
+		}
 
-	for {
-		newPoint := SupportMinkowskiDifference(shapeA, shapeB, direction)
-		if newPoint.Dot(direction) <= 0 {
-			// Origin is not in the Minkowski difference. No collision.
-			return GJKResult{Collision: false}
+		// The origin lies inside the triangle: the shapes overlap.
+		if s.count == 3 {
+			return GJKResult{Collision: true, Simplex: s.points()}
 		}
 
-		simplex = append(simplex, newPoint)
+		closest := s.closestPoint()
+		if closest.LengthSaqured() < gjkTouching*gjkTouching {
+			// The origin lies on a vertex or edge: the shapes are touching.
+			s.enclose(shapeA, shapeB)
+			return GJKResult{Collision: true, Simplex: s.points()}
+		}
 
-		if containsOrigin(simplex, &direction) {
-			// Origin is inside the simplex. Collision detected.
-			return GJKResult{Collision: true, Simplex: simplex}
+		// Search towards the origin from the closest point.
+		direction := closest.Negate()
+		vertex := newSimplexVertex(shapeA, shapeB, direction)
+
+		// Stop once the new support point no longer brings us closer.
+		if closest.LengthSaqured()-vertex.point.Dot(closest) <= gjkTolerance*closest.LengthSaqured() || s.contains(vertex) {
+			break
 		}
+
+		s.vertices[s.count] = vertex
+		s.count++
 	}
+
+	pointA, pointB := s.witnessPoints()
+	return GJKResult{
+		Collision: false,
+		Distance:  pointA.Subtract(pointB).Length(),
+		PointA:    pointA,
+		PointB:    pointB,
+	}
+}
+
+// solve2 reduces a line simplex to the closest feature using barycentric
+// coordinates of the origin.
+func (s *simplex) solve2() {
+	w1 := s.vertices[0].point
+	w2 := s.vertices[1].point
+	e12 := w2.Subtract(w1)
+
+	// Region of w1.
+	d12_2 := -w1.Dot(e12)
+	if d12_2 <= 0 {
+		s.vertices[0].u = 1
+		s.count = 1
+		return
+	}
+
+	// Region of w2.
+	d12_1 := w2.Dot(e12)
+	if d12_1 <= 0 {
+		s.vertices[1].u = 1
+		s.vertices[0] = s.vertices[1]
+		s.count = 1
+		return
+	}
+
+	// Region of the edge.
+	inv := 1 / (d12_1 + d12_2)
+	s.vertices[0].u = d12_1 * inv
+	s.vertices[1].u = d12_2 * inv
+	s.count = 2
 }
 
+// solve3 reduces a triangle simplex to the closest feature. If the origin is
+// inside the triangle the simplex is left with three vertices.
+func (s *simplex) solve3() {
+	w1 := s.vertices[0].point
+	w2 := s.vertices[1].point
+	w3 := s.vertices[2].point
+
+	// Edge regions.
+	e12 := w2.Subtract(w1)
+	d12_1 := w2.Dot(e12)
+	d12_2 := -w1.Dot(e12)
+
+	e13 := w3.Subtract(w1)
+	d13_1 := w3.Dot(e13)
+	d13_2 := -w1.Dot(e13)
+
+	e23 := w3.Subtract(w2)
+	d23_1 := w3.Dot(e23)
+	d23_2 := -w2.Dot(e23)
+
+	// Triangle regions.
+	n123 := e12.Cross(e13)
+	if math.Abs(n123) < 1e-12 {
+		// Degenerate (collinear) triangle: keep the newest edge.
+		s.vertices[0] = s.vertices[1]
+		s.vertices[1] = s.vertices[2]
+		s.count = 2
+		s.solve2()
+		return
+	}
+	d123_1 := n123 * w2.Cross(w3)
+	d123_2 := n123 * w3.Cross(w1)
+	d123_3 := n123 * w1.Cross(w2)
+
+	// Vertex w1 region.
+	if d12_2 <= 0 && d13_2 <= 0 {
+		s.vertices[0].u = 1
+		s.count = 1
+		return
+	}
+
+	// Edge w1-w2 region.
+	if d12_1 > 0 && d12_2 > 0 && d123_3 <= 0 {
+		inv := 1 / (d12_1 + d12_2)
+		s.vertices[0].u = d12_1 * inv
+		s.vertices[1].u = d12_2 * inv
+		s.count = 2
+		return
+	}
+
+	// Edge w1-w3 region.
+	if d13_1 > 0 && d13_2 > 0 && d123_2 <= 0 {
+		inv := 1 / (d13_1 + d13_2)
+		s.vertices[0].u = d13_1 * inv
+		s.vertices[2].u = d13_2 * inv
+		s.vertices[1] = s.vertices[2]
+		s.count = 2
+		return
+	}
 
-// containsOrigin updates the simplex and returns true if the origin is contained.
-// It also updates the search direction for the next iteration.
-func containsOrigin(simplex []Vector2D, direction *Vector2D) bool {
-	// Get the last added point (A) and the point before it (B).
-	A := simplex[len(simplex)-1]
-	B := simplex[len(simplex)-2]
+	// Vertex w2 region.
+	if d12_1 <= 0 && d23_2 <= 0 {
+		s.vertices[1].u = 1
+		s.vertices[0] = s.vertices[1]
+		s.count = 1
+		return
+	}
 
-	// The vector from B to A.
-	AB := B.Subtract(A)
-	// The vector from A to the origin.
-	AO := A.Multiply(-1)
+	// Vertex w3 region.
+	if d13_1 <= 0 && d23_1 <= 0 {
+		s.vertices[2].u = 1
+		s.vertices[0] = s.vertices[2]
+		s.count = 1
+		return
+	}
 
-	// Determine if the origin is on the same side as the perp vector.
-	// This finds the closest edge to the origin.
-	if AB.Perp().Dot(AO) < 0 {
-		// Origin is not on the side of the perp vector.
-		// The new search direction is towards the origin from the last added point.
-		*direction = AB.Perp().Multiply(-1)
-	} else {
-		*direction = AB.Perp()
+	// Edge w2-w3 region.
+	if d23_1 > 0 && d23_2 > 0 && d123_1 <= 0 {
+		inv := 1 / (d23_1 + d23_2)
+		s.vertices[1].u = d23_1 * inv
+		s.vertices[2].u = d23_2 * inv
+		s.vertices[0] = s.vertices[2]
+		s.count = 2
+		return
 	}
 
+	// Must be inside the triangle.
+	inv := 1 / (d123_1 + d123_2 + d123_3)
+	s.vertices[0].u = d123_1 * inv
+	s.vertices[1].u = d123_2 * inv
+	s.vertices[2].u = d123_3 * inv
+	s.count = 3
+}
+
+// closestPoint returns the point of the simplex closest to the origin.
+func (s *simplex) closestPoint() Vector2D {
+	var point Vector2D
+	for i := 0; i < s.count; i++ {
+		point = point.Add(s.vertices[i].point.Multiply(s.vertices[i].u))
+	}
+	return point
+}
+
+// witnessPoints returns the closest points on shape A and shape B.
+func (s *simplex) witnessPoints() (Vector2D, Vector2D) {
+	var pointA, pointB Vector2D
+	for i := 0; i < s.count; i++ {
+		pointA = pointA.Add(s.vertices[i].a.Multiply(s.vertices[i].u))
+		pointB = pointB.Add(s.vertices[i].b.Multiply(s.vertices[i].u))
+	}
+	return pointA, pointB
+}
+
+// contains reports whether the simplex already holds the given vertex.
+func (s *simplex) contains(vertex simplexVertex) bool {
+	for i := 0; i < s.count; i++ {
+		if s.vertices[i].point == vertex.point {
+			return true
+		}
+	}
 	return false
-}
\ No newline at end of file
+}
+
+// enclose grows a point or line simplex touching the origin into a triangle,
+// so that EPA always receives a polygon.
+func (s *simplex) enclose(shapeA, shapeB Shape) {
+	directions := []Vector2D{{X: 1, Y: 0}, {X: -1, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: -1}}
+
+	if s.count == 1 {
+		for _, direction := range directions {
+			vertex := newSimplexVertex(shapeA, shapeB, direction)
+			if vertex.point.Subtract(s.vertices[0].point).LengthSaqured() > gjkTouching {
+				s.vertices[1] = vertex
+				s.count = 2
+				break
+			}
+		}
+	}
+
+	if s.count == 2 {
+		edge := s.vertices[1].point.Subtract(s.vertices[0].point)
+		for _, direction := range []Vector2D{edge.Perp(), edge.Perp().Negate()} {
+			vertex := newSimplexVertex(shapeA, shapeB, direction)
+			if math.Abs(vertex.point.Subtract(s.vertices[0].point).Cross(edge)) > gjkTouching {
+				s.vertices[2] = vertex
+				s.count = 3
+				break
+			}
+		}
+	}
+}
+
+// points returns the Minkowski difference points of the simplex.
+func (s *simplex) points() []Vector2D {
+	points := make([]Vector2D, s.count)
+	for i := 0; i < s.count; i++ {
+		points[i] = s.vertices[i].point
+	}
+	return points
+}
@@ -0,0 +1,140 @@
+package collision
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics/geometry"
+)
+
+func near(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func nearVector(a, b Vector2D, tolerance float64) bool {
+	return near(a.X, b.X, tolerance) && near(a.Y, b.Y, tolerance)
+}
+
+func TestGJKSeparated(t *testing.T) {
+	tests := []struct {
+		name           string
+		a, b           Shape
+		distance       float64
+		pointA, pointB Vector2D
+	}{
+		{
+			"circles",
+			&geometry.Circle{Center: Vector2D{X: 0, Y: 0}, Radius: 10},
+			&geometry.Circle{Center: Vector2D{X: 30, Y: 0}, Radius: 5},
+			15, Vector2D{X: 10, Y: 0}, Vector2D{X: 25, Y: 0},
+		},
+		{
+			"squares face to face",
+			square(Vector2D{X: 0, Y: 0}, 20),
+			square(Vector2D{X: 0, Y: 50}, 20),
+			30, Vector2D{X: 0, Y: 10}, Vector2D{X: 0, Y: 40},
+		},
+		{
+			"square corner to circle",
+			square(Vector2D{X: 0, Y: 0}, 20),
+			&geometry.Circle{Center: Vector2D{X: 20, Y: 20}, Radius: 5},
+			math.Sqrt(200) - 5, Vector2D{X: 10, Y: 10}, Vector2D{X: 20 - 5/math.Sqrt2, Y: 20 - 5/math.Sqrt2},
+		},
+		{
+			"rotated box",
+			&geometry.Rectangle{Position: Vector2D{X: 0, Y: 0}, Width: 20, Height: 20, Rotation: 45},
+			&geometry.Circle{Center: Vector2D{X: 30, Y: 0}, Radius: 5},
+			25 - math.Sqrt(200), Vector2D{X: math.Sqrt(200), Y: 0}, Vector2D{X: 25, Y: 0},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := GJKDetectCollision(test.a, test.b)
+			if result.Collision {
+				t.Fatalf("GJKDetectCollision() reported a collision")
+			}
+			if !near(result.Distance, test.distance, 1e-6) {
+				t.Errorf("Distance = %v, want %v", result.Distance, test.distance)
+			}
+			// Curved shapes only converge to within GJK's tolerance.
+			if !nearVector(result.PointA, test.pointA, 1e-3) || !nearVector(result.PointB, test.pointB, 1e-3) {
+				t.Errorf("closest points %v, %v; want %v, %v", result.PointA, result.PointB, test.pointA, test.pointB)
+			}
+		})
+	}
+}
+
+func TestGJKOverlapping(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Shape
+	}{
+		{"circles", &geometry.Circle{Radius: 10}, &geometry.Circle{Center: Vector2D{X: 15, Y: 0}, Radius: 10}},
+		{"same square", square(Vector2D{}, 20), square(Vector2D{}, 20)},
+		{"square inside square", square(Vector2D{}, 40), square(Vector2D{X: 3, Y: -2}, 10)},
+		{"corner into face", square(Vector2D{}, 20), &geometry.Rectangle{Position: Vector2D{X: 22, Y: 0}, Width: 20, Height: 20, Rotation: 45}},
+		{"triangle and segment", &geometry.Triangle{Vertices: []Vector2D{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 0, Y: 20}}}, &geometry.Segment{Start: Vector2D{X: -5, Y: 5}, End: Vector2D{X: 25, Y: 5}}},
+		{"touching faces", square(Vector2D{}, 20), square(Vector2D{X: 20, Y: 0}, 20)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := GJKDetectCollision(test.a, test.b)
+			if !result.Collision {
+				t.Fatalf("GJKDetectCollision() = %+v, want a collision", result)
+			}
+			if len(result.Simplex) != 3 {
+				t.Fatalf("Simplex has %d points, want a triangle for EPA", len(result.Simplex))
+			}
+			if !triangleContainsOrigin(result.Simplex) {
+				t.Errorf("Simplex %v does not enclose the origin", result.Simplex)
+			}
+		})
+	}
+}
+
+// triangleContainsOrigin reports whether the origin is inside or on the edge
+// of a triangle of either winding.
+func triangleContainsOrigin(points []Vector2D) bool {
+	const tolerance = 1e-6
+	positive, negative := false, false
+	for i := range points {
+		a, b := points[i], points[(i+1)%len(points)]
+		cross := b.Subtract(a).Cross(a.Negate())
+		positive = positive || cross > tolerance
+		negative = negative || cross < -tolerance
+	}
+	return !(positive && negative)
+}
+
+func TestEPA(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   Shape
+		normal Vector2D
+		depth  float64
+	}{
+		{"squares side by side", square(Vector2D{}, 20), square(Vector2D{X: 15, Y: 0}, 20), Vector2D{X: 1, Y: 0}, 5},
+		{"squares stacked", square(Vector2D{}, 20), square(Vector2D{X: 2, Y: -18}, 20), Vector2D{X: 0, Y: -1}, 2},
+		{"circles", &geometry.Circle{Radius: 10}, &geometry.Circle{Center: Vector2D{X: 0, Y: 15}, Radius: 10}, Vector2D{X: 0, Y: 1}, 5},
+		{"circle on square", square(Vector2D{}, 20), &geometry.Circle{Center: Vector2D{X: -12, Y: 0}, Radius: 4}, Vector2D{X: -1, Y: 0}, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := GJKDetectCollision(test.a, test.b)
+			if !result.Collision {
+				t.Fatalf("GJKDetectCollision() found no collision")
+			}
+			normal, depth := EPA(result.Simplex, test.a, test.b)
+			if !nearVector(normal, test.normal, 1e-3) || !near(depth, test.depth, 1e-3) {
+				t.Errorf("EPA() = %v, %v; want %v, %v", normal, depth, test.normal, test.depth)
+			}
+		})
+	}
+}
+
+func TestEPANeedsTriangle(t *testing.T) {
+	normal, depth := EPA([]Vector2D{{X: 1, Y: 0}, {X: -1, Y: 0}}, square(Vector2D{}, 2), square(Vector2D{}, 2))
+	if normal != (Vector2D{}) || depth != 0 {
+		t.Errorf("EPA() on a line = %v, %v; want nothing", normal, depth)
+	}
+}
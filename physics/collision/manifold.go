@@ -0,0 +1,216 @@
+package collision
+
+import (
+	"math"
+
+	"2d_game_engine/physics/geometry"
+)
+
+//-----------------------------------------------------------------------------
+// Contact Manifold: Contact Point Generation
+//-----------------------------------------------------------------------------
+
+// ContactPoint is a single world-space point where two shapes touch.
+type ContactPoint struct {
+	Point Vector2D
+	Depth float64 // Penetration depth at this point
+}
+
+// Manifold describes how two shapes overlap. The normal points from shape A
+// towards shape B, matching the normal returned by EPA.
+type Manifold struct {
+	Normal   Vector2D
+	Depth    float64
+	Contacts []ContactPoint
+}
+
+// polygonalShape is implemented by shapes that can expose their outline in
+// world space, such as geometry.Polygon, geometry.Triangle and
+// geometry.Rectangle.
+type polygonalShape interface {
+	WorldVertices() []Vector2D
+}
+
+// feature is the edge of a polygon that best faces a direction.
+type feature struct {
+	deepest Vector2D // Vertex furthest along the direction
+	start   Vector2D
+	end     Vector2D
+}
+
+func (f feature) direction() Vector2D {
+	return f.end.Subtract(f.start)
+}
+
+// DetectCollision runs GJK and EPA on two shapes and builds their contact
+// manifold. It returns false when the shapes do not overlap. Compounds are
+// tested child by child and report their deepest contact.
+func DetectCollision(shapeA, shapeB Shape) (Manifold, bool) {
+	if compound, ok := shapeA.(*geometry.Compound); ok {
+		return deepestManifold(compound.WorldChildren(), func(child Shape) (Manifold, bool) {
+			return DetectCollision(child, shapeB)
+		})
+	}
+	if compound, ok := shapeB.(*geometry.Compound); ok {
+		return deepestManifold(compound.WorldChildren(), func(child Shape) (Manifold, bool) {
+			return DetectCollision(shapeA, child)
+		})
+	}
+
+	result := GJKDetectCollision(shapeA, shapeB)
+	if !result.Collision {
+		return Manifold{}, false
+	}
+
+	normal, depth := EPA(result.Simplex, shapeA, shapeB)
+	if normal.LengthSaqured() == 0 {
+		return Manifold{}, false
+	}
+
+	return BuildManifold(shapeA, shapeB, normal, depth), true
+}
+
+// deepestManifold collides each child and keeps the deepest manifold.
+func deepestManifold(children []Shape, detect func(child Shape) (Manifold, bool)) (Manifold, bool) {
+	var deepest Manifold
+	found := false
+	for _, child := range children {
+		manifold, ok := detect(child)
+		if ok && (!found || manifold.Depth > deepest.Depth) {
+			deepest = manifold
+			found = true
+		}
+	}
+	return deepest, found
+}
+
+// BuildManifold produces the contact points between two overlapping shapes
+// given the collision normal and depth from EPA. Polygonal pairs are clipped
+// against each other and yield up to two contacts, any pair involving a
+// circle yields a single point contact.
+func BuildManifold(shapeA, shapeB Shape, normal Vector2D, depth float64) Manifold {
+	manifold := Manifold{Normal: normal, Depth: depth}
+
+	// Circles touch at the point on their surface deepest inside the other shape.
+	if circle, ok := shapeB.(*geometry.Circle); ok {
+		point := circle.Center.Subtract(normal.Multiply(circle.Radius))
+		manifold.Contacts = []ContactPoint{{Point: point, Depth: depth}}
+		return manifold
+	}
+	if circle, ok := shapeA.(*geometry.Circle); ok {
+		point := circle.Center.Add(normal.Multiply(circle.Radius))
+		manifold.Contacts = []ContactPoint{{Point: point, Depth: depth}}
+		return manifold
+	}
+
+	polygonA, okA := shapeA.(polygonalShape)
+	polygonB, okB := shapeB.(polygonalShape)
+	if okA && okB {
+		contacts := clipContacts(polygonA.WorldVertices(), polygonB.WorldVertices(), normal)
+		if len(contacts) > 0 {
+			manifold.Contacts = contacts
+			return manifold
+		}
+	}
+
+	// Fall back to the deepest point of shape B for any other shape.
+	point := shapeB.Support(normal.Negate())
+	manifold.Contacts = []ContactPoint{{Point: point, Depth: depth}}
+	return manifold
+}
+
+// clipContacts finds the reference and incident edges of two polygons and
+// clips the incident edge against the reference edge's side planes.
+func clipContacts(verticesA, verticesB []Vector2D, normal Vector2D) []ContactPoint {
+	if len(verticesA) < 2 || len(verticesB) < 2 {
+		return nil
+	}
+
+	edgeA := bestEdge(verticesA, normal)
+	edgeB := bestEdge(verticesB, normal.Negate())
+
+	// The reference edge is the one most perpendicular to the normal.
+	reference, incident := edgeA, edgeB
+	referenceNormal := normal
+	if math.Abs(edgeA.direction().Normalize().Dot(normal)) > math.Abs(edgeB.direction().Normalize().Dot(normal)) {
+		reference, incident = edgeB, edgeA
+		referenceNormal = normal.Negate()
+	}
+
+	tangent := reference.direction().Normalize()
+
+	// Clip the incident edge against both ends of the reference edge.
+	points := clip(incident.start, incident.end, tangent, tangent.Dot(reference.start))
+	if len(points) < 2 {
+		return nil
+	}
+	points = clip(points[0], points[1], tangent.Negate(), -tangent.Dot(reference.end))
+	if len(points) < 2 {
+		return nil
+	}
+
+	// Use the reference edge's face normal, pointing out of the reference shape.
+	faceNormal := tangent.Perp()
+	if faceNormal.Dot(referenceNormal) < 0 {
+		faceNormal = faceNormal.Negate()
+	}
+	faceOffset := faceNormal.Dot(reference.deepest)
+
+	// Keep only the points that lie behind the reference face.
+	contacts := make([]ContactPoint, 0, 2)
+	for _, point := range points {
+		depth := faceOffset - faceNormal.Dot(point)
+		if depth >= 0 {
+			contacts = append(contacts, ContactPoint{Point: point, Depth: depth})
+		}
+	}
+	return contacts
+}
+
+// bestEdge returns the edge of a polygon that is most perpendicular to the
+// given direction among the two edges sharing its furthest vertex.
+func bestEdge(vertices []Vector2D, direction Vector2D) feature {
+	index := 0
+	maxDot := math.Inf(-1)
+	for i, v := range vertices {
+		if dot := v.Dot(direction); dot > maxDot {
+			maxDot = dot
+			index = i
+		}
+	}
+
+	count := len(vertices)
+	v := vertices[index]
+	next := vertices[(index+1)%count]
+	prev := vertices[(index+count-1)%count]
+
+	toNext := next.Subtract(v).Normalize()
+	toPrev := prev.Subtract(v).Normalize()
+
+	if math.Abs(toPrev.Dot(direction)) <= math.Abs(toNext.Dot(direction)) {
+		return feature{deepest: v, start: prev, end: v}
+	}
+	return feature{deepest: v, start: v, end: next}
+}
+
+// clip keeps the part of the segment p1-p2 whose projection onto direction is
+// at least offset.
+func clip(p1, p2 Vector2D, direction Vector2D, offset float64) []Vector2D {
+	points := make([]Vector2D, 0, 2)
+	d1 := direction.Dot(p1) - offset
+	d2 := direction.Dot(p2) - offset
+
+	if d1 >= 0 {
+		points = append(points, p1)
+	}
+	if d2 >= 0 {
+		points = append(points, p2)
+	}
+
+	// The points are on opposite sides: add the intersection point.
+	if d1*d2 < 0 {
+		t := d1 / (d1 - d2)
+		points = append(points, p1.Add(p2.Subtract(p1).Multiply(t)))
+	}
+	return points
+}
@@ -0,0 +1,166 @@
+package collision
+
+import (
+	"sort"
+	"testing"
+
+	"2d_game_engine/physics/geometry"
+)
+
+// sortedContacts orders contacts by X then Y so tests don't depend on which
+// edge was clipped first.
+func sortedContacts(contacts []ContactPoint) []ContactPoint {
+	sorted := append([]ContactPoint(nil), contacts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Point.X != sorted[j].Point.X {
+			return sorted[i].Point.X < sorted[j].Point.X
+		}
+		return sorted[i].Point.Y < sorted[j].Point.Y
+	})
+	return sorted
+}
+
+func TestDetectCollisionBoxOnBox(t *testing.T) {
+	// A 20 box sinking 2 pixels into a 100 wide floor whose top is y=0.
+	floor := &geometry.Rectangle{Position: Vector2D{X: 0, Y: 10}, Width: 100, Height: 20}
+	box := &geometry.Rectangle{Position: Vector2D{X: 5, Y: -8}, Width: 20, Height: 20}
+
+	manifold, ok := DetectCollision(box, floor)
+	if !ok {
+		t.Fatalf("DetectCollision() found no collision")
+	}
+	if !nearVector(manifold.Normal, Vector2D{X: 0, Y: 1}, 1e-6) || !near(manifold.Depth, 2, 1e-6) {
+		t.Errorf("normal %v depth %v, want {0 1} and 2", manifold.Normal, manifold.Depth)
+	}
+	contacts := sortedContacts(manifold.Contacts)
+	if len(contacts) != 2 {
+		t.Fatalf("got %d contacts, want 2", len(contacts))
+	}
+	// The floor's top face is clipped to the width of the box.
+	for i, want := range []Vector2D{{X: -5, Y: 0}, {X: 15, Y: 0}} {
+		if !nearVector(contacts[i].Point, want, 1e-6) || !near(contacts[i].Depth, 2, 1e-6) {
+			t.Errorf("contact %d = %+v, want %v at depth 2", i, contacts[i], want)
+		}
+	}
+}
+
+func TestDetectCollisionClipsToOverlap(t *testing.T) {
+	// A wide box hanging over the edge of a narrow one: the contacts are
+	// clipped to the narrow box's top face.
+	pillar := &geometry.Rectangle{Position: Vector2D{X: 0, Y: 20}, Width: 10, Height: 40}
+	plank := &geometry.Rectangle{Position: Vector2D{X: 20, Y: -4}, Width: 60, Height: 10}
+
+	manifold, ok := DetectCollision(pillar, plank)
+	if !ok {
+		t.Fatalf("DetectCollision() found no collision")
+	}
+	if !nearVector(manifold.Normal, Vector2D{X: 0, Y: -1}, 1e-6) {
+		t.Errorf("normal = %v, want {0 -1}", manifold.Normal)
+	}
+	contacts := sortedContacts(manifold.Contacts)
+	if len(contacts) != 2 {
+		t.Fatalf("got %d contacts, want 2", len(contacts))
+	}
+	if !near(contacts[0].Point.X, -5, 1e-6) || !near(contacts[1].Point.X, 5, 1e-6) {
+		t.Errorf("contacts at x=%v and x=%v, want the pillar's width", contacts[0].Point.X, contacts[1].Point.X)
+	}
+	for _, contact := range contacts {
+		if !near(contact.Depth, 1, 1e-6) {
+			t.Errorf("contact %+v, want depth 1", contact)
+		}
+	}
+}
+
+func TestDetectCollisionCornerOnFace(t *testing.T) {
+	floor := &geometry.Rectangle{Position: Vector2D{X: 0, Y: 10}, Width: 100, Height: 20}
+	// A diamond whose bottom corner is 1 pixel below the floor's top.
+	diamond := &geometry.Rectangle{Position: Vector2D{X: 0, Y: 1 - 10*1.4142135623730951}, Width: 20, Height: 20, Rotation: 45}
+
+	manifold, ok := DetectCollision(diamond, floor)
+	if !ok {
+		t.Fatalf("DetectCollision() found no collision")
+	}
+	if len(manifold.Contacts) != 1 {
+		t.Fatalf("got contacts %+v, want only the corner", manifold.Contacts)
+	}
+	if contact := manifold.Contacts[0]; !nearVector(contact.Point, Vector2D{X: 0, Y: 1}, 1e-6) || !near(contact.Depth, 1, 1e-6) {
+		t.Errorf("contact = %+v, want the corner at {0 1} with depth 1", contact)
+	}
+}
+
+func TestDetectCollisionCircle(t *testing.T) {
+	box := square(Vector2D{}, 20)
+	circle := &geometry.Circle{Center: Vector2D{X: 0, Y: 13}, Radius: 5}
+
+	for _, order := range []struct {
+		name   string
+		a, b   Shape
+		normal Vector2D
+	}{
+		{"box first", box, circle, Vector2D{X: 0, Y: 1}},
+		{"circle first", circle, box, Vector2D{X: 0, Y: -1}},
+	} {
+		t.Run(order.name, func(t *testing.T) {
+			manifold, ok := DetectCollision(order.a, order.b)
+			if !ok {
+				t.Fatalf("DetectCollision() found no collision")
+			}
+			if !nearVector(manifold.Normal, order.normal, 1e-3) || !near(manifold.Depth, 2, 1e-3) {
+				t.Errorf("normal %v depth %v, want %v and 2", manifold.Normal, manifold.Depth, order.normal)
+			}
+			// The contact is the circle's deepest point, inside the box.
+			if len(manifold.Contacts) != 1 || !nearVector(manifold.Contacts[0].Point, Vector2D{X: 0, Y: 8}, 1e-3) {
+				t.Errorf("contacts = %+v, want one at {0 8}", manifold.Contacts)
+			}
+		})
+	}
+}
+
+func TestDetectCollisionCompoundKeepsDeepest(t *testing.T) {
+	// A square dropped into the cup's left wall and notch floor at once.
+	block := square(Vector2D{X: 25, Y: 37}, 10)
+
+	manifold, ok := DetectCollision(cup(), block)
+	if !ok {
+		t.Fatalf("DetectCollision() found no collision")
+	}
+	// The block overlaps the notch floor by 2 and the wall by 0 on its left
+	// face, so the floor wins.
+	if !nearVector(manifold.Normal, Vector2D{X: 0, Y: -1}, 1e-6) || !near(manifold.Depth, 2, 1e-6) {
+		t.Errorf("normal %v depth %v, want {0 -1} and 2", manifold.Normal, manifold.Depth)
+	}
+}
+
+func TestDetectCollisionSeparated(t *testing.T) {
+	if manifold, ok := DetectCollision(square(Vector2D{}, 10), square(Vector2D{X: 11, Y: 0}, 10)); ok {
+		t.Errorf("DetectCollision() = %+v for separated squares", manifold)
+	}
+}
+
+func TestClip(t *testing.T) {
+	right := Vector2D{X: 1, Y: 0}
+	tests := []struct {
+		name   string
+		p1, p2 Vector2D
+		offset float64
+		want   []Vector2D
+	}{
+		{"both kept", Vector2D{X: 2, Y: 0}, Vector2D{X: 4, Y: 1}, 1, []Vector2D{{X: 2, Y: 0}, {X: 4, Y: 1}}},
+		{"both cut", Vector2D{X: 0, Y: 0}, Vector2D{X: 0.5, Y: 1}, 1, []Vector2D{}},
+		{"split", Vector2D{X: 0, Y: 0}, Vector2D{X: 4, Y: 4}, 1, []Vector2D{{X: 4, Y: 4}, {X: 1, Y: 1}}},
+		{"on the plane", Vector2D{X: 1, Y: 0}, Vector2D{X: 0, Y: 0}, 1, []Vector2D{{X: 1, Y: 0}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := clip(test.p1, test.p2, right, test.offset)
+			if len(got) != len(test.want) {
+				t.Fatalf("clip() = %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if !nearVector(got[i], test.want[i], 1e-12) {
+					t.Errorf("clip() = %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package collision
+
+import (
+	"math"
+
+	"2d_game_engine/physics/geometry"
+)
+
+//-----------------------------------------------------------------------------
+// Ray casts, shape casts and point and overlap tests against single shapes.
+// Rays are segments from -> to; fractions are measured along them from 0 at
+// from to 1 at to. Rays that start inside a shape do not hit it.
+//-----------------------------------------------------------------------------
+
+// RayHit describes where a ray first crosses a shape's boundary.
+type RayHit struct {
+	Point    Vector2D
+	Normal   Vector2D // Surface normal at the hit point, facing the ray
+	Fraction float64
+}
+
+// RayCast intersects the segment from -> to with a shape. Circles, polygons,
+// triangles and rectangles are intersected exactly, compounds child by child;
+// other shapes fall back to conservative advancement of a point.
+func RayCast(shape Shape, from, to Vector2D) (RayHit, bool) {
+	switch s := shape.(type) {
+	case *geometry.Circle:
+		return rayCastCircle(s, from, to)
+	case *geometry.Compound:
+		var closest RayHit
+		found := false
+		for _, child := range s.WorldChildren() {
+			if hit, ok := RayCast(child, from, to); ok && (!found || hit.Fraction < closest.Fraction) {
+				closest, found = hit, true
+			}
+		}
+		return closest, found
+	case polygonalShape:
+		return rayCastPolygon(s.WorldVertices(), from, to)
+	default:
+		return rayCastSupport(shape, from, to)
+	}
+}
+
+func rayCastCircle(circle *geometry.Circle, from, to Vector2D) (RayHit, bool) {
+	s := from.Subtract(circle.Center)
+	b := s.Dot(s) - circle.Radius*circle.Radius
+
+	// Solve |s + t*d| = r for the smaller root.
+	d := to.Subtract(from)
+	c := s.Dot(d)
+	rr := d.Dot(d)
+	sigma := c*c - rr*b
+	if sigma < 0 || rr < 1e-12 {
+		return RayHit{}, false
+	}
+
+	a := -(c + math.Sqrt(sigma))
+	if a < 0 || a > rr {
+		return RayHit{}, false
+	}
+
+	fraction := a / rr
+	return RayHit{
+		Point:    from.Add(d.Multiply(fraction)),
+		Normal:   s.Add(d.Multiply(fraction)).Normalize(),
+		Fraction: fraction,
+	}, true
+}
+
+// rayCastPolygon clips the ray against each edge of a convex polygon.
+func rayCastPolygon(vertices []Vector2D, from, to Vector2D) (RayHit, bool) {
+	if len(vertices) < 3 {
+		return RayHit{}, false
+	}
+
+	// Outward normals depend on the winding.
+	winding := 1.0
+	if geometry.SignedArea(vertices) < 0 {
+		winding = -1
+	}
+
+	d := to.Subtract(from)
+	lower, upper := 0.0, 1.0
+	var hitNormal Vector2D
+	hit := false
+	for i := range vertices {
+		v1 := vertices[i]
+		v2 := vertices[(i+1)%len(vertices)]
+		edge := v2.Subtract(v1)
+		normal := Vector2D{X: edge.Y, Y: -edge.X}.Multiply(winding)
+
+		// The ray is inside this edge's half-plane where numerator >= t*denominator.
+		numerator := normal.Dot(v1.Subtract(from))
+		denominator := normal.Dot(d)
+		switch {
+		case denominator == 0:
+			if numerator < 0 {
+				return RayHit{}, false
+			}
+		case denominator < 0 && numerator < lower*denominator:
+			// Entering the half-plane.
+			lower = numerator / denominator
+			hitNormal = normal
+			hit = true
+		case denominator > 0 && numerator < upper*denominator:
+			// Leaving the half-plane.
+			upper = numerator / denominator
+		}
+		if upper < lower {
+			return RayHit{}, false
+		}
+	}
+
+	if !hit {
+		return RayHit{}, false
+	}
+	return RayHit{
+		Point:    from.Add(d.Multiply(lower)),
+		Normal:   hitNormal.Normalize(),
+		Fraction: lower,
+	}, true
+}
+
+// rayCastSupport sweeps a point against any convex shape.
+func rayCastSupport(shape Shape, from, to Vector2D) (RayHit, bool) {
+	result := TimeOfImpact(
+		Sweep{Shape: &pointShape{}, Position0: from, Position1: to},
+		Sweep{Shape: shape},
+	)
+	if !result.Hit || result.Overlapping {
+		return RayHit{}, false
+	}
+	return RayHit{
+		Point:    result.PointB,
+		Normal:   result.Normal.Negate(),
+		Fraction: result.Time,
+	}, true
+}
+
+// ContainsPoint reports whether a point lies inside or on a shape.
+func ContainsPoint(shape Shape, point Vector2D) bool {
+	switch s := shape.(type) {
+	case *geometry.Circle:
+		return point.Subtract(s.Center).LengthSaqured() <= s.Radius*s.Radius
+	case *geometry.Compound:
+		for _, child := range s.WorldChildren() {
+			if ContainsPoint(child, point) {
+				return true
+			}
+		}
+		return false
+	case polygonalShape:
+		vertices := s.WorldVertices()
+		if len(vertices) < 3 {
+			return false
+		}
+		winding := geometry.SignedArea(vertices)
+		for i := range vertices {
+			edge := vertices[(i+1)%len(vertices)].Subtract(vertices[i])
+			if edge.Cross(point.Subtract(vertices[i]))*winding < 0 {
+				return false
+			}
+		}
+		return true
+	default:
+		return GJKDetectCollision(shape, &pointShape{point: point}).Collision
+	}
+}
+
+// TestOverlap reports whether two shapes overlap or touch. Compounds are
+// tested child by child.
+func TestOverlap(shapeA, shapeB Shape) bool {
+	for _, pair := range [2][2]Shape{{shapeA, shapeB}, {shapeB, shapeA}} {
+		if compound, ok := pair[0].(*geometry.Compound); ok {
+			for _, child := range compound.WorldChildren() {
+				if TestOverlap(child, pair[1]) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return GJKDetectCollision(shapeA, shapeB).Collision
+}
+
+// ShapeCast moves a shape by translation and returns when it first touches
+// target. The shapes are given in world space. Compounds are cast child by
+// child.
+func ShapeCast(shape Shape, translation Vector2D, target Shape) TOIResult {
+	return TimeOfImpact(
+		Sweep{Shape: shape, Position1: translation},
+		Sweep{Shape: target},
+	)
+}
+
+// pointShape is a single point, used to run GJK on rays and points.
+type pointShape struct {
+	point Vector2D
+}
+
+func (p *pointShape) Support(direction Vector2D) Vector2D {
+	return p.point
+}
@@ -0,0 +1,153 @@
+package collision
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics/geometry"
+)
+
+// cup returns a compound cup 60 wide and 60 tall, open at the top (Y down)
+// with a notch 20 wide whose floor is at y = 40.
+func cup() *geometry.Compound {
+	return geometry.NewCompound([]geometry.Polygon{
+		{Vertices: []Vector2D{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 60}, {X: 0, Y: 60}}},
+		{Vertices: []Vector2D{{X: 20, Y: 40}, {X: 40, Y: 40}, {X: 40, Y: 60}, {X: 20, Y: 60}}},
+		{Vertices: []Vector2D{{X: 40, Y: 0}, {X: 60, Y: 0}, {X: 60, Y: 60}, {X: 40, Y: 60}}},
+	})
+}
+
+func square(center Vector2D, size float64) *geometry.Polygon {
+	half := size / 2
+	return &geometry.Polygon{
+		Vertices: []Vector2D{{X: -half, Y: -half}, {X: half, Y: -half}, {X: half, Y: half}, {X: -half, Y: half}},
+		Position: center,
+	}
+}
+
+func TestShapeCastHitsPolygon(t *testing.T) {
+	target := square(Vector2D{X: 100, Y: 0}, 20)
+	result := ShapeCast(square(Vector2D{}, 10), Vector2D{X: 200, Y: 0}, target)
+	if !result.Hit || result.Overlapping {
+		t.Fatalf("ShapeCast() = %+v, want a hit", result)
+	}
+	// The faces meet after 85 pixels, less the touching distance.
+	if travelled := result.Time * 200; travelled > 85 || travelled < 84.5 {
+		t.Errorf("travelled %v pixels before the hit, want just under 85", travelled)
+	}
+	if math.Abs(result.Normal.X-1) > 1e-6 {
+		t.Errorf("normal = %v, want {1 0}", result.Normal)
+	}
+}
+
+func TestShapeCastIntoConcaveCompound(t *testing.T) {
+	// Dropped into the notch, the box passes the cup's rim and lands on the
+	// notch floor rather than on the convex hull's top.
+	result := ShapeCast(square(Vector2D{X: 30, Y: -50}, 10), Vector2D{X: 0, Y: 200}, cup())
+	if !result.Hit {
+		t.Fatalf("ShapeCast() missed the cup")
+	}
+	if travelled := result.Time * 200; travelled < 84.5 || travelled > 85 {
+		t.Errorf("travelled %v pixels, want just under 85 to the notch floor", travelled)
+	}
+
+	// Dropped onto a wall, it lands on the rim.
+	result = ShapeCast(square(Vector2D{X: 10, Y: -50}, 10), Vector2D{X: 0, Y: 200}, cup())
+	if travelled := result.Time * 200; !result.Hit || travelled < 44.5 || travelled > 45 {
+		t.Errorf("onto the rim: hit %v after %v pixels, want just under 45", result.Hit, travelled)
+	}
+}
+
+func TestShapeCastCompoundAgainstCompound(t *testing.T) {
+	caster := geometry.NewCompound([]geometry.Polygon{*square(Vector2D{X: 30, Y: -50}, 10)})
+	result := ShapeCast(caster, Vector2D{X: 0, Y: 200}, cup())
+	if travelled := result.Time * 200; !result.Hit || travelled < 84.5 || travelled > 85 {
+		t.Errorf("hit %v after %v pixels, want just under 85 to the notch floor", result.Hit, travelled)
+	}
+}
+
+func TestRayCastIntoConcaveCompound(t *testing.T) {
+	hit, ok := RayCast(cup(), Vector2D{X: 30, Y: -50}, Vector2D{X: 30, Y: 150})
+	if !ok {
+		t.Fatalf("RayCast() missed the cup")
+	}
+	if math.Abs(hit.Point.Y-40) > 1e-6 {
+		t.Errorf("ray hit at %v, want the notch floor at y = 40", hit.Point)
+	}
+}
+
+func TestRayCast(t *testing.T) {
+	tests := []struct {
+		name     string
+		shape    Shape
+		from, to Vector2D
+		hit      bool
+		fraction float64
+		normal   Vector2D
+	}{
+		{"circle", &geometry.Circle{Center: Vector2D{X: 50, Y: 0}, Radius: 10}, Vector2D{}, Vector2D{X: 100, Y: 0}, true, 0.4, Vector2D{X: -1, Y: 0}},
+		{"polygon", square(Vector2D{X: 0, Y: 50}, 20), Vector2D{}, Vector2D{X: 0, Y: 100}, true, 0.4, Vector2D{X: 0, Y: -1}},
+		{"rotated box", &geometry.Rectangle{Position: Vector2D{X: 50, Y: 0}, Width: 20, Height: 20, Rotation: 45}, Vector2D{X: 0, Y: -5}, Vector2D{X: 100, Y: -5}, true, (55 - 10*math.Sqrt2) / 100, Vector2D{X: -math.Sqrt2 / 2, Y: -math.Sqrt2 / 2}},
+		{"capsule", &geometry.Capsule{Position: Vector2D{X: 50, Y: 0}, Length: 40, Radius: 5, Rotation: 90}, Vector2D{}, Vector2D{X: 100, Y: 0}, true, 0.45, Vector2D{X: -1, Y: 0}},
+		{"too short", square(Vector2D{X: 50, Y: 0}, 20), Vector2D{}, Vector2D{X: 30, Y: 0}, false, 0, Vector2D{}},
+		{"misses", &geometry.Circle{Center: Vector2D{X: 50, Y: 20}, Radius: 10}, Vector2D{}, Vector2D{X: 100, Y: 0}, false, 0, Vector2D{}},
+		{"starts inside", square(Vector2D{}, 20), Vector2D{}, Vector2D{X: 100, Y: 0}, false, 0, Vector2D{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hit, ok := RayCast(test.shape, test.from, test.to)
+			if ok != test.hit {
+				t.Fatalf("RayCast() = %+v, %v; want hit %v", hit, ok, test.hit)
+			}
+			if !ok {
+				return
+			}
+			// Shapes without a closed-form cast stop up to toiTarget short.
+			length := test.to.Subtract(test.from).Length()
+			if !near(hit.Fraction, test.fraction, 2*toiTarget/length) {
+				t.Errorf("Fraction = %v, want %v", hit.Fraction, test.fraction)
+			}
+			if want := test.from.Add(test.to.Subtract(test.from).Multiply(test.fraction)); !nearVector(hit.Point, want, 1e-3) {
+				t.Errorf("Point = %v, want %v", hit.Point, want)
+			}
+			if !nearVector(hit.Normal, test.normal, 1e-3) {
+				t.Errorf("Normal = %v, want %v", hit.Normal, test.normal)
+			}
+		})
+	}
+}
+
+func TestContainsPoint(t *testing.T) {
+	box := &geometry.Rectangle{Position: Vector2D{X: 10, Y: 10}, Width: 20, Height: 10, Rotation: 90}
+	for _, test := range []struct {
+		point Vector2D
+		want  bool
+	}{
+		{Vector2D{X: 10, Y: 10}, true},
+		{Vector2D{X: 10, Y: 19}, true},
+		{Vector2D{X: 5, Y: 10}, true}, // On the edge
+		{Vector2D{X: 19, Y: 10}, false},
+		{Vector2D{X: 30, Y: 30}, false},
+	} {
+		if got := ContainsPoint(box, test.point); got != test.want {
+			t.Errorf("ContainsPoint(%v) = %v, want %v", test.point, got, test.want)
+		}
+	}
+	if ContainsPoint(cup(), Vector2D{X: 30, Y: 20}) {
+		t.Errorf("point in the cup's notch counted as inside")
+	}
+	if !ContainsPoint(cup(), Vector2D{X: 30, Y: 50}) {
+		t.Errorf("point in the cup's base counted as outside")
+	}
+}
+
+func TestTestOverlap(t *testing.T) {
+	circle := &geometry.Circle{Center: Vector2D{X: 30, Y: 20}, Radius: 5}
+	if TestOverlap(cup(), circle) {
+		t.Errorf("circle in the cup's notch overlaps the cup")
+	}
+	circle.Center.Y = 36
+	if !TestOverlap(cup(), circle) {
+		t.Errorf("circle touching the notch floor does not overlap the cup")
+	}
+}
@@ -0,0 +1,260 @@
+package collision
+
+import (
+	"math"
+	"sort"
+)
+
+//-----------------------------------------------------------------------------
+// SpatialHash buckets proxies into a uniform grid of square cells. It suits
+// levels made of many similarly sized objects, such as tile maps. Proxies
+// too big for the grid, such as a level-wide floor or bounds gone infinite,
+// are kept in a separate list and tested against everything instead.
+//-----------------------------------------------------------------------------
+
+const (
+	// maxProxyCells is the most cells a proxy or query is spread over.
+	maxProxyCells = 1024
+	// maxCellCoordinate keeps cell coordinates well inside the int range.
+	maxCellCoordinate = 1 << 30
+)
+
+type cellKey struct {
+	x, y int
+}
+
+// cellRange is the inclusive range of cells covered by a proxy.
+type cellRange struct {
+	minX, minY, maxX, maxY int
+}
+
+type hashProxy struct {
+	bounds AABB
+	cells  cellRange
+	large  bool // Not in the grid; see maxProxyCells
+	static bool
+	filter Filter
+}
+
+type SpatialHash struct {
+	cellSize float64
+	cells    map[cellKey][]int
+	proxies  map[int]*hashProxy
+	large    []int // IDs of proxies too big for the grid, sorted
+}
+
+// NewSpatialHash creates a spatial hash with the given cell size in pixels.
+// A good cell size is about the size of a typical body. It panics if the cell
+// size is not a positive, finite number.
+func NewSpatialHash(cellSize float64) *SpatialHash {
+	if !(cellSize > 0) || math.IsInf(cellSize, 1) {
+		panic("collision: spatial hash cell size must be positive and finite")
+	}
+	return &SpatialHash{
+		cellSize: cellSize,
+		cells:    make(map[cellKey][]int),
+		proxies:  make(map[int]*hashProxy),
+		large:    make([]int, 0),
+	}
+}
+
+func (sh *SpatialHash) Insert(id int, bounds AABB, static bool, filter Filter) {
+	cells, ok := sh.cellRange(bounds)
+	proxy := &hashProxy{bounds: bounds, cells: cells, large: !ok, static: static, filter: filter}
+	sh.proxies[id] = proxy
+	sh.place(id, proxy)
+}
+
+func (sh *SpatialHash) Move(id int, bounds AABB) {
+	proxy, ok := sh.proxies[id]
+	if !ok {
+		return
+	}
+	proxy.bounds = bounds
+
+	// Only touch the grid when the proxy crosses into other cells.
+	cells, ok := sh.cellRange(bounds)
+	if cells != proxy.cells || proxy.large != !ok {
+		sh.unplace(id, proxy)
+		proxy.cells, proxy.large = cells, !ok
+		sh.place(id, proxy)
+	}
+}
+
+func (sh *SpatialHash) Remove(id int) {
+	proxy, ok := sh.proxies[id]
+	if !ok {
+		return
+	}
+	sh.unplace(id, proxy)
+	delete(sh.proxies, id)
+}
+
+// place adds a proxy to its cells, or to the large list.
+func (sh *SpatialHash) place(id int, proxy *hashProxy) {
+	if !proxy.large {
+		sh.addToCells(id, proxy.cells)
+		return
+	}
+	i := sort.SearchInts(sh.large, id)
+	sh.large = append(sh.large, 0)
+	copy(sh.large[i+1:], sh.large[i:])
+	sh.large[i] = id
+}
+
+// unplace removes a proxy from its cells, or from the large list.
+func (sh *SpatialHash) unplace(id int, proxy *hashProxy) {
+	if !proxy.large {
+		sh.removeFromCells(id, proxy.cells)
+		return
+	}
+	i := sort.SearchInts(sh.large, id)
+	sh.large = append(sh.large[:i], sh.large[i+1:]...)
+}
+
+// candidates calls callback with every proxy that may overlap a proxy or
+// query covering cells: those in the cells and the large ones. When cells is
+// not usable (ok is false), every proxy is a candidate. IDs may repeat.
+func (sh *SpatialHash) candidates(cells cellRange, ok bool, callback func(id int) bool) {
+	if !ok {
+		for _, id := range sh.sortedIDs() {
+			if !callback(id) {
+				return
+			}
+		}
+		return
+	}
+	for _, id := range sh.large {
+		if !callback(id) {
+			return
+		}
+	}
+	stop := false
+	sh.forEachCell(cells, func(key cellKey) {
+		for _, id := range sh.cells[key] {
+			if stop {
+				return
+			}
+			stop = !callback(id)
+		}
+	})
+}
+
+// sortedIDs returns every proxy ID in order.
+func (sh *SpatialHash) sortedIDs() []int {
+	ids := make([]int, 0, len(sh.proxies))
+	for id := range sh.proxies {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (sh *SpatialHash) Pairs() []Pair {
+	pairs := make([]Pair, 0)
+	seen := make(map[Pair]bool)
+
+	for id, proxy := range sh.proxies {
+		if proxy.static {
+			continue
+		}
+		sh.candidates(proxy.cells, !proxy.large, func(other int) bool {
+			if other == id {
+				return true
+			}
+			pair := newPair(id, other)
+			if seen[pair] {
+				return true
+			}
+			otherProxy := sh.proxies[other]
+			if proxy.bounds.Overlaps(otherProxy.bounds) && ShouldCollide(proxy.filter, otherProxy.filter) {
+				seen[pair] = true
+				pairs = append(pairs, pair)
+			}
+			return true
+		})
+	}
+
+	sortPairs(pairs)
+	return pairs
+}
+
+func (sh *SpatialHash) Query(bounds AABB, callback func(id int) bool) {
+	seen := make(map[int]bool)
+	cells, ok := sh.cellRange(bounds)
+	sh.candidates(cells, ok, func(id int) bool {
+		if seen[id] {
+			return true
+		}
+		seen[id] = true
+		return !sh.proxies[id].bounds.Overlaps(bounds) || callback(id)
+	})
+}
+
+// RayCast visits the cells covered by the segment's bounding box, so it suits
+// short rays such as ground probes best.
+func (sh *SpatialHash) RayCast(from, to Vector2D, callback func(id int, maxFraction float64) float64) {
+	bounds := AABB{
+		Min: Vector2D{X: math.Min(from.X, to.X), Y: math.Min(from.Y, to.Y)},
+		Max: Vector2D{X: math.Max(from.X, to.X), Y: math.Max(from.Y, to.Y)},
+	}
+
+	maxFraction := 1.0
+	sh.Query(bounds, func(id int) bool {
+		if !rayHitsBounds(sh.proxies[id].bounds, from, to, maxFraction) {
+			return true
+		}
+		maxFraction = callback(id, maxFraction)
+		return maxFraction > 0
+	})
+}
+
+// cellRange returns the cells covered by bounds. It returns false when the
+// bounds are not finite, lie too far out, or cover more than maxProxyCells.
+func (sh *SpatialHash) cellRange(bounds AABB) (cellRange, bool) {
+	minX, minY := math.Floor(bounds.Min.X/sh.cellSize), math.Floor(bounds.Min.Y/sh.cellSize)
+	maxX, maxY := math.Floor(bounds.Max.X/sh.cellSize), math.Floor(bounds.Max.Y/sh.cellSize)
+	for _, coordinate := range [4]float64{minX, minY, maxX, maxY} {
+		// Also false for NaN.
+		if !(math.Abs(coordinate) <= maxCellCoordinate) {
+			return cellRange{}, false
+		}
+	}
+	if (maxX-minX+1)*(maxY-minY+1) > maxProxyCells {
+		return cellRange{}, false
+	}
+	return cellRange{minX: int(minX), minY: int(minY), maxX: int(maxX), maxY: int(maxY)}, true
+}
+
+func (sh *SpatialHash) forEachCell(cells cellRange, callback func(key cellKey)) {
+	for x := cells.minX; x <= cells.maxX; x++ {
+		for y := cells.minY; y <= cells.maxY; y++ {
+			callback(cellKey{x: x, y: y})
+		}
+	}
+}
+
+func (sh *SpatialHash) addToCells(id int, cells cellRange) {
+	sh.forEachCell(cells, func(key cellKey) {
+		sh.cells[key] = append(sh.cells[key], id)
+	})
+}
+
+func (sh *SpatialHash) removeFromCells(id int, cells cellRange) {
+	sh.forEachCell(cells, func(key cellKey) {
+		bucket := sh.cells[key]
+		for i, other := range bucket {
+			if other == id {
+				// Order within a bucket does not matter, so swap-remove.
+				bucket[i] = bucket[len(bucket)-1]
+				bucket = bucket[:len(bucket)-1]
+				break
+			}
+		}
+		if len(bucket) == 0 {
+			delete(sh.cells, key)
+		} else {
+			sh.cells[key] = bucket
+		}
+	})
+}
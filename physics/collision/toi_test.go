@@ -0,0 +1,138 @@
+package collision
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics/geometry"
+)
+
+// staticSweep keeps a shape still at a position for the whole sweep.
+func staticSweep(shape Shape, position Vector2D) Sweep {
+	return Sweep{Shape: shape, Position0: position, Position1: position}
+}
+
+func localSquare(size float64) *geometry.Polygon {
+	return square(Vector2D{}, size)
+}
+
+func TestSweepInterpolates(t *testing.T) {
+	sweep := Sweep{Position0: Vector2D{X: 0, Y: 10}, Position1: Vector2D{X: 100, Y: 30}, Angle0: 10, Angle1: 50}
+	if got := sweep.Position(0.25); got != (Vector2D{X: 25, Y: 15}) {
+		t.Errorf("Position(0.25) = %v, want {25 15}", got)
+	}
+	if got := sweep.Angle(0.5); got != 30 {
+		t.Errorf("Angle(0.5) = %v, want 30", got)
+	}
+}
+
+func TestTimeOfImpact(t *testing.T) {
+	wall := staticSweep(&geometry.Rectangle{Width: 20, Height: 200}, Vector2D{X: 200, Y: 0})
+	tests := []struct {
+		name   string
+		sweep  Sweep
+		hit    bool
+		time   float64 // Where the shapes would touch exactly
+		normal Vector2D
+	}{
+		{
+			"circle into wall",
+			Sweep{Shape: &geometry.Circle{Radius: 10}, Position0: Vector2D{X: 0, Y: 0}, Position1: Vector2D{X: 400, Y: 0}},
+			true, 180.0 / 400, Vector2D{X: 1, Y: 0},
+		},
+		{
+			"square into wall at an angle",
+			Sweep{Shape: localSquare(20), Position0: Vector2D{X: 0, Y: -50}, Position1: Vector2D{X: 380, Y: 50}},
+			true, 180.0 / 380, Vector2D{X: 1, Y: 0},
+		},
+		{
+			"stops short",
+			Sweep{Shape: &geometry.Circle{Radius: 10}, Position0: Vector2D{X: 0, Y: 0}, Position1: Vector2D{X: 150, Y: 0}},
+			false, 0, Vector2D{},
+		},
+		{
+			"passes beside",
+			Sweep{Shape: &geometry.Circle{Radius: 10}, Position0: Vector2D{X: 0, Y: 150}, Position1: Vector2D{X: 400, Y: 150}},
+			false, 0, Vector2D{},
+		},
+		{
+			"moving away",
+			Sweep{Shape: &geometry.Circle{Radius: 10}, Position0: Vector2D{X: 179.9, Y: 0}, Position1: Vector2D{X: 0, Y: 0}},
+			false, 0, Vector2D{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := TimeOfImpact(test.sweep, wall)
+			if result.Hit != test.hit || result.Overlapping {
+				t.Fatalf("TimeOfImpact() = %+v, want hit %v", result, test.hit)
+			}
+			if !test.hit {
+				return
+			}
+			// The shapes stop up to toiTarget short of touching.
+			travel := test.sweep.Position1.Subtract(test.sweep.Position0).Length()
+			if gap := (test.time - result.Time) * travel; gap < 0 || gap > 2*toiTarget {
+				t.Errorf("Time = %v, want just before %v", result.Time, test.time)
+			}
+			if !nearVector(result.Normal, test.normal, 1e-3) {
+				t.Errorf("Normal = %v, want %v", result.Normal, test.normal)
+			}
+			if GJKDetectCollision(test.sweep.ShapeAt(result.Time), wall.ShapeAt(result.Time)).Collision {
+				t.Errorf("shapes overlap at the reported time")
+			}
+		})
+	}
+}
+
+func TestTimeOfImpactOverlapping(t *testing.T) {
+	a := staticSweep(localSquare(20), Vector2D{})
+	b := Sweep{Shape: localSquare(20), Position0: Vector2D{X: 10, Y: 0}, Position1: Vector2D{X: 100, Y: 0}}
+	if result := TimeOfImpact(a, b); !result.Hit || !result.Overlapping || result.Time != 0 {
+		t.Errorf("TimeOfImpact() = %+v, want an overlap at time 0", result)
+	}
+}
+
+func TestTimeOfImpactBothMoving(t *testing.T) {
+	// Two circles 100 apart closing at 100 each: they touch after 80 of the
+	// 200 pixels they close by.
+	a := Sweep{Shape: &geometry.Circle{Radius: 10}, Position0: Vector2D{X: 0, Y: 0}, Position1: Vector2D{X: 100, Y: 0}}
+	b := Sweep{Shape: &geometry.Circle{Radius: 10}, Position0: Vector2D{X: 100, Y: 0}, Position1: Vector2D{X: 0, Y: 0}}
+	result := TimeOfImpact(a, b)
+	if !result.Hit || result.Time > 0.4 || result.Time < 0.4-toiTarget/200 {
+		t.Errorf("TimeOfImpact() = %+v, want a hit just before 0.4", result)
+	}
+}
+
+func TestTimeOfImpactRotation(t *testing.T) {
+	// A long bar spinning about its centre sweeps through a small square it
+	// never reaches by translation alone.
+	bar := Sweep{Shape: &geometry.Rectangle{Width: 200, Height: 4}, Angle0: 0, Angle1: 90}
+	target := staticSweep(localSquare(10), Vector2D{X: 0, Y: 80})
+	result := TimeOfImpact(bar, target)
+	if !result.Hit || result.Time <= 0 || result.Time >= 1 {
+		t.Fatalf("TimeOfImpact() = %+v, want a hit part way through the turn", result)
+	}
+	if GJKDetectCollision(bar.ShapeAt(result.Time), target.ShapeAt(result.Time)).Collision {
+		t.Errorf("shapes overlap at the reported time")
+	}
+	// The bar's edge, 2 from its axis, reaches the square's corner at {5 75}.
+	corner := Vector2D{X: 5, Y: 75}
+	want := geometry.Degrees(math.Atan2(corner.Y, corner.X) - math.Asin(2/corner.Length()))
+	if angle := bar.Angle(result.Time); angle > want || angle < want-1 {
+		t.Errorf("bar hit at %v degrees, want just before %v", angle, want)
+	}
+}
+
+func TestTimeOfImpactCompound(t *testing.T) {
+	// A small ball falls into the cup's notch: it passes the hull's top and
+	// hits the notch floor at y=40.
+	ball := Sweep{Shape: &geometry.Circle{Radius: 5}, Position0: Vector2D{X: 30, Y: -50}, Position1: Vector2D{X: 30, Y: 100}}
+	result := TimeOfImpact(ball, staticSweep(cup(), Vector2D{}))
+	if !result.Hit {
+		t.Fatalf("TimeOfImpact() = %+v, want a hit", result)
+	}
+	if y := ball.Position(result.Time).Y; y < 35-2*toiTarget || y > 35 {
+		t.Errorf("ball stopped at y=%v, want just above 35", y)
+	}
+}
@@ -0,0 +1,144 @@
+package physics
+
+import (
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/geometry"
+	"2d_game_engine/physics/material"
+)
+
+//-----------------------------------------------------------------------------
+// Continuous collision detection: bullets are swept from where they started
+// the step to where they ended it. At the first impact the bullet is stopped,
+// its approaching velocity removed, and it moves on for the rest of the step
+// in a new sub-step. Other bodies are treated as resting at their end pose.
+//-----------------------------------------------------------------------------
+
+// maxSubSteps limits how many impacts a bullet resolves in one step.
+const maxSubSteps = 8
+
+// bulletStart is where a bullet was at the start of the step.
+type bulletStart struct {
+	body     *Body
+	position Vector2D
+	angle    float64
+}
+
+// beginContinuous records the start pose of every active bullet.
+func (w *World) beginContinuous() []bulletStart {
+	starts := make([]bulletStart, 0)
+	for _, b := range w.bodies {
+		if b.GetIsBullet() && !b.GetIsSensor() && isActive(b) && b.GetShape() != nil {
+			starts = append(starts, bulletStart{body: b, position: b.GetPosition(), angle: b.GetAngle()})
+		}
+	}
+	return starts
+}
+
+// solveContinuous sweeps every bullet over the step that was just integrated.
+func (w *World) solveContinuous(starts []bulletStart, deltaTime float64) {
+	if len(starts) == 0 {
+		return
+	}
+	w.updateBroadPhase()
+	for _, start := range starts {
+		w.sweepBullet(start, deltaTime)
+	}
+}
+
+func (w *World) sweepBullet(start bulletStart, deltaTime float64) {
+	b := start.body
+	position, angle := start.position, start.angle
+	remaining := deltaTime
+
+	for i := 0; i < maxSubSteps; i++ {
+		sweep := collision.Sweep{
+			Shape:     b.GetShape(),
+			Position0: position,
+			Position1: b.GetPosition(),
+			Angle0:    angle,
+			Angle1:    b.GetAngle(),
+		}
+		other, result := w.firstImpact(b, sweep)
+		if other == nil {
+			return
+		}
+
+		// Stop at the impact, respond, and move on with what is left of the step.
+		position, angle = sweep.Position(result.Time), sweep.Angle(result.Time)
+		b.SetPosition(position)
+		b.SetAngle(angle)
+		w.resolveImpact(b, other, result.Normal)
+
+		remaining *= 1 - result.Time
+		b.SetPosition(position.Add(b.GetVelocity().Multiply(remaining)))
+		b.SetAngle(angle + geometry.Degrees(b.GetAngularVelocity()*remaining))
+	}
+
+	// Out of sub-steps: stay at the last impact rather than risk tunnelling.
+	b.SetPosition(position)
+	b.SetAngle(angle)
+}
+
+// firstImpact returns the body a bullet's sweep hits first, or nil. Bodies the
+// bullet already overlaps at the start, or touches while moving away, are left
+// to the discrete solver, and bullets do not sweep against each other.
+func (w *World) firstImpact(b *Body, sweep collision.Sweep) (*Body, collision.TOIResult) {
+	bounds := geometry.ShapeBounds(sweep.ShapeAt(0)).Union(geometry.ShapeBounds(sweep.ShapeAt(1)))
+
+	var first *Body
+	best := collision.TOIResult{Time: 1}
+	w.broadPhase.Query(bounds, func(id int) bool {
+		other := w.bodyByID[id]
+		if other == nil || other == b || other.GetIsBullet() || other.GetIsSensor() || !w.shouldCollide(b, other) {
+			return true
+		}
+
+		otherSweep := collision.Sweep{
+			Shape:     other.GetShape(),
+			Position0: other.GetPosition(),
+			Position1: other.GetPosition(),
+			Angle0:    other.GetAngle(),
+			Angle1:    other.GetAngle(),
+		}
+		result := collision.TimeOfImpact(sweep, otherSweep)
+		if !result.Hit || result.Overlapping {
+			return true
+		}
+		if result.Time == 0 && sweep.Position1.Subtract(sweep.Position0).Dot(result.Normal) <= 0 {
+			// Touching at the start but moving away, as after a bounce. A
+			// spinning bullet can still be reported as closing in.
+			return true
+		}
+		if result.Time < best.Time || (result.Time == best.Time && first != nil && id < first.GetID()) {
+			first, best = other, result
+		}
+		return true
+	})
+	return first, best
+}
+
+// resolveImpact removes the bullet's velocity into the body it hit, bouncing
+// off if the impact is fast enough. The normal points from the bullet to the
+// other body. Only linear velocity is affected.
+func (w *World) resolveImpact(b, other *Body, normal Vector2D) {
+	approach := b.GetVelocity().Subtract(other.GetVelocity()).Dot(normal)
+	if approach <= 0 {
+		return
+	}
+
+	restitution := material.Combine(b.GetMaterial(), other.GetMaterial()).Restitution
+	if approach < restitutionThreshold {
+		restitution = 0
+	}
+
+	inverseMassA, _ := inverseMasses(b)
+	inverseMassB, _ := inverseMasses(other)
+	impulse := normal.Multiply(-(1 + restitution) * approach / (inverseMassA + inverseMassB))
+	b.ApplyImpulse(impulse, b.GetPosition())
+	if inverseMassB != 0 {
+		if other.GetIsSleeping() {
+			w.wakeIsland(other)
+		}
+		other.ApplyImpulse(impulse.Negate(), other.GetPosition())
+	}
+}
@@ -0,0 +1,60 @@
+package physics
+
+import (
+	"testing"
+
+	"2d_game_engine/physics/body"
+	"2d_game_engine/physics/material"
+)
+
+func TestBulletStopsAtWall(t *testing.T) {
+	world := NewWorld()
+	world.SetGravity(Vector2D{})
+
+	wall := body.FromRectangle(Vector2D{X: 500, Y: 0}, 20, 200)
+	wall.SetIsStatic(true)
+	world.AddBody(wall)
+
+	bullet := body.FromCircle(Vector2D{X: 0, Y: 0}, 5)
+	bullet.SetIsBullet(true)
+	bullet.SetVelocity(Vector2D{X: 6000, Y: 0})
+	world.AddBody(bullet)
+
+	for i := 0; i < 20; i++ {
+		world.Step(1.0 / 60)
+		if x := bullet.GetPosition().X; x > 490 {
+			t.Fatalf("step %d: bullet tunnelled to x = %v", i, x)
+		}
+	}
+}
+
+func TestBulletBouncesOffWall(t *testing.T) {
+	world := NewWorld()
+	world.SetGravity(Vector2D{})
+
+	wall := body.FromRectangle(Vector2D{X: 500, Y: 0}, 20, 200)
+	wall.SetIsStatic(true)
+	world.AddBody(wall)
+
+	bullet := body.FromCircle(Vector2D{X: 0, Y: 0}, 5)
+	bullet.SetMaterial(material.Rubber)
+	bullet.SetIsBullet(true)
+	bullet.SetVelocity(Vector2D{X: 6000, Y: 0})
+	world.AddBody(bullet)
+
+	bounced := -1
+	for i := 0; i < 20; i++ {
+		world.Step(1.0 / 60)
+		if bounced < 0 && bullet.GetVelocity().X < 0 {
+			bounced = i
+		}
+	}
+	if bounced < 0 {
+		t.Fatalf("bullet never bounced, velocity %v", bullet.GetVelocity())
+	}
+
+	// Once bounced the bullet must leave the wall rather than stay glued to it.
+	if x := bullet.GetPosition().X; x > 400 {
+		t.Errorf("bullet still near the wall at x = %v, velocity %v", x, bullet.GetVelocity())
+	}
+}
@@ -0,0 +1,122 @@
+package physics
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/fixed"
+	"2d_game_engine/physics/geometry"
+)
+
+//-----------------------------------------------------------------------------
+// Determinism for lockstep simulation and replays. The solver already visits
+// bodies, pairs and contacts in ID order, so the same build on the same
+// machine always gives the same results. Built with -tags fixedpoint, the
+// world also steps in the Q32.32 arithmetic of the fixed package: body
+// integration, the broad phase bounds, the narrow phase, the contact solver
+// and sleeping never round through float64, so a world of bodies and contacts
+// gives bit-identical results on every architecture. Joints, continuous
+// collision for bullets, effectors and the character controller still run in
+// float64, where the compiler may fuse multiply-adds differently from one
+// architecture to another; quantizeState snaps what they leave behind back to
+// the grid, but worlds that use them are only reproducible per architecture.
+// StateHash summarises the state so peers can compare it cheaply and detect a
+// desync.
+//-----------------------------------------------------------------------------
+
+// FixedPoint reports whether the engine was built with -tags fixedpoint, in
+// which case the world steps in fixed-point arithmetic.
+const FixedPoint = fixed.Enabled
+
+// narrowPhase collides two bodies' world shapes, in fixed point when
+// FixedPoint is set.
+func narrowPhase(bodyA, bodyB *Body) (collision.Manifold, bool) {
+	if FixedPoint {
+		return collision.DetectCollisionFixed(bodyA.GetFixedWorldShape(), bodyB.GetFixedWorldShape())
+	}
+	return collision.DetectCollision(bodyA.GetWorldShape(), bodyB.GetWorldShape())
+}
+
+// bodyBounds returns the bounds the broad phase keeps for a body. In fixed
+// point they come from the same shapes as the narrow phase, so the pairs it
+// reports do not depend on float64 rounding either.
+func bodyBounds(b *Body) geometry.AABB {
+	if FixedPoint {
+		return b.FixedBounds()
+	}
+	return b.Bounds()
+}
+
+// quantizeState rounds body state and warm-start impulses to fixed point.
+func (w *World) quantizeState() {
+	if !FixedPoint {
+		return
+	}
+
+	for _, b := range w.bodies {
+		// Static and sleeping bodies do not move, so they keep their state.
+		if b.GetIsStatic() || b.GetIsSleeping() {
+			continue
+		}
+		b.SetPosition(quantizeVector(b.GetPosition()))
+		b.SetAngle(quantize(b.GetAngle()))
+		b.SetVelocity(quantizeVector(b.GetVelocity()))
+		b.SetAngularVelocity(quantize(b.GetAngularVelocity()))
+	}
+	for _, c := range w.contacts {
+		for i := range c.points {
+			c.points[i].normalImpulse = quantize(c.points[i].normalImpulse)
+			c.points[i].tangentImpulse = quantize(c.points[i].tangentImpulse)
+		}
+	}
+}
+
+// quantize rounds a value to the nearest Q32.32 fixed-point number.
+func quantize(value float64) float64 {
+	return fixed.FromFloat(value).Float()
+}
+
+func quantizeVector(v Vector2D) Vector2D {
+	return Vector2D{X: quantize(v.X), Y: quantize(v.Y)}
+}
+
+// StateHash returns a 64-bit FNV-1a hash of every body's ID, position, angle,
+// velocity, angular velocity and sleep state, in ID order. Values are hashed
+// as fixed-point numbers, so two worlds that agree to within the fixed-point
+// resolution hash the same. Compare hashes between peers, or against a
+// recording, to detect a desync.
+func (w *World) StateHash() uint64 {
+	bodies := make([]*Body, len(w.bodies))
+	copy(bodies, w.bodies)
+	sort.Slice(bodies, func(i, j int) bool {
+		return bodies[i].GetID() < bodies[j].GetID()
+	})
+
+	hash := fnv.New64a()
+	var buffer [8]byte
+	write := func(value int64) {
+		binary.LittleEndian.PutUint64(buffer[:], uint64(value))
+		hash.Write(buffer[:])
+	}
+	writeFloat := func(value float64) {
+		write(int64(fixed.FromFloat(value)))
+	}
+
+	for _, b := range bodies {
+		write(int64(b.GetID()))
+		writeFloat(b.GetPosition().X)
+		writeFloat(b.GetPosition().Y)
+		writeFloat(b.GetAngle())
+		writeFloat(b.GetVelocity().X)
+		writeFloat(b.GetVelocity().Y)
+		writeFloat(b.GetAngularVelocity())
+		if b.GetIsSleeping() {
+			write(1)
+		} else {
+			write(0)
+		}
+	}
+	return hash.Sum64()
+}
@@ -0,0 +1,83 @@
+package physics
+
+import (
+	"testing"
+
+	"2d_game_engine/physics/body"
+	"2d_game_engine/physics/collision"
+)
+
+// newStackScene returns a world with a floor, a stack of boxes and a few
+// balls, which between them exercise contacts, friction, restitution and
+// sleeping. With bullet set it also fires a bullet along the floor, for
+// continuous collision.
+func newStackScene(bullet bool) *World {
+	world := NewWorld()
+
+	floor := body.FromRectangle(Vector2D{X: 400, Y: 600}, 800, 40)
+	floor.SetIsStatic(true)
+	world.AddBody(floor)
+
+	for i := 0; i < 5; i++ {
+		box := body.FromRectangle(Vector2D{X: 400 + float64(i%2)*3, Y: 560 - float64(i)*42}, 40, 40)
+		world.AddBody(box)
+	}
+	for i := 0; i < 3; i++ {
+		ball := body.FromCircle(Vector2D{X: 200 + float64(i)*60, Y: 300 - float64(i)*30}, 15)
+		ball.SetRestitution(0.5)
+		world.AddBody(ball)
+	}
+
+	if bullet {
+		b := body.FromCircle(Vector2D{X: 0, Y: 540}, 4)
+		b.SetIsBullet(true)
+		b.SetVelocity(Vector2D{X: 3000, Y: 0})
+		world.AddBody(b)
+	}
+	return world
+}
+
+const determinismSteps = 300
+
+func TestStateHashRepeatable(t *testing.T) {
+	a, b := newStackScene(true), newStackScene(true)
+	for i := 0; i < determinismSteps; i++ {
+		a.Step(1.0 / 60)
+		b.Step(1.0 / 60)
+		if a.StateHash() != b.StateHash() {
+			t.Fatalf("step %d: identical worlds hash differently", i)
+		}
+	}
+}
+
+func TestStateHashSameForEveryBroadPhase(t *testing.T) {
+	// Broad phases only propose pairs, so they must not change the result.
+	broadPhases := []collision.BroadPhase{collision.NewBruteForce(), collision.NewSpatialHash(40), collision.NewAABBTree(4)}
+	hashes := make([]uint64, len(broadPhases))
+	for i, broadPhase := range broadPhases {
+		world := newStackScene(true)
+		world.SetBroadPhase(broadPhase)
+		for step := 0; step < determinismSteps; step++ {
+			world.Step(1.0 / 60)
+		}
+		hashes[i] = world.StateHash()
+	}
+	for i := 1; i < len(hashes); i++ {
+		if hashes[i] != hashes[0] {
+			t.Errorf("%T gives hash %#x, %T gives %#x", broadPhases[i], hashes[i], broadPhases[0], hashes[0])
+		}
+	}
+}
+
+func TestStateHashDetectsChange(t *testing.T) {
+	a, b := newStackScene(true), newStackScene(true)
+	if a.StateHash() != b.StateHash() {
+		t.Fatalf("identical worlds hash differently")
+	}
+
+	moved := b.GetBodies()[1]
+	moved.SetPosition(moved.GetPosition().Add(Vector2D{X: 1e-6}))
+	if a.StateHash() == b.StateHash() {
+		t.Errorf("hash did not change when a body moved")
+	}
+}
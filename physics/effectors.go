@@ -0,0 +1,230 @@
+package physics
+
+import (
+	"math"
+
+	"2d_game_engine/physics/geometry"
+	"2d_game_engine/physics/material"
+)
+
+//-----------------------------------------------------------------------------
+// Area effectors push the bodies overlapping a sensor region: wind zones,
+// water volumes, explosions. They run at the start of every step, before
+// velocities are integrated, and only affect dynamic bodies that the region's
+// collision filter matches. Sleeping bodies are left alone, so bodies settled
+// in water can sleep, unless the effector is a WakingEffector.
+//-----------------------------------------------------------------------------
+
+// Effector applies forces to a body inside a region.
+type Effector interface {
+	Apply(world *World, region, b *Body)
+}
+
+// WakingEffector is an effector that also reaches sleeping bodies, waking
+// them, because it can set resting bodies moving.
+type WakingEffector interface {
+	Effector
+	WakesBodies() bool
+}
+
+type areaEffector struct {
+	id       int
+	region   *Body
+	effector Effector
+}
+
+// AddEffector attaches an effector to a region body, making it a sensor, and
+// returns an ID that can be passed to RemoveEffector. A region may carry
+// several effectors; they run in the order they were added.
+func (w *World) AddEffector(region *Body, effector Effector) int {
+	region.SetIsSensor(true)
+	w.nextEffectorID++
+	w.effectors = append(w.effectors, areaEffector{id: w.nextEffectorID, region: region, effector: effector})
+	return w.nextEffectorID
+}
+
+// RemoveEffector removes an effector added with AddEffector.
+func (w *World) RemoveEffector(id int) {
+	for i, e := range w.effectors {
+		if e.id == id {
+			w.effectors = append(w.effectors[:i], w.effectors[i+1:]...)
+			return
+		}
+	}
+}
+
+// applyEffectors runs each effector over the bodies overlapping its region.
+func (w *World) applyEffectors() {
+	for _, e := range w.effectors {
+		region := e.region
+		if w.bodyByID[region.GetID()] != region || region.GetShape() == nil {
+			continue
+		}
+		waking, ok := e.effector.(WakingEffector)
+		wakes := ok && waking.WakesBodies()
+		for _, b := range w.QueryShape(region.GetWorldShape(), region.GetCollisionFilter()) {
+			if b == region || b.GetIsStatic() || b.GetIsSensor() {
+				continue
+			}
+			if b.GetIsSleeping() {
+				if !wakes {
+					continue
+				}
+				w.wakeIsland(b)
+			}
+			e.effector.Apply(w, region, b)
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+// Directional
+//-----------------------------------------------------------------------------
+
+// DirectionalEffector pushes bodies one way, like wind or a conveyor of air.
+type DirectionalEffector struct {
+	Force Vector2D
+	// Acceleration makes Force an acceleration, scaled by each body's mass,
+	// so heavy and light bodies are pushed alike.
+	Acceleration bool
+}
+
+func (e *DirectionalEffector) Apply(world *World, region, b *Body) {
+	force := e.Force
+	if e.Acceleration {
+		force = force.Multiply(b.GetMass())
+	}
+	b.ApplyForce(force, b.GetPosition())
+}
+
+// WakesBodies is true: wind blows resting bodies away.
+func (e *DirectionalEffector) WakesBodies() bool {
+	return true
+}
+
+//-----------------------------------------------------------------------------
+// Radial
+//-----------------------------------------------------------------------------
+
+// Falloff shapes how a radial effector weakens with distance.
+type Falloff int
+
+const (
+	// FalloffNone keeps full strength out to the radius.
+	FalloffNone Falloff = iota
+	// FalloffLinear fades to nothing at the radius.
+	FalloffLinear
+	// FalloffQuadratic fades to nothing at the radius, dropping quickly
+	// near the centre.
+	FalloffQuadratic
+)
+
+// RadialEffector pushes bodies away from the region's position, or pulls them
+// in with a negative strength. For an explosion, add one for a single step
+// and then remove it.
+type RadialEffector struct {
+	Strength float64
+	// Radius limits the effect; zero uses the distance from the region's
+	// position to the corner of its bounds.
+	Radius       float64
+	Falloff      Falloff
+	Acceleration bool // Strength is an acceleration, scaled by each body's mass
+}
+
+func (e *RadialEffector) Apply(world *World, region, b *Body) {
+	center := region.GetPosition()
+	offset := b.GetPosition().Subtract(center)
+	distance := offset.Length()
+	if distance < 1e-9 {
+		return
+	}
+
+	radius := e.Radius
+	if radius <= 0 {
+		bounds := region.Bounds()
+		radius = math.Max(bounds.Min.Distance(center), bounds.Max.Distance(center))
+	}
+	if distance > radius {
+		return
+	}
+
+	strength := e.Strength
+	switch e.Falloff {
+	case FalloffLinear:
+		strength *= 1 - distance/radius
+	case FalloffQuadratic:
+		strength *= (1 - distance/radius) * (1 - distance/radius)
+	}
+	if e.Acceleration {
+		strength *= b.GetMass()
+	}
+	b.ApplyForce(offset.Divide(distance).Multiply(strength), b.GetPosition())
+}
+
+// WakesBodies is true: explosions reach sleeping bodies.
+func (e *RadialEffector) WakesBodies() bool {
+	return true
+}
+
+//-----------------------------------------------------------------------------
+// Drag
+//-----------------------------------------------------------------------------
+
+// DragEffector slows bodies down, like thick air or mud. The drags are rates
+// per second, independent of mass: a linear drag of 2 loses about 2 × speed
+// per second. Keep them below 2 / deltaTime.
+type DragEffector struct {
+	LinearDrag  float64
+	AngularDrag float64
+}
+
+func (e *DragEffector) Apply(world *World, region, b *Body) {
+	b.ApplyForce(b.GetVelocity().Multiply(-e.LinearDrag*b.GetMass()), b.GetPosition())
+	b.ApplyTorque(-e.AngularDrag * b.GetInertia() * b.GetAngularVelocity())
+}
+
+//-----------------------------------------------------------------------------
+// Buoyancy
+//-----------------------------------------------------------------------------
+
+// BuoyancyEffector makes the region a body of fluid. Each body's outline is
+// clipped against the region to find the submerged area and its centroid;
+// the fluid pushes up against gravity in proportion to that area, at the
+// centroid, so floating bodies right themselves. Bodies less dense than
+// Density float. Compound bodies are clipped by their convex hull.
+type BuoyancyEffector struct {
+	Density float64 // Mass per square pixel, like material densities
+	// LinearDrag and AngularDrag slow submerged bodies, in proportion to how
+	// much of them is under.
+	LinearDrag   float64
+	AngularDrag  float64
+	FlowVelocity Vector2D // Velocity of the fluid, for currents
+}
+
+// NewWater returns a buoyancy effector with the density of the default
+// material and some drag.
+func NewWater() *BuoyancyEffector {
+	return &BuoyancyEffector{Density: material.Default.Density, LinearDrag: 2, AngularDrag: 1}
+}
+
+func (e *BuoyancyEffector) Apply(world *World, region, b *Body) {
+	submerged := geometry.ClipPolygon(b.WorldVertices(), region.WorldVertices())
+	area := geometry.Area(submerged)
+	if area < 1e-9 {
+		return
+	}
+	centroid := geometry.Centroid(submerged)
+	displaced := e.Density * area
+
+	// Archimedes: the weight of the displaced fluid, pushing against gravity.
+	b.ApplyForce(world.GetGravity().Multiply(-displaced), centroid)
+
+	// Drag relative to the flow, at the centre of the submerged part.
+	offset := centroid.Subtract(b.GetPosition())
+	velocity := b.GetVelocity().Add(crossScalar(b.GetAngularVelocity(), offset))
+	b.ApplyForce(velocity.Subtract(e.FlowVelocity).Multiply(-e.LinearDrag*displaced), centroid)
+	if bodyArea := b.GetArea(); bodyArea > 0 {
+		fraction := math.Min(area/bodyArea, 1)
+		b.ApplyTorque(-e.AngularDrag * fraction * b.GetInertia() * b.GetAngularVelocity())
+	}
+}
@@ -0,0 +1,133 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics/body"
+)
+
+// newEffectorWorld returns a world without gravity holding a static region
+// of the given size centred at the origin.
+func newEffectorWorld(width, height float64) (*World, *Body) {
+	world := NewWorld()
+	world.SetGravity(Vector2D{})
+	world.SetSleepingEnabled(false)
+	region := body.FromRectangle(Vector2D{}, width, height)
+	region.SetIsStatic(true)
+	world.AddBody(region)
+	return world, region
+}
+
+// addBall adds a ball without air friction, so only effectors slow it.
+func addBall(world *World, position Vector2D, radius float64) *Body {
+	ball := body.FromCircle(position, radius)
+	ball.SetFrictionAir(0)
+	world.AddBody(ball)
+	return ball
+}
+
+func TestDirectionalEffector(t *testing.T) {
+	world, region := newEffectorWorld(200, 200)
+	light := addBall(world, Vector2D{X: 0, Y: -50}, 5)
+	heavy := addBall(world, Vector2D{X: 0, Y: 50}, 20)
+	outside := addBall(world, Vector2D{X: 0, Y: 500}, 5)
+	world.AddEffector(region, &DirectionalEffector{Force: Vector2D{X: 100, Y: 0}, Acceleration: true})
+	if !region.GetIsSensor() {
+		t.Errorf("AddEffector() did not make the region a sensor")
+	}
+
+	stepFor(world, 0.5)
+	for name, b := range map[string]*Body{"light": light, "heavy": heavy} {
+		if speed := b.GetVelocity().X; math.Abs(speed-50) > 1e-6 {
+			t.Errorf("%s ball moves at %v after half a second, want 50", name, speed)
+		}
+	}
+	if !outside.GetVelocity().IsZero() {
+		t.Errorf("ball outside the region moves at %v", outside.GetVelocity())
+	}
+}
+
+func TestRadialEffectorFalloff(t *testing.T) {
+	world, region := newEffectorWorld(400, 400)
+	near := addBall(world, Vector2D{X: 25, Y: 0}, 5)
+	far := addBall(world, Vector2D{X: 0, Y: -75}, 5)
+	beyond := addBall(world, Vector2D{X: 150, Y: 0}, 5)
+	world.AddEffector(region, &RadialEffector{Strength: 6000, Radius: 100, Falloff: FalloffLinear, Acceleration: true})
+
+	world.Step(1.0 / 60)
+	// Linear falloff: three quarters of the strength at 25, a quarter at 75.
+	if speed := near.GetVelocity(); !speed.ApproxEqual(Vector2D{X: 75, Y: 0}, 1e-6) {
+		t.Errorf("near ball pushed to %v, want {75 0}", speed)
+	}
+	if speed := far.GetVelocity(); !speed.ApproxEqual(Vector2D{X: 0, Y: -25}, 1e-6) {
+		t.Errorf("far ball pushed to %v, want {0 -25}", speed)
+	}
+	if !beyond.GetVelocity().IsZero() {
+		t.Errorf("ball beyond the radius pushed to %v", beyond.GetVelocity())
+	}
+}
+
+func TestDragEffector(t *testing.T) {
+	world, region := newEffectorWorld(2000, 200)
+	inside := addBall(world, Vector2D{}, 5)
+	outside := addBall(world, Vector2D{X: 0, Y: 500}, 5)
+	for _, b := range []*Body{inside, outside} {
+		b.SetVelocity(Vector2D{X: 100, Y: 0})
+		b.SetAngularVelocity(3)
+	}
+	world.AddEffector(region, &DragEffector{LinearDrag: 2, AngularDrag: 2})
+
+	stepFor(world, 1)
+	// A drag of 2 for one second leaves about e⁻² of the speed.
+	if speed := inside.GetVelocity().X; speed < 10 || speed > 17 {
+		t.Errorf("dragged ball moves at %v after a second, want about 13.5", speed)
+	}
+	if spin := inside.GetAngularVelocity(); spin < 0.3 || spin > 0.5 {
+		t.Errorf("dragged ball spins at %v after a second, want about 0.4", spin)
+	}
+	if outside.GetVelocity().X != 100 {
+		t.Errorf("ball outside the region slowed to %v", outside.GetVelocity().X)
+	}
+}
+
+func TestBuoyancyEffector(t *testing.T) {
+	world := NewWorld()
+	world.SetSleepingEnabled(false)
+	// Water 400 deep with its surface at y=0.
+	water := body.FromRectangle(Vector2D{X: 0, Y: 200}, 1000, 400)
+	water.SetIsStatic(true)
+	world.AddBody(water)
+	effector := NewWater()
+	world.AddEffector(water, effector)
+
+	cork := body.FromRectangle(Vector2D{X: -100, Y: -50}, 20, 20)
+	cork.SetDensity(effector.Density / 2)
+	stone := body.FromRectangle(Vector2D{X: 100, Y: -50}, 20, 20)
+	stone.SetDensity(effector.Density * 2)
+	world.AddBody(cork)
+	world.AddBody(stone)
+
+	stepFor(world, 10)
+	// Half as dense as the water, the cork floats half under.
+	if y := cork.GetPosition().Y; math.Abs(y) > 1 {
+		t.Errorf("cork floats at y=%v, want its centre on the surface", y)
+	}
+	if y := stone.GetPosition().Y; y < 100 {
+		t.Errorf("stone is at y=%v, want it sunk", y)
+	}
+}
+
+func TestRemoveEffector(t *testing.T) {
+	world, region := newEffectorWorld(200, 200)
+	ball := addBall(world, Vector2D{}, 5)
+	id := world.AddEffector(region, &DirectionalEffector{Force: Vector2D{X: 100, Y: 0}, Acceleration: true})
+	world.Step(1.0 / 60)
+	world.RemoveEffector(id)
+
+	speed := ball.GetVelocity()
+	stepFor(world, 1)
+	if ball.GetVelocity() != speed {
+		t.Errorf("ball sped up from %v to %v after the effector was removed", speed, ball.GetVelocity())
+	}
+}
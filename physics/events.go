@@ -0,0 +1,115 @@
+package physics
+
+import (
+	"2d_game_engine/physics/collision"
+)
+
+//-----------------------------------------------------------------------------
+// Collision Events
+//-----------------------------------------------------------------------------
+
+// CollisionEventType tells whether a pair started, kept or stopped touching.
+type CollisionEventType int
+
+const (
+	CollisionStart CollisionEventType = iota
+	CollisionStay
+	CollisionEnd
+)
+
+// CollisionEvent describes a pair of touching bodies. The normal points from
+// BodyA towards BodyB. End events carry the last known contact data.
+type CollisionEvent struct {
+	Type     CollisionEventType
+	BodyA    *Body
+	BodyB    *Body
+	Normal   Vector2D
+	Depth    float64
+	Points   []collision.ContactPoint
+	IsSensor bool // True when either body is a sensor
+}
+
+// CollisionHandler is called for every collision event of the type it was
+// subscribed to.
+type CollisionHandler func(event CollisionEvent)
+
+type collisionSubscription struct {
+	id        int
+	eventType CollisionEventType
+	handler   CollisionHandler
+}
+
+// Subscribe registers a handler for one type of collision event and returns
+// an ID that can be passed to Unsubscribe. Handlers run at the end of Step,
+// in the order they were subscribed.
+func (w *World) Subscribe(eventType CollisionEventType, handler CollisionHandler) int {
+	w.nextSubscriptionID++
+	w.subscriptions = append(w.subscriptions, collisionSubscription{
+		id:        w.nextSubscriptionID,
+		eventType: eventType,
+		handler:   handler,
+	})
+	return w.nextSubscriptionID
+}
+
+// Unsubscribe removes a handler registered with Subscribe.
+func (w *World) Unsubscribe(id int) {
+	for i, subscription := range w.subscriptions {
+		if subscription.id == id {
+			w.subscriptions = append(w.subscriptions[:i], w.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// dispatchCollisionEvents compares the contacts of this step with those of
+// the previous one and notifies subscribers.
+func (w *World) dispatchCollisionEvents(previous []*contact, previousMap map[pairKey]*contact) {
+	if len(w.subscriptions) == 0 {
+		return
+	}
+
+	events := make([]CollisionEvent, 0, len(w.contacts)+len(previous))
+	for _, c := range w.contacts {
+		eventType := CollisionStart
+		if _, ok := previousMap[newPairKey(c.bodyA, c.bodyB)]; ok {
+			eventType = CollisionStay
+		}
+		events = append(events, c.event(eventType))
+	}
+	for _, c := range previous {
+		if _, ok := w.contactMap[newPairKey(c.bodyA, c.bodyB)]; !ok {
+			events = append(events, c.event(CollisionEnd))
+		}
+	}
+
+	// Copy the subscriptions so handlers may subscribe or unsubscribe.
+	subscriptions := append([]collisionSubscription(nil), w.subscriptions...)
+	for _, event := range events {
+		for _, subscription := range subscriptions {
+			if subscription.eventType == event.Type {
+				subscription.handler(event)
+			}
+		}
+	}
+}
+
+func (c *contact) event(eventType CollisionEventType) CollisionEvent {
+	points := make([]collision.ContactPoint, len(c.points))
+	depth := 0.0
+	for i, cp := range c.points {
+		points[i] = collision.ContactPoint{Point: cp.point, Depth: cp.depth}
+		if cp.depth > depth {
+			depth = cp.depth
+		}
+	}
+	return CollisionEvent{
+		Type:     eventType,
+		BodyA:    c.bodyA,
+		BodyB:    c.bodyB,
+		Normal:   c.normal,
+		Depth:    depth,
+		Points:   points,
+		IsSensor: c.sensor,
+	}
+}
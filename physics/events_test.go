@@ -0,0 +1,111 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics/body"
+)
+
+// eventLog counts the events of each type and keeps the last one.
+type eventLog struct {
+	counts map[CollisionEventType]int
+	last   map[CollisionEventType]CollisionEvent
+}
+
+func logEvents(world *World) *eventLog {
+	log := &eventLog{counts: make(map[CollisionEventType]int), last: make(map[CollisionEventType]CollisionEvent)}
+	for _, eventType := range []CollisionEventType{CollisionStart, CollisionStay, CollisionEnd} {
+		world.Subscribe(eventType, func(event CollisionEvent) {
+			log.counts[event.Type]++
+			log.last[event.Type] = event
+		})
+	}
+	return log
+}
+
+func TestSensorReportsWithoutResponse(t *testing.T) {
+	world := NewWorld()
+	world.SetSleepingEnabled(false)
+	sensor := body.FromRectangle(Vector2D{X: 0, Y: 100}, 200, 40)
+	sensor.SetIsStatic(true)
+	sensor.SetIsSensor(true)
+	world.AddBody(sensor)
+	ball := body.FromCircle(Vector2D{X: 0, Y: 0}, 5)
+	ball.SetFrictionAir(0)
+	world.AddBody(ball)
+	log := logEvents(world)
+
+	// The ball falls straight through: 1.5 s takes it about 1100 pixels.
+	stepFor(world, 1.5)
+	if log.counts[CollisionStart] != 1 || log.counts[CollisionEnd] != 1 {
+		t.Errorf("got %d start and %d end events, want one of each", log.counts[CollisionStart], log.counts[CollisionEnd])
+	}
+	if log.counts[CollisionStay] == 0 {
+		t.Errorf("got no stay events while the ball was inside the sensor")
+	}
+	if event := log.last[CollisionStart]; !event.IsSensor {
+		t.Errorf("start event is not marked as a sensor event")
+	}
+	if got, want := ball.GetVelocity().Y, DefaultGravity.Y*1.5; math.Abs(got-want) > integrationTolerance() {
+		t.Errorf("ball falls at %v after the sensor, want %v", got, want)
+	}
+}
+
+func TestContactEvents(t *testing.T) {
+	world := NewWorld()
+	floor := body.FromRectangle(Vector2D{X: 0, Y: 20}, 400, 40)
+	floor.SetIsStatic(true)
+	world.AddBody(floor)
+	box := body.FromRectangle(Vector2D{X: 0, Y: -30}, 20, 20)
+	world.AddBody(box)
+	log := logEvents(world)
+
+	stepFor(world, 2)
+	if log.counts[CollisionStart] != 1 || log.counts[CollisionEnd] != 0 {
+		t.Fatalf("got %d start and %d end events landing, want 1 and 0", log.counts[CollisionStart], log.counts[CollisionEnd])
+	}
+	start := log.last[CollisionStart]
+	if start.IsSensor || len(start.Points) == 0 || start.Depth <= 0 {
+		t.Errorf("start event = %+v, want a solid contact with points", start)
+	}
+	// The normal points from body A to body B.
+	if direction := start.BodyB.GetPosition().Subtract(start.BodyA.GetPosition()); start.Normal.Dot(direction) <= 0 {
+		t.Errorf("normal %v points from B to A", start.Normal)
+	}
+
+	// Resting bodies keep reporting, asleep or not.
+	stays := log.counts[CollisionStay]
+	world.Step(1.0 / 60)
+	if log.counts[CollisionStay] != stays+1 {
+		t.Errorf("resting box raised %d stay events in a step, want 1", log.counts[CollisionStay]-stays)
+	}
+
+	// Lifting the box ends the contact.
+	box.SetPosition(Vector2D{X: 0, Y: -200})
+	box.SetIsSleeping(false)
+	world.Step(1.0 / 60)
+	if log.counts[CollisionEnd] != 1 {
+		t.Errorf("lifting the box raised %d end events, want 1", log.counts[CollisionEnd])
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	world := NewWorld()
+	floor := body.FromRectangle(Vector2D{X: 0, Y: 20}, 400, 40)
+	floor.SetIsStatic(true)
+	world.AddBody(floor)
+	world.AddBody(body.FromRectangle(Vector2D{X: 0, Y: -9}, 20, 20))
+
+	calls := 0
+	var id int
+	id = world.Subscribe(CollisionStay, func(CollisionEvent) {
+		calls++
+		// Handlers may unsubscribe themselves.
+		world.Unsubscribe(id)
+	})
+	stepFor(world, 0.5)
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want once before unsubscribing", calls)
+	}
+}
@@ -0,0 +1,351 @@
+// Package fixed provides Q32.32 fixed-point scalars and vectors. Unlike
+// float64, every operation is exact integer arithmetic, so results are the
+// same on every architecture and compiler; this is what lockstep multiplayer
+// and replays need.
+package fixed
+
+import (
+	"math"
+	"math/bits"
+)
+
+// Fixed is a signed Q32.32 number: 32 integer bits and 32 fractional bits,
+// covering about ±2.1e9 with a resolution of about 2.3e-10. Conversions and
+// arithmetic saturate: results too large to represent become Max or Min
+// rather than wrapping round like the underlying int64.
+type Fixed int64
+
+const (
+	// FracBits is the number of fractional bits.
+	FracBits = 32
+
+	One  Fixed = 1 << FracBits
+	Half Fixed = One >> 1
+
+	// Epsilon is the smallest positive value.
+	Epsilon Fixed = 1
+
+	Max Fixed = math.MaxInt64
+	Min Fixed = math.MinInt64
+)
+
+// Pi, its multiples and the natural logarithm of 2, rounded to the nearest
+// representable value.
+var (
+	Pi     = FromFloat(math.Pi)
+	TwoPi  = FromFloat(2 * math.Pi)
+	HalfPi = FromFloat(math.Pi / 2)
+	Ln2    = FromFloat(math.Ln2)
+)
+
+// FromInt converts an integer exactly. Integers out of range saturate to Max
+// or Min.
+func FromInt(i int) Fixed {
+	switch {
+	case int64(i) > math.MaxInt32:
+		return Max
+	case int64(i) < math.MinInt32:
+		return Min
+	}
+	return Fixed(int64(i) << FracBits)
+}
+
+// FromFloat converts a float64, rounding to the nearest representable value.
+// Values out of range saturate to Max or Min and NaN converts to zero. The
+// conversion is exact IEEE arithmetic, so it gives the same result on every
+// platform.
+func FromFloat(f float64) Fixed {
+	scaled := math.Round(f * float64(One))
+	switch {
+	case math.IsNaN(scaled):
+		return 0
+	case scaled >= float64(Max):
+		// float64(Max) rounds up to 2⁶³, which is already out of range.
+		return Max
+	case scaled <= float64(Min):
+		return Min
+	}
+	return Fixed(scaled)
+}
+
+// FromRatio returns numerator / denominator.
+func FromRatio(numerator, denominator int) Fixed {
+	return FromInt(numerator).Div(FromInt(denominator))
+}
+
+// Float converts to the nearest float64.
+func (f Fixed) Float() float64 {
+	return float64(f) / float64(One)
+}
+
+// Int returns the integer part, rounding towards negative infinity.
+func (f Fixed) Int() int {
+	return int(f >> FracBits)
+}
+
+// Floor rounds towards negative infinity.
+func (f Fixed) Floor() Fixed {
+	return f &^ (One - 1)
+}
+
+// Ceil rounds towards positive infinity.
+func (f Fixed) Ceil() Fixed {
+	return f.Add(One - 1).Floor()
+}
+
+// Round rounds to the nearest integer, halves away from zero.
+func (f Fixed) Round() Fixed {
+	if f < 0 {
+		return f.Neg().Add(Half).Floor().Neg()
+	}
+	return f.Add(Half).Floor()
+}
+
+func (f Fixed) Add(other Fixed) Fixed {
+	sum := f + other
+	// Overflow gives a sum whose sign differs from both operands'.
+	if (f < 0) == (other < 0) && (sum < 0) != (f < 0) {
+		return saturate(f < 0)
+	}
+	return sum
+}
+
+func (f Fixed) Sub(other Fixed) Fixed {
+	difference := f - other
+	if (f < 0) != (other < 0) && (difference < 0) != (f < 0) {
+		return saturate(f < 0)
+	}
+	return difference
+}
+
+func (f Fixed) Neg() Fixed {
+	if f == Min {
+		return Max
+	}
+	return -f
+}
+
+func (f Fixed) Abs() Fixed {
+	if f < 0 {
+		return f.Neg()
+	}
+	return f
+}
+
+// magnitude returns |f| as an unsigned integer, which holds |Min| exactly.
+func (f Fixed) magnitude() uint64 {
+	if f < 0 {
+		return -uint64(f)
+	}
+	return uint64(f)
+}
+
+// saturate returns Min for negative overflow and Max otherwise.
+func saturate(negative bool) Fixed {
+	if negative {
+		return Min
+	}
+	return Max
+}
+
+// signed applies a sign to a magnitude, saturating when it is out of range.
+func signed(magnitude uint64, negative bool) Fixed {
+	if negative {
+		if magnitude > 1<<63 {
+			return Min
+		}
+		return Fixed(-magnitude)
+	}
+	if magnitude > math.MaxInt64 {
+		return Max
+	}
+	return Fixed(magnitude)
+}
+
+// Mul returns f * other, rounded to nearest. Products too large to represent
+// saturate to Max or Min.
+func (f Fixed) Mul(other Fixed) Fixed {
+	negative := (f < 0) != (other < 0)
+	hi, lo := bits.Mul64(f.magnitude(), other.magnitude())
+
+	// Round, then drop the extra fractional bits.
+	lo, carry := bits.Add64(lo, 1<<(FracBits-1), 0)
+	hi += carry
+	if hi>>(64-FracBits) != 0 {
+		return saturate(negative)
+	}
+	return signed(hi<<(64-FracBits)|lo>>FracBits, negative)
+}
+
+// Div returns f / other, truncated towards zero. Results too large to
+// represent saturate to Max or Min. Like integer division, it panics when
+// other is zero.
+func (f Fixed) Div(other Fixed) Fixed {
+	if other == 0 {
+		panic("fixed: division by zero")
+	}
+	negative := (f < 0) != (other < 0)
+	numerator := f.magnitude()
+	denominator := other.magnitude()
+
+	hi, lo := numerator>>(64-FracBits), numerator<<FracBits
+	if hi >= denominator {
+		return saturate(negative)
+	}
+	quotient, _ := bits.Div64(hi, lo, denominator)
+	return signed(quotient, negative)
+}
+
+// Sqrt returns the square root, rounded down. Negative values return 0.
+func (f Fixed) Sqrt() Fixed {
+	if f <= 0 {
+		return 0
+	}
+
+	// Take the integer square root of f * 2^32. The float estimate is only a
+	// starting point; the corrections make the result exact.
+	hi, lo := uint64(f)>>(64-FracBits), uint64(f)<<FracBits
+	root := uint64(math.Sqrt(float64(f) * float64(One)))
+	for root > 0 && squareGreater(root, hi, lo) {
+		root--
+	}
+	for !squareGreater(root+1, hi, lo) {
+		root++
+	}
+	return Fixed(root)
+}
+
+// squareGreater reports whether root² > hi:lo as 128-bit integers.
+func squareGreater(root, hi, lo uint64) bool {
+	squareHi, squareLo := bits.Mul64(root, root)
+	return squareHi > hi || (squareHi == hi && squareLo > lo)
+}
+
+// Min returns the smaller of f and other.
+func (f Fixed) Min(other Fixed) Fixed {
+	if other < f {
+		return other
+	}
+	return f
+}
+
+// Max returns the larger of f and other.
+func (f Fixed) Max(other Fixed) Fixed {
+	if other > f {
+		return other
+	}
+	return f
+}
+
+// Clamp limits f to [low, high].
+func (f Fixed) Clamp(low, high Fixed) Fixed {
+	return f.Max(low).Min(high)
+}
+
+// Sin returns the sine of an angle in radians, accurate to about 1e-9.
+func Sin(angle Fixed) Fixed {
+	// Reduce to [-π, π], then to [-π/2, π/2] where the series converges fast.
+	angle %= TwoPi
+	if angle > Pi {
+		angle -= TwoPi
+	} else if angle < -Pi {
+		angle += TwoPi
+	}
+	if angle > HalfPi {
+		angle = Pi - angle
+	} else if angle < -HalfPi {
+		angle = -Pi - angle
+	}
+
+	// Taylor series: x - x³/3! + x⁵/5! - ...
+	squared := angle.Mul(angle)
+	term := angle
+	sum := angle
+	for n := 1; n <= 8; n++ {
+		term = term.Mul(squared).Div(FromInt((2 * n) * (2*n + 1))).Neg()
+		sum += term
+	}
+	return sum
+}
+
+// Cos returns the cosine of an angle in radians.
+func Cos(angle Fixed) Fixed {
+	return Sin(angle + HalfPi)
+}
+
+// Radians converts an angle in degrees to radians, like geometry.Radians.
+func Radians(degrees Fixed) Fixed {
+	return degrees.Mul(Pi).Div(FromInt(180))
+}
+
+// Degrees converts an angle in radians to degrees, like geometry.Degrees.
+func Degrees(radians Fixed) Fixed {
+	return radians.Mul(FromInt(180)).Div(Pi)
+}
+
+// Exp returns e raised to the power x, accurate to about 1e-9 relative.
+// Results too large to represent saturate to Max.
+func Exp(x Fixed) Fixed {
+	// Split x into k·ln2 + r with r in [0, ln2), so that e^x = 2^k · e^r.
+	k := x.Div(Ln2).Floor()
+	r := x.Sub(k.Mul(Ln2))
+
+	// Taylor series: 1 + r + r²/2! + ...
+	term, sum := One, One
+	for n := 1; n <= 14; n++ {
+		term = term.Mul(r).Div(FromInt(n))
+		sum = sum.Add(term)
+	}
+
+	shift := k.Int()
+	switch {
+	case shift >= 64-FracBits-1:
+		return Max
+	case shift <= -64:
+		return 0
+	case shift < 0:
+		return sum >> -shift
+	case sum > Max>>shift:
+		return Max
+	}
+	return sum << shift
+}
+
+// Log returns the natural logarithm of x, accurate to about 1e-9. Values that
+// are not positive return Min.
+func Log(x Fixed) Fixed {
+	if x <= 0 {
+		return Min
+	}
+
+	// Scale x by a power of two into m in [1, 2), so that ln x = k·ln2 + ln m.
+	k := bits.Len64(uint64(x)) - 1 - FracBits
+	m := x
+	if k > 0 {
+		m >>= k
+	} else {
+		m <<= -k
+	}
+
+	// ln m = 2·atanh(s) = 2(s + s³/3 + s⁵/5 + ...) with s = (m-1)/(m+1) < 1/3.
+	s := (m - One).Div(m + One)
+	squared := s.Mul(s)
+	term, sum := s, s
+	for n := 3; n <= 21; n += 2 {
+		term = term.Mul(squared)
+		sum = sum.Add(term.Div(FromInt(n)))
+	}
+	return FromInt(k).Mul(Ln2).Add(sum << 1)
+}
+
+// Pow returns base raised to the power exponent for a positive base. A base
+// that is not positive returns zero, or One for a zero exponent.
+func Pow(base, exponent Fixed) Fixed {
+	if exponent == 0 {
+		return One
+	}
+	if base <= 0 {
+		return 0
+	}
+	return Exp(exponent.Mul(Log(base)))
+}
@@ -0,0 +1,228 @@
+package fixed
+
+import (
+	"math"
+	"math/bits"
+	"testing"
+)
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Fixed
+		want Fixed
+	}{
+		{"one", One, One, One},
+		{"halves", Half, Half, One / 4},
+		{"integers", FromInt(6), FromInt(7), FromInt(42)},
+		{"negative", FromInt(-3), FromInt(2), FromInt(-6)},
+		{"both negative", FromInt(-3), FromInt(-2), FromInt(6)},
+		{"fractions", FromFloat(1.5), FromFloat(-2.5), FromFloat(-3.75)},
+		{"zero", FromInt(12345), 0, 0},
+		{"half an epsilon rounds up", Epsilon, Half, Epsilon},
+		{"negative half an epsilon rounds away", -Epsilon, Half, -Epsilon},
+		{"under half an epsilon rounds down", Epsilon, Half - 1, 0},
+		{"large", FromInt(40000), FromInt(50000), FromInt(2000000000)},
+		{"saturates to max", FromInt(100000), FromInt(100000), Max},
+		{"saturates to min", FromInt(-100000), FromInt(100000), Min},
+		{"both negative saturate to max", FromInt(-100000), FromInt(-100000), Max},
+		{"just out of range", Max, One + Epsilon, Max},
+		{"min by one", Min, One, Min},
+		{"min by minus one", Min, -One, Max},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.a.Mul(test.b); got != test.want {
+				t.Errorf("%v.Mul(%v) = %v, want %v", test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
+
+func TestAddSubSaturate(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Fixed
+		want Fixed
+	}{
+		{"add", FromInt(2).Add(FromInt(3)), FromInt(5)},
+		{"add past max", Max.Add(Epsilon), Max},
+		{"add past min", Min.Add(-Epsilon), Min},
+		{"sub", FromInt(2).Sub(FromInt(3)), -One},
+		{"sub past max", Max.Sub(-One), Max},
+		{"sub past min", Min.Sub(One), Min},
+		{"neg min", Min.Neg(), Max},
+		{"abs min", Min.Abs(), Max},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.got != test.want {
+				t.Errorf("got %v, want %v", test.got, test.want)
+			}
+		})
+	}
+}
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Fixed
+		want Fixed
+	}{
+		{"exact", FromInt(7), FromInt(2), FromFloat(3.5)},
+		{"third truncates", One, FromInt(3), 0x55555555},
+		{"negative third truncates towards zero", -One, FromInt(3), -0x55555555},
+		{"negative divisor", FromInt(8), FromInt(-2), FromInt(-4)},
+		{"both negative", FromInt(-8), FromInt(-2), FromInt(4)},
+		{"by epsilon", Epsilon, Epsilon, One},
+		{"saturates to max", Max, Half, Max},
+		{"saturates to min", FromInt(-1000000000), Epsilon, Min},
+		{"min saturates", Min, Half, Min},
+		{"largest in range", Max, FromInt(2), Max / 2},
+		{"negative saturates after division", -Max, Half, Min},
+		{"zero numerator", 0, FromInt(5), 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.a.Div(test.b); got != test.want {
+				t.Errorf("%v.Div(%v) = %v, want %v", test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
+
+func TestDivByZeroPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Div by zero did not panic")
+		}
+	}()
+	One.Div(0)
+}
+
+func TestSqrt(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Fixed
+		want Fixed
+	}{
+		{"zero", 0, 0},
+		{"negative", FromInt(-4), 0},
+		{"one", One, One},
+		{"four", FromInt(4), FromInt(2)},
+		{"quarter", One / 4, Half},
+		{"two rounds down", FromInt(2), 6074000999},
+		{"epsilon", Epsilon, 65536},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.f.Sqrt(); got != test.want {
+				t.Errorf("%v.Sqrt() = %v, want %v", test.f, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSqrtIsFloorOfRoot(t *testing.T) {
+	// root² <= f·2³² < (root+1)², as 128-bit integers.
+	for _, f := range []Fixed{3, 1 << 20, FromInt(10), FromFloat(123456.789), Max - 1, Max} {
+		root := uint64(f.Sqrt())
+		hi, lo := uint64(f)>>(64-FracBits), uint64(f)<<FracBits
+		if squareGreater(root, hi, lo) || !squareGreater(root+1, hi, lo) {
+			squareHi, squareLo := bits.Mul64(root, root)
+			t.Errorf("Sqrt(%v) = %v: square %x:%x is not the floor of %x:%x", f, root, squareHi, squareLo, hi, lo)
+		}
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		name string
+		f    float64
+		want Fixed
+	}{
+		{"half", 0.5, Half},
+		{"negative", -2.25, -FromInt(2) - One/4},
+		{"rounds to nearest", 1.6 / float64(One), 2},
+		{"halves round away from zero", -1.5 / float64(One), -2},
+		{"NaN", math.NaN(), 0},
+		{"positive infinity", math.Inf(1), Max},
+		{"negative infinity", math.Inf(-1), Min},
+		{"too large", 1e30, Max},
+		{"too small", -1e30, Min},
+		{"smallest", -math.Ldexp(1, 31), Min},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := FromFloat(test.f); got != test.want {
+				t.Errorf("FromFloat(%v) = %v, want %v", test.f, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSinCos(t *testing.T) {
+	for _, angle := range []float64{0, 0.5, 1, math.Pi / 2, 2, math.Pi, -1, -3, 10, -10} {
+		if got, want := Sin(FromFloat(angle)).Float(), math.Sin(angle); math.Abs(got-want) > 1e-8 {
+			t.Errorf("Sin(%v) = %v, want %v", angle, got, want)
+		}
+		if got, want := Cos(FromFloat(angle)).Float(), math.Cos(angle); math.Abs(got-want) > 1e-8 {
+			t.Errorf("Cos(%v) = %v, want %v", angle, got, want)
+		}
+	}
+}
+
+func TestExpLog(t *testing.T) {
+	for _, x := range []float64{-20, -3, -1, -0.5, 0, 0.25, 1, 2.5, 10, 21} {
+		if got, want := Exp(FromFloat(x)).Float(), math.Exp(x); math.Abs(got-want) > 1e-8*math.Max(1, want) {
+			t.Errorf("Exp(%v) = %v, want %v", x, got, want)
+		}
+	}
+	for _, x := range []float64{1e-6, 0.01, 0.5, 1, 2, math.E, 1000, 1e9} {
+		// Compare with the log of the rounded input, which matters for small x.
+		if got, want := Log(FromFloat(x)).Float(), math.Log(FromFloat(x).Float()); math.Abs(got-want) > 1e-8 {
+			t.Errorf("Log(%v) = %v, want %v", x, got, want)
+		}
+	}
+	if Exp(FromInt(30)) != Max || Exp(FromInt(-60)) != 0 {
+		t.Errorf("Exp() does not saturate to Max and underflow to zero")
+	}
+	if Log(0) != Min || Log(-One) != Min {
+		t.Errorf("Log() of a non-positive value is not Min")
+	}
+}
+
+func TestPow(t *testing.T) {
+	tests := []struct {
+		base, exponent, want float64
+	}{
+		{0.99, 1, 0.99},
+		{0.99, 0.5, math.Sqrt(0.99)},
+		{0.99, 2.4, math.Pow(0.99, 2.4)},
+		{2, 10, 1024},
+		{0, 1, 0},
+		{0, 0, 1},
+	}
+	for _, test := range tests {
+		got := Pow(FromFloat(test.base), FromFloat(test.exponent)).Float()
+		if math.Abs(got-test.want) > 1e-8*math.Max(1, test.want) {
+			t.Errorf("Pow(%v, %v) = %v, want %v", test.base, test.exponent, got, test.want)
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	if got := Degrees(Radians(FromInt(33))).Float(); math.Abs(got-33) > 1e-8 {
+		t.Errorf("Degrees(Radians(33)) = %v", got)
+	}
+	// Positive angles turn X towards Y, like geometry.RotationMat2.
+	rotation := NewRotation(FromInt(90))
+	turned := rotation.Apply(Vector{X: FromInt(10)})
+	if math.Abs(turned.X.Float()) > 1e-8 || math.Abs(turned.Y.Float()-10) > 1e-8 {
+		t.Errorf("rotating {10 0} by 90 degrees gives %v", turned.Float())
+	}
+	back := rotation.ApplyInverse(turned)
+	if math.Abs(back.X.Float()-10) > 1e-8 || math.Abs(back.Y.Float()) > 1e-8 {
+		t.Errorf("ApplyInverse(Apply(v)) = %v, want {10 0}", back.Float())
+	}
+}
@@ -0,0 +1,7 @@
+//go:build fixedpoint
+
+package fixed
+
+// Enabled reports whether the engine was built with -tags fixedpoint, which
+// makes the physics step run in fixed-point arithmetic.
+const Enabled = true
@@ -0,0 +1,117 @@
+package fixed
+
+import (
+	"2d_game_engine/physics/geometry"
+)
+
+// Vector mirrors geometry.Vector2D with fixed-point components.
+type Vector struct {
+	X, Y Fixed
+}
+
+// VectorFromFloat converts a geometry.Vector2D, rounding each component.
+func VectorFromFloat(v geometry.Vector2D) Vector {
+	return Vector{X: FromFloat(v.X), Y: FromFloat(v.Y)}
+}
+
+// Float converts back to a geometry.Vector2D.
+func (v Vector) Float() geometry.Vector2D {
+	return geometry.Vector2D{X: v.X.Float(), Y: v.Y.Float()}
+}
+
+// Add returns the sum of two vectors.
+func (v Vector) Add(other Vector) Vector {
+	return Vector{X: v.X.Add(other.X), Y: v.Y.Add(other.Y)}
+}
+
+// Subtract returns the difference of two vectors.
+func (v Vector) Subtract(other Vector) Vector {
+	return Vector{X: v.X.Sub(other.X), Y: v.Y.Sub(other.Y)}
+}
+
+// Multiply scales the vector.
+func (v Vector) Multiply(scalar Fixed) Vector {
+	return Vector{X: v.X.Mul(scalar), Y: v.Y.Mul(scalar)}
+}
+
+// Divide scales the vector by 1/scalar.
+func (v Vector) Divide(scalar Fixed) Vector {
+	return Vector{X: v.X.Div(scalar), Y: v.Y.Div(scalar)}
+}
+
+// Dot returns the dot product of two vectors.
+func (v Vector) Dot(other Vector) Fixed {
+	return v.X.Mul(other.X).Add(v.Y.Mul(other.Y))
+}
+
+// Cross returns the 2D cross product of two vectors.
+func (v Vector) Cross(other Vector) Fixed {
+	return v.X.Mul(other.Y).Sub(v.Y.Mul(other.X))
+}
+
+// LengthSquared returns the squared magnitude of the vector.
+func (v Vector) LengthSquared() Fixed {
+	return v.Dot(v)
+}
+
+// Length returns the magnitude of the vector.
+func (v Vector) Length() Fixed {
+	return v.LengthSquared().Sqrt()
+}
+
+// Normalize returns a unit vector in the same direction, or the zero vector.
+func (v Vector) Normalize() Vector {
+	length := v.Length()
+	if length == 0 {
+		return Vector{}
+	}
+	return v.Divide(length)
+}
+
+// Rotate rotates the vector by an angle in radians.
+func (v Vector) Rotate(angle Fixed) Vector {
+	cos, sin := Cos(angle), Sin(angle)
+	return Vector{
+		X: v.X.Mul(cos).Sub(v.Y.Mul(sin)),
+		Y: v.X.Mul(sin).Add(v.Y.Mul(cos)),
+	}
+}
+
+// Perp returns the vector rotated by 90 degrees, like geometry.Vector2D.Perp.
+func (v Vector) Perp() Vector {
+	return Vector{X: v.Y.Neg(), Y: v.X}
+}
+
+// Negate returns the negated vector.
+func (v Vector) Negate() Vector {
+	return Vector{X: v.X.Neg(), Y: v.Y.Neg()}
+}
+
+// Rotation holds the cosine and sine of an angle, so vectors can be turned
+// by it without evaluating the series again. It mirrors geometry.RotationMat2.
+type Rotation struct {
+	Cos, Sin Fixed
+}
+
+// NewRotation returns the rotation by an angle in degrees. Like body angles,
+// positive angles turn X towards Y.
+func NewRotation(degrees Fixed) Rotation {
+	radians := Radians(degrees)
+	return Rotation{Cos: Cos(radians), Sin: Sin(radians)}
+}
+
+// Apply rotates a vector.
+func (r Rotation) Apply(v Vector) Vector {
+	return Vector{
+		X: v.X.Mul(r.Cos).Sub(v.Y.Mul(r.Sin)),
+		Y: v.X.Mul(r.Sin).Add(v.Y.Mul(r.Cos)),
+	}
+}
+
+// ApplyInverse rotates a vector back by the angle.
+func (r Rotation) ApplyInverse(v Vector) Vector {
+	return Vector{
+		X: v.X.Mul(r.Cos).Add(v.Y.Mul(r.Sin)),
+		Y: v.Y.Mul(r.Cos).Sub(v.X.Mul(r.Sin)),
+	}
+}
@@ -0,0 +1,238 @@
+package geometry
+
+import (
+	"math"
+)
+
+// -----------------------------------------------------------------------------
+// AABB is an axis-aligned bounding box.
+// -----------------------------------------------------------------------------
+type AABB struct {
+	Min Vector2D
+	Max Vector2D
+}
+
+// AABBFromPoints returns the smallest box enclosing the points.
+func AABBFromPoints(points []Vector2D) AABB {
+	if len(points) == 0 {
+		return AABB{}
+	}
+	bounds := AABB{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		bounds = bounds.Include(p)
+	}
+	return bounds
+}
+
+// Include returns the box grown to enclose a point.
+func (a AABB) Include(point Vector2D) AABB {
+	return AABB{
+		Min: Vector2D{X: math.Min(a.Min.X, point.X), Y: math.Min(a.Min.Y, point.Y)},
+		Max: Vector2D{X: math.Max(a.Max.X, point.X), Y: math.Max(a.Max.Y, point.Y)},
+	}
+}
+
+// Center returns the middle of the box.
+func (a AABB) Center() Vector2D {
+	return a.Min.Add(a.Max).Multiply(0.5)
+}
+
+// Size returns the width and height of the box.
+func (a AABB) Size() Vector2D {
+	return a.Max.Subtract(a.Min)
+}
+
+// Extents returns half the size of the box.
+func (a AABB) Extents() Vector2D {
+	return a.Size().Multiply(0.5)
+}
+
+// Area returns the area of the box.
+func (a AABB) Area() float64 {
+	size := a.Size()
+	return size.X * size.Y
+}
+
+// Translate returns the box moved by offset.
+func (a AABB) Translate(offset Vector2D) AABB {
+	return AABB{Min: a.Min.Add(offset), Max: a.Max.Add(offset)}
+}
+
+// ContainsPoint reports whether a point lies inside or on the box.
+func (a AABB) ContainsPoint(point Vector2D) bool {
+	return point.X >= a.Min.X && point.X <= a.Max.X &&
+		point.Y >= a.Min.Y && point.Y <= a.Max.Y
+}
+
+// Overlaps reports whether two boxes intersect. Touching edges count as overlapping.
+func (a AABB) Overlaps(other AABB) bool {
+	return a.Min.X <= other.Max.X && a.Max.X >= other.Min.X &&
+		a.Min.Y <= other.Max.Y && a.Max.Y >= other.Min.Y
+}
+
+// Contains reports whether other lies entirely inside the box.
+func (a AABB) Contains(other AABB) bool {
+	return a.Min.X <= other.Min.X && a.Min.Y <= other.Min.Y &&
+		a.Max.X >= other.Max.X && a.Max.Y >= other.Max.Y
+}
+
+// Union returns the smallest box enclosing both boxes.
+func (a AABB) Union(other AABB) AABB {
+	return AABB{
+		Min: Vector2D{X: math.Min(a.Min.X, other.Min.X), Y: math.Min(a.Min.Y, other.Min.Y)},
+		Max: Vector2D{X: math.Max(a.Max.X, other.Max.X), Y: math.Max(a.Max.Y, other.Max.Y)},
+	}
+}
+
+// Expand grows the box by margin on every side.
+func (a AABB) Expand(margin float64) AABB {
+	return AABB{
+		Min: Vector2D{X: a.Min.X - margin, Y: a.Min.Y - margin},
+		Max: Vector2D{X: a.Max.X + margin, Y: a.Max.Y + margin},
+	}
+}
+
+// Perimeter returns the perimeter of the box, used as a cost metric by trees.
+func (a AABB) Perimeter() float64 {
+	return 2 * ((a.Max.X - a.Min.X) + (a.Max.Y - a.Min.Y))
+}
+
+// RayCast returns the fraction along the segment from -> to at which it enters
+// the box. A segment that starts inside the box enters at 0.
+func (a AABB) RayCast(from, to Vector2D) (float64, bool) {
+	tMin, tMax := 0.0, 1.0
+	direction := to.Subtract(from)
+
+	slabs := [2][4]float64{
+		{from.X, direction.X, a.Min.X, a.Max.X},
+		{from.Y, direction.Y, a.Min.Y, a.Max.Y},
+	}
+	for _, slab := range slabs {
+		origin, delta, low, high := slab[0], slab[1], slab[2], slab[3]
+		if math.Abs(delta) < 1e-12 {
+			// Parallel to the slab: it must already lie between its planes.
+			if origin < low || origin > high {
+				return 0, false
+			}
+			continue
+		}
+
+		t1 := (low - origin) / delta
+		t2 := (high - origin) / delta
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin = math.Max(tMin, t1)
+		tMax = math.Min(tMax, t2)
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
+
+// -----------------------------------------------------------------------------
+// Shape bounds.
+// -----------------------------------------------------------------------------
+
+// Bounded is implemented by shapes that can compute their own bounding box,
+// which is cheaper and tighter than querying support points.
+type Bounded interface {
+	Bounds() AABB
+}
+
+// ShapeBounds returns the bounding box of a shape, using its Bounds method
+// when it has one and its support points otherwise.
+func ShapeBounds(shape Shape) AABB {
+	if bounded, ok := shape.(Bounded); ok {
+		return bounded.Bounds()
+	}
+	return SupportBounds(shape)
+}
+
+// Bounds returns the box enclosing the circle.
+func (c *Circle) Bounds() AABB {
+	radius := Vector2D{X: c.Radius, Y: c.Radius}
+	return AABB{Min: c.Center.Subtract(radius), Max: c.Center.Add(radius)}
+}
+
+// Bounds returns the box enclosing the polygon at its position and rotation.
+func (p *Polygon) Bounds() AABB {
+	return transformedBounds(p.Vertices, p.Position, p.Rotation)
+}
+
+// Bounds returns the box enclosing the triangle at its position and rotation.
+func (t *Triangle) Bounds() AABB {
+	return transformedBounds(t.Vertices, t.Position, t.Rotation)
+}
+
+// Bounds returns the box enclosing the rectangle at its rotation.
+func (r *Rectangle) Bounds() AABB {
+	return transformedBounds(r.Vertices(), r.Position, r.Rotation)
+}
+
+// Bounds returns the box enclosing the segment.
+func (s *Segment) Bounds() AABB {
+	return AABBFromPoints([]Vector2D{s.Start, s.End})
+}
+
+// Bounds returns the box enclosing both caps of the capsule.
+func (c *Capsule) Bounds() AABB {
+	start, end := c.Endpoints()
+	return AABBFromPoints([]Vector2D{start, end}).Expand(c.Radius)
+}
+
+// Bounds returns the tight box around the rotated ellipse.
+func (e *Ellipse) Bounds() AABB {
+	rotation := RotationMat2(e.Rotation)
+	extents := Vector2D{
+		X: math.Hypot(e.RadiusX*rotation.M00, e.RadiusY*rotation.M01),
+		Y: math.Hypot(e.RadiusX*rotation.M10, e.RadiusY*rotation.M11),
+	}
+	return AABB{Min: e.Position.Subtract(extents), Max: e.Position.Add(extents)}
+}
+
+// Bounds returns the union of the children's bounds.
+func (c *Compound) Bounds() AABB {
+	if len(c.Children) == 0 {
+		return AABB{Min: c.Position, Max: c.Position}
+	}
+	bounds := ShapeBounds(c.WorldChild(0))
+	for i := 1; i < len(c.Children); i++ {
+		bounds = bounds.Union(ShapeBounds(c.WorldChild(i)))
+	}
+	return bounds
+}
+
+// transformedBounds returns the bounds of vertices rotated by rotation degrees
+// and offset by position, without allocating the transformed vertices.
+func transformedBounds(vertices []Vector2D, position Vector2D, rotation float64) AABB {
+	if len(vertices) == 0 {
+		return AABB{Min: position, Max: position}
+	}
+
+	matrix := RotationMat2(rotation)
+	bounds := AABB{
+		Min: Vector2D{X: math.Inf(1), Y: math.Inf(1)},
+		Max: Vector2D{X: math.Inf(-1), Y: math.Inf(-1)},
+	}
+	for _, v := range vertices {
+		bounds = bounds.Include(matrix.MulVector(v).Add(position))
+	}
+	return bounds
+}
+
+// SupportBounds computes the bounding box of any shape from its support
+// points along the four axis directions.
+func SupportBounds(shape Shape) AABB {
+	return AABB{
+		Min: Vector2D{
+			X: shape.Support(Vector2D{X: -1, Y: 0}).X,
+			Y: shape.Support(Vector2D{X: 0, Y: -1}).Y,
+		},
+		Max: Vector2D{
+			X: shape.Support(Vector2D{X: 1, Y: 0}).X,
+			Y: shape.Support(Vector2D{X: 0, Y: 1}).Y,
+		},
+	}
+}
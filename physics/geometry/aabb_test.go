@@ -0,0 +1,141 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func nearAABB(a, b AABB) bool {
+	const tolerance = 1e-9
+	return math.Abs(a.Min.X-b.Min.X) <= tolerance && math.Abs(a.Min.Y-b.Min.Y) <= tolerance &&
+		math.Abs(a.Max.X-b.Max.X) <= tolerance && math.Abs(a.Max.Y-b.Max.Y) <= tolerance
+}
+
+func TestShapeBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape Shape
+		want  AABB
+	}{
+		{
+			"circle",
+			&Circle{Center: Vector2D{X: 10, Y: 20}, Radius: 5},
+			AABB{Min: Vector2D{X: 5, Y: 15}, Max: Vector2D{X: 15, Y: 25}},
+		},
+		{
+			"polygon",
+			&Polygon{Vertices: rectangle(0, 0, 10, 20), Position: Vector2D{X: 100, Y: 0}},
+			AABB{Min: Vector2D{X: 100, Y: 0}, Max: Vector2D{X: 110, Y: 20}},
+		},
+		{
+			"triangle turned",
+			&Triangle{Vertices: []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 20}}, Rotation: 90},
+			AABB{Min: Vector2D{X: -20, Y: 0}, Max: Vector2D{X: 0, Y: 10}},
+		},
+		{
+			"rectangle turned",
+			&Rectangle{Position: Vector2D{X: 50, Y: 50}, Width: 40, Height: 10, Rotation: 90},
+			AABB{Min: Vector2D{X: 45, Y: 30}, Max: Vector2D{X: 55, Y: 70}},
+		},
+		{
+			"segment",
+			&Segment{Start: Vector2D{X: 10, Y: -5}, End: Vector2D{X: -10, Y: 5}},
+			AABB{Min: Vector2D{X: -10, Y: -5}, Max: Vector2D{X: 10, Y: 5}},
+		},
+		{
+			"capsule",
+			&Capsule{Position: Vector2D{X: 0, Y: 0}, Length: 40, Radius: 5},
+			AABB{Min: Vector2D{X: -25, Y: -5}, Max: Vector2D{X: 25, Y: 5}},
+		},
+		{
+			"ellipse turned",
+			&Ellipse{Position: Vector2D{X: 0, Y: 0}, RadiusX: 30, RadiusY: 10, Rotation: 90},
+			AABB{Min: Vector2D{X: -10, Y: -30}, Max: Vector2D{X: 10, Y: 30}},
+		},
+		{
+			"compound",
+			&Compound{
+				Position: Vector2D{X: 100, Y: 100},
+				Children: []CompoundChild{
+					{Shape: &Circle{Radius: 5}, Position: Vector2D{X: -20, Y: 0}},
+					{Shape: &Rectangle{Width: 10, Height: 30}, Position: Vector2D{X: 20, Y: 0}},
+				},
+			},
+			AABB{Min: Vector2D{X: 75, Y: 85}, Max: Vector2D{X: 125, Y: 115}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ShapeBounds(test.shape); !nearAABB(got, test.want) {
+				t.Errorf("ShapeBounds() = %v, want %v", got, test.want)
+			}
+			// Every shape's own bounds are as tight as its support points.
+			if got := SupportBounds(test.shape); !nearAABB(got, test.want) {
+				t.Errorf("SupportBounds() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRotatedEllipseBoundsAreTight(t *testing.T) {
+	ellipse := &Ellipse{Position: Vector2D{X: 5, Y: 5}, RadiusX: 30, RadiusY: 10, Rotation: 30}
+	if got, want := ellipse.Bounds(), SupportBounds(ellipse); !nearAABB(got, want) {
+		t.Errorf("Bounds() = %v, want the support bounds %v", got, want)
+	}
+}
+
+func TestAABBSetOperations(t *testing.T) {
+	a := AABB{Min: Vector2D{X: 0, Y: 0}, Max: Vector2D{X: 10, Y: 10}}
+	b := AABB{Min: Vector2D{X: 10, Y: 5}, Max: Vector2D{X: 20, Y: 8}}
+	c := AABB{Min: Vector2D{X: 2, Y: 2}, Max: Vector2D{X: 4, Y: 4}}
+
+	if !a.Overlaps(b) || !b.Overlaps(a) {
+		t.Errorf("boxes sharing an edge do not overlap")
+	}
+	if a.Overlaps(b.Translate(Vector2D{X: 0.1, Y: 0})) {
+		t.Errorf("separated boxes overlap")
+	}
+	if !a.Contains(c) || c.Contains(a) || a.Contains(b) {
+		t.Errorf("Contains() is wrong for nested and overlapping boxes")
+	}
+	if got := a.Union(b); got != (AABB{Min: Vector2D{X: 0, Y: 0}, Max: Vector2D{X: 20, Y: 10}}) {
+		t.Errorf("Union() = %v", got)
+	}
+	if got := c.Expand(2); got != (AABB{Min: Vector2D{X: 0, Y: 0}, Max: Vector2D{X: 6, Y: 6}}) {
+		t.Errorf("Expand(2) = %v", got)
+	}
+	if got := AABBFromPoints([]Vector2D{{X: 3, Y: -1}, {X: -2, Y: 4}, {X: 0, Y: 0}}); got != (AABB{Min: Vector2D{X: -2, Y: -1}, Max: Vector2D{X: 3, Y: 4}}) {
+		t.Errorf("AABBFromPoints() = %v", got)
+	}
+	if b.Center() != (Vector2D{X: 15, Y: 6.5}) || b.Size() != (Vector2D{X: 10, Y: 3}) || b.Area() != 30 || b.Perimeter() != 26 {
+		t.Errorf("measurements of %v are wrong", b)
+	}
+	if !a.ContainsPoint(Vector2D{X: 10, Y: 0}) || a.ContainsPoint(Vector2D{X: 10, Y: -1}) {
+		t.Errorf("ContainsPoint() is wrong on and outside the edge")
+	}
+}
+
+func TestAABBRayCast(t *testing.T) {
+	box := AABB{Min: Vector2D{X: 10, Y: 10}, Max: Vector2D{X: 20, Y: 20}}
+	tests := []struct {
+		name     string
+		from, to Vector2D
+		hit      bool
+		fraction float64
+	}{
+		{"straight in", Vector2D{X: 0, Y: 15}, Vector2D{X: 40, Y: 15}, true, 0.25},
+		{"diagonal", Vector2D{X: 0, Y: 0}, Vector2D{X: 20, Y: 20}, true, 0.5},
+		{"from inside", Vector2D{X: 15, Y: 15}, Vector2D{X: 40, Y: 15}, true, 0},
+		{"parallel outside", Vector2D{X: 0, Y: 5}, Vector2D{X: 40, Y: 5}, false, 0},
+		{"too short", Vector2D{X: 0, Y: 15}, Vector2D{X: 9, Y: 15}, false, 0},
+		{"pointing away", Vector2D{X: 0, Y: 15}, Vector2D{X: -40, Y: 15}, false, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fraction, ok := box.RayCast(test.from, test.to)
+			if ok != test.hit || fraction != test.fraction {
+				t.Errorf("RayCast() = %v, %v; want %v, %v", fraction, ok, test.fraction, test.hit)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package geometry
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// -----------------------------------------------------------------------------
+// Convex hulls and convex decomposition. GJK and EPA only handle convex shapes,
+// so concave outlines are split into convex polygons that can be used as the
+// children of a Compound.
+// -----------------------------------------------------------------------------
+
+// decomposeEpsilon is the cross product below which three points are treated
+// as collinear.
+const decomposeEpsilon = 1e-9
+
+// ErrTriangulationFailed is returned when ear clipping cannot finish an
+// outline that passed validation, which only happens through round-off on
+// nearly degenerate outlines.
+var ErrTriangulationFailed = errors.New("polygon could not be triangulated")
+
+// ConvexHull returns the convex hull of a set of points using Andrew's
+// monotone chain, wound counter-clockwise (in a Y-up frame) with collinear
+// points removed. Fewer than three distinct points are returned as they are.
+func ConvexHull(points []Vector2D) []Vector2D {
+	sorted := make([]Vector2D, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+
+	unique := sorted[:0]
+	for i, p := range sorted {
+		if i == 0 || p != sorted[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+
+	// Build the lower hull left to right, then the upper hull right to left.
+	hull := make([]Vector2D, 0, 2*len(unique))
+	for _, p := range unique {
+		for len(hull) >= 2 && Orientation(hull[len(hull)-2], hull[len(hull)-1], p) <= decomposeEpsilon {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(unique) - 2; i >= 0; i-- {
+		p := unique[i]
+		for len(hull) >= lower && Orientation(hull[len(hull)-2], hull[len(hull)-1], p) <= decomposeEpsilon {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	// The last point repeats the first.
+	return hull[:len(hull)-1]
+}
+
+// Triangulate splits a simple polygon into triangles by ear clipping. The
+// outline may be wound either way; the triangles are wound counter-clockwise
+// (in a Y-up frame). Collinear vertices are skipped. Outlines that are not
+// simple polygons are rejected with the error from ValidatePolygon.
+func Triangulate(vertices []Vector2D) ([]Polygon, error) {
+	pieces, err := triangulateIndices(vertices)
+	if err != nil {
+		return nil, err
+	}
+	triangles := make([]Polygon, 0, len(pieces))
+	for _, indices := range pieces {
+		triangles = append(triangles, polygonFromIndices(vertices, indices))
+	}
+	return triangles, nil
+}
+
+// Decompose splits a simple, possibly concave polygon into convex polygons.
+// It ear-clips the outline into triangles, then merges neighbouring pieces
+// across diagonals while the result stays convex (Hertel–Mehlhorn), which
+// gives at most four times the minimum number of pieces. The polygons keep
+// the input's coordinates and are wound counter-clockwise (in a Y-up frame).
+// Outlines that are not simple polygons are rejected with the error from
+// ValidatePolygon.
+func Decompose(vertices []Vector2D) ([]Polygon, error) {
+	pieces, err := triangulateIndices(vertices)
+	if err != nil {
+		return nil, err
+	}
+
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < len(pieces) && !merged; i++ {
+			for j := i + 1; j < len(pieces) && !merged; j++ {
+				piece, ok := mergePieces(vertices, pieces[i], pieces[j])
+				if !ok {
+					continue
+				}
+				pieces[i] = piece
+				pieces = append(pieces[:j], pieces[j+1:]...)
+				merged = true
+			}
+		}
+	}
+
+	polygons := make([]Polygon, len(pieces))
+	for i, indices := range pieces {
+		polygons[i] = polygonFromIndices(vertices, indices)
+	}
+	return polygons, nil
+}
+
+// NewCompound groups polygons into a compound shape at the origin, keeping
+// each polygon's own position and rotation.
+func NewCompound(polygons []Polygon) *Compound {
+	children := make([]CompoundChild, len(polygons))
+	for i := range polygons {
+		polygon := polygons[i]
+		children[i] = CompoundChild{Shape: &polygon}
+	}
+	return &Compound{Children: children}
+}
+
+// triangulateIndices validates and ear-clips a simple polygon, returning each
+// triangle as counter-clockwise indices into vertices.
+func triangulateIndices(vertices []Vector2D) ([][]int, error) {
+	if err := ValidatePolygon(vertices); err != nil {
+		return nil, err
+	}
+
+	remaining := make([]int, len(vertices))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	if SignedArea(vertices) < 0 {
+		for i, j := 0, len(remaining)-1; i < j; i, j = i+1, j-1 {
+			remaining[i], remaining[j] = remaining[j], remaining[i]
+		}
+	}
+
+	triangles := make([][]int, 0, len(vertices))
+	for len(remaining) > 3 {
+		n := len(remaining)
+		clipped := false
+		for i := 0; i < n; i++ {
+			prev, curr, next := remaining[(i+n-1)%n], remaining[i], remaining[(i+1)%n]
+			cross := Orientation(vertices[prev], vertices[curr], vertices[next])
+			if cross <= decomposeEpsilon && cross >= -decomposeEpsilon {
+				// Collinear vertices add nothing; drop them.
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				clipped = true
+				break
+			}
+			if cross < 0 || !isEar(vertices, remaining, prev, curr, next) {
+				continue
+			}
+			triangles = append(triangles, []int{prev, curr, next})
+			remaining = append(remaining[:i], remaining[i+1:]...)
+			clipped = true
+			break
+		}
+		if !clipped {
+			// No ear left: stop rather than loop or return part of the area.
+			return nil, fmt.Errorf("%w: no ear among %d remaining vertices", ErrTriangulationFailed, n)
+		}
+	}
+	if len(remaining) == 3 && Orientation(vertices[remaining[0]], vertices[remaining[1]], vertices[remaining[2]]) > decomposeEpsilon {
+		triangles = append(triangles, remaining)
+	}
+	return triangles, nil
+}
+
+// isEar reports whether no other remaining vertex lies inside the triangle
+// prev, curr, next.
+func isEar(vertices []Vector2D, remaining []int, prev, curr, next int) bool {
+	a, b, c := vertices[prev], vertices[curr], vertices[next]
+	for _, index := range remaining {
+		if index == prev || index == curr || index == next {
+			continue
+		}
+		p := vertices[index]
+		if p == a || p == b || p == c {
+			continue
+		}
+		if Orientation(a, b, p) >= 0 && Orientation(b, c, p) >= 0 && Orientation(c, a, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// mergePieces joins two counter-clockwise pieces that share an edge, if the
+// result is convex.
+func mergePieces(vertices []Vector2D, pieceA, pieceB []int) ([]int, bool) {
+	for i := range pieceA {
+		a, b := pieceA[i], pieceA[(i+1)%len(pieceA)]
+		for j := range pieceB {
+			if pieceB[j] != b || pieceB[(j+1)%len(pieceB)] != a {
+				continue
+			}
+
+			// Walk pieceA from b round to a, then pieceB from after a back to b.
+			merged := make([]int, 0, len(pieceA)+len(pieceB)-2)
+			for k := 1; k <= len(pieceA); k++ {
+				merged = append(merged, pieceA[(i+k)%len(pieceA)])
+			}
+			for k := 2; k < len(pieceB); k++ {
+				merged = append(merged, pieceB[(j+k)%len(pieceB)])
+			}
+			if !isConvexIndices(vertices, merged) {
+				return nil, false
+			}
+			return merged, true
+		}
+	}
+	return nil, false
+}
+
+// isConvexIndices reports whether a counter-clockwise piece turns left (or
+// goes straight) at every vertex.
+func isConvexIndices(vertices []Vector2D, indices []int) bool {
+	n := len(indices)
+	for i := range indices {
+		a := vertices[indices[(i+n-1)%n]]
+		b := vertices[indices[i]]
+		c := vertices[indices[(i+1)%n]]
+		if Orientation(a, b, c) < -decomposeEpsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// polygonFromIndices copies the indexed vertices into a polygon.
+func polygonFromIndices(vertices []Vector2D, indices []int) Polygon {
+	polygon := Polygon{Vertices: make([]Vector2D, len(indices))}
+	for i, index := range indices {
+		polygon.Vertices[i] = vertices[index]
+	}
+	return polygon
+}
@@ -0,0 +1,96 @@
+package geometry
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// lShape is a concave L, 30 wide and 30 tall with arms 10 thick, area 500.
+var lShape = []Vector2D{{X: 0, Y: 0}, {X: 30, Y: 0}, {X: 30, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 30}, {X: 0, Y: 30}}
+
+func totalArea(polygons []Polygon) float64 {
+	total := 0.0
+	for _, polygon := range polygons {
+		total += Area(polygon.Vertices)
+	}
+	return total
+}
+
+func TestConvexHull(t *testing.T) {
+	points := []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 5}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 5, Y: 0}, {X: 0, Y: 0}}
+	hull := ConvexHull(points)
+	if len(hull) != 4 {
+		t.Fatalf("ConvexHull() = %v, want the four corners", hull)
+	}
+	if !IsCounterClockwise(hull) {
+		t.Errorf("ConvexHull() = %v is not counter-clockwise", hull)
+	}
+	if area := Area(hull); area != 100 {
+		t.Errorf("hull area = %v, want 100", area)
+	}
+}
+
+func TestTriangulate(t *testing.T) {
+	for _, vertices := range [][]Vector2D{lShape, NormalizeWinding(lShape), reversed(lShape)} {
+		triangles, err := Triangulate(vertices)
+		if err != nil {
+			t.Fatalf("Triangulate() error: %v", err)
+		}
+		if len(triangles) != len(vertices)-2 {
+			t.Errorf("got %d triangles, want %d", len(triangles), len(vertices)-2)
+		}
+		if area := totalArea(triangles); math.Abs(area-500) > 1e-9 {
+			t.Errorf("triangles cover %v, want 500", area)
+		}
+	}
+}
+
+func TestDecompose(t *testing.T) {
+	pieces, err := Decompose(lShape)
+	if err != nil {
+		t.Fatalf("Decompose() error: %v", err)
+	}
+	if len(pieces) != 2 {
+		t.Errorf("L shape decomposed into %d pieces, want 2", len(pieces))
+	}
+	for _, piece := range pieces {
+		if !IsConvex(piece.Vertices) {
+			t.Errorf("piece %v is not convex", piece.Vertices)
+		}
+	}
+	if area := totalArea(pieces); math.Abs(area-500) > 1e-9 {
+		t.Errorf("pieces cover %v, want 500", area)
+	}
+}
+
+func TestDecomposeRejectsBadOutlines(t *testing.T) {
+	tests := []struct {
+		name     string
+		vertices []Vector2D
+		want     error
+	}{
+		{"bow tie", []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 10}, {X: 10, Y: 0}, {X: 0, Y: 10}}, ErrSelfIntersecting},
+		{"flat outline folds back", []Vector2D{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 10, Y: 0}}, ErrSelfIntersecting},
+		{"two points", []Vector2D{{X: 0, Y: 0}, {X: 5, Y: 0}}, ErrTooFewVertices},
+		{"repeated vertex", []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}}, ErrDuplicateVertex},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if pieces, err := Decompose(test.vertices); !errors.Is(err, test.want) {
+				t.Errorf("Decompose() = %v, %v; want error %v", pieces, err, test.want)
+			}
+			if _, err := Triangulate(test.vertices); !errors.Is(err, test.want) {
+				t.Errorf("Triangulate() error %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func reversed(vertices []Vector2D) []Vector2D {
+	out := make([]Vector2D, len(vertices))
+	for i, v := range vertices {
+		out[len(vertices)-1-i] = v
+	}
+	return out
+}
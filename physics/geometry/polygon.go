@@ -0,0 +1,113 @@
+package geometry
+
+import (
+	"math"
+)
+
+// -----------------------------------------------------------------------------
+// Polygon mass properties.
+// -----------------------------------------------------------------------------
+
+// SignedArea returns the signed area of a polygon. It is positive when the
+// vertices are wound counter-clockwise (in a Y-up frame).
+func SignedArea(vertices []Vector2D) float64 {
+	area := 0.0
+	for i := range vertices {
+		area += vertices[i].Cross(vertices[(i+1)%len(vertices)])
+	}
+	return area / 2
+}
+
+// Area returns the unsigned area of a polygon.
+func Area(vertices []Vector2D) float64 {
+	return math.Abs(SignedArea(vertices))
+}
+
+// Centroid returns the centre of mass of a polygon of uniform density. For
+// degenerate polygons it falls back to the average of the vertices.
+func Centroid(vertices []Vector2D) Vector2D {
+	signedArea := SignedArea(vertices)
+	if signedArea == 0 {
+		var sum Vector2D
+		for _, v := range vertices {
+			sum = sum.Add(v)
+		}
+		if len(vertices) == 0 {
+			return sum
+		}
+		return sum.Divide(float64(len(vertices)))
+	}
+
+	var centroid Vector2D
+	for i := range vertices {
+		a := vertices[i]
+		b := vertices[(i+1)%len(vertices)]
+		centroid = centroid.Add(a.Add(b).Multiply(a.Cross(b)))
+	}
+	return centroid.Divide(6 * signedArea)
+}
+
+// MomentOfInertia returns the moment of inertia of a polygon of the given
+// mass about the origin of its vertex coordinates.
+func MomentOfInertia(vertices []Vector2D, mass float64) float64 {
+	numerator := 0.0
+	denominator := 0.0
+	for i := range vertices {
+		a := vertices[i]
+		b := vertices[(i+1)%len(vertices)]
+		cross := a.Cross(b)
+		numerator += cross * (a.Dot(a) + a.Dot(b) + b.Dot(b))
+		denominator += cross
+	}
+	if denominator == 0 {
+		return 0
+	}
+	return mass * numerator / (6 * denominator)
+}
+
+// -----------------------------------------------------------------------------
+// Polygon clipping.
+// -----------------------------------------------------------------------------
+
+// ClipPolygon returns the part of subject inside the convex polygon clip,
+// using Sutherland–Hodgman. Either may be wound either way; the result keeps
+// the subject's winding. It is empty when they do not overlap.
+func ClipPolygon(subject, clip []Vector2D) []Vector2D {
+	if len(clip) < 3 {
+		return nil
+	}
+	sign := 1.0
+	if SignedArea(clip) < 0 {
+		sign = -1
+	}
+
+	output := append([]Vector2D(nil), subject...)
+	for i := range clip {
+		if len(output) == 0 {
+			break
+		}
+		a, b := clip[i], clip[(i+1)%len(clip)]
+		// Positive on the inner side of the edge from a to b.
+		side := func(p Vector2D) float64 {
+			return sign * Orientation(a, b, p)
+		}
+
+		input := output
+		output = make([]Vector2D, 0, len(input)+1)
+		for j := range input {
+			current, next := input[j], input[(j+1)%len(input)]
+			currentSide, nextSide := side(current), side(next)
+			if currentSide >= 0 {
+				output = append(output, current)
+			}
+			if (currentSide >= 0) != (nextSide >= 0) {
+				t := currentSide / (currentSide - nextSide)
+				output = append(output, current.Lerp(next, t))
+			}
+		}
+	}
+	if len(output) < 3 {
+		return nil
+	}
+	return output
+}
@@ -0,0 +1,106 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func rectangle(x, y, width, height float64) []Vector2D {
+	return []Vector2D{{X: x, Y: y}, {X: x + width, Y: y}, {X: x + width, Y: y + height}, {X: x, Y: y + height}}
+}
+
+func TestPolygonMassProperties(t *testing.T) {
+	tests := []struct {
+		name       string
+		vertices   []Vector2D
+		signedArea float64
+		centroid   Vector2D
+		// inertia is for a mass of 1 about the origin.
+		inertia float64
+	}{
+		{"centred rectangle", rectangle(-20, -5, 40, 10), 400, Vector2D{}, (40*40 + 10*10) / 12.0},
+		{"offset rectangle", rectangle(10, 0, 40, 10), 400, Vector2D{X: 30, Y: 5}, (40*40+10*10)/12.0 + 30*30 + 5*5},
+		{"clockwise", reversed(rectangle(-20, -5, 40, 10)), -400, Vector2D{}, (40*40 + 10*10) / 12.0},
+		{"right triangle", []Vector2D{{X: 0, Y: 0}, {X: 30, Y: 0}, {X: 0, Y: 30}}, 450, Vector2D{X: 10, Y: 10}, (30*30 + 30*30) / 6.0},
+		// The L is a 30x10 bar from the origin plus a 10x20 bar on top of it.
+		{"L shape", lShape, 500, Vector2D{X: 11, Y: 11}, (100000 + 280000.0/3) / 500},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := SignedArea(test.vertices); math.Abs(got-test.signedArea) > 1e-9 {
+				t.Errorf("SignedArea() = %v, want %v", got, test.signedArea)
+			}
+			if got := Area(test.vertices); math.Abs(got-math.Abs(test.signedArea)) > 1e-9 {
+				t.Errorf("Area() = %v, want %v", got, math.Abs(test.signedArea))
+			}
+			if got := Centroid(test.vertices); got.Subtract(test.centroid).Length() > 1e-9 {
+				t.Errorf("Centroid() = %v, want %v", got, test.centroid)
+			}
+			if got := MomentOfInertia(test.vertices, 1); math.Abs(got-test.inertia) > 1e-9 {
+				t.Errorf("MomentOfInertia() = %v, want %v", got, test.inertia)
+			}
+		})
+	}
+}
+
+func TestMomentOfInertiaScalesWithMass(t *testing.T) {
+	square := rectangle(-5, -5, 10, 10)
+	if got, want := MomentOfInertia(square, 3), 3*MomentOfInertia(square, 1); math.Abs(got-want) > 1e-9 {
+		t.Errorf("MomentOfInertia(mass 3) = %v, want %v", got, want)
+	}
+}
+
+func TestDegeneratePolygonMassProperties(t *testing.T) {
+	line := []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 20, Y: 0}}
+	if got := Area(line); got != 0 {
+		t.Errorf("Area() of a line = %v, want 0", got)
+	}
+	if got := Centroid(line); got != (Vector2D{X: 10, Y: 0}) {
+		t.Errorf("Centroid() of a line = %v, want the vertex average", got)
+	}
+	if got := MomentOfInertia(line, 1); got != 0 {
+		t.Errorf("MomentOfInertia() of a line = %v, want 0", got)
+	}
+	if got := Centroid(nil); got != (Vector2D{}) {
+		t.Errorf("Centroid(nil) = %v, want the origin", got)
+	}
+}
+
+func TestClipPolygon(t *testing.T) {
+	square := rectangle(0, 0, 10, 10)
+	tests := []struct {
+		name     string
+		subject  []Vector2D
+		clip     []Vector2D
+		area     float64
+		centroid Vector2D
+	}{
+		{"overlapping corner", square, rectangle(5, 5, 10, 10), 25, Vector2D{X: 7.5, Y: 7.5}},
+		{"clip wound the other way", square, reversed(rectangle(5, 5, 10, 10)), 25, Vector2D{X: 7.5, Y: 7.5}},
+		{"subject inside", square, rectangle(-5, -5, 20, 20), 100, Vector2D{X: 5, Y: 5}},
+		{"clip inside", rectangle(-5, -5, 20, 20), square, 100, Vector2D{X: 5, Y: 5}},
+		{"half under a surface", square, rectangle(-100, 5, 200, 100), 50, Vector2D{X: 5, Y: 7.5}},
+		{"triangle across an edge", []Vector2D{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 0, Y: 20}}, square, 100, Vector2D{X: 5, Y: 5}},
+		{"disjoint", square, rectangle(20, 0, 10, 10), 0, Vector2D{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clipped := ClipPolygon(test.subject, test.clip)
+			if test.area == 0 {
+				if clipped != nil {
+					t.Errorf("ClipPolygon() = %v, want nil", clipped)
+				}
+				return
+			}
+			if area := Area(clipped); math.Abs(area-test.area) > 1e-9 {
+				t.Errorf("clipped area = %v, want %v", area, test.area)
+			}
+			if centroid := Centroid(clipped); !nearVector(centroid, test.centroid) {
+				t.Errorf("clipped centroid = %v, want %v", centroid, test.centroid)
+			}
+			if (SignedArea(clipped) > 0) != (SignedArea(test.subject) > 0) {
+				t.Errorf("ClipPolygon() changed the subject's winding")
+			}
+		})
+	}
+}
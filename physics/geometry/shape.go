@@ -35,36 +35,13 @@ type Polygon struct {
 
 // Support for a Polygon is the vertex that has the maximum dot product with the direction.
 func (p *Polygon) Support(direction Vector2D) Vector2D {
-    maxDot := math.Inf(-1)
-    var supportPoint Vector2D
-
-    // Convert rotation from degrees to radians.
-    rotationRad := p.Rotation * math.Pi / 180
-    cosTheta := math.Cos(rotationRad)
-    sinTheta := math.Sin(rotationRad)
-
-    // Iterate through all vertices to find the one with the max dot product.
-    for _, v := range p.Vertices {
-        // Apply rotation to the vertex.
-        rotatedX := v.X*cosTheta - v.Y*sinTheta
-        rotatedY := v.X*sinTheta + v.Y*cosTheta
-        rotatedV := Vector2D{X: rotatedX, Y: rotatedY}
-
-        // Calculate the dot product of the rotated vertex with the direction.
-        dotProduct := rotatedV.Dot(direction)
-
-        // If this is the furthest point so far, store it.
-        if dotProduct > maxDot {
-            maxDot = dotProduct
-            supportPoint = rotatedV
-        }
-    }
-
-    // Add the polygon's position to get the absolute world coordinate.
-    return Vector2D{
-        X: supportPoint.X + p.Position.X,
-        Y: supportPoint.Y + p.Position.Y,
-    }
+	return verticesSupport(p.Vertices, NewTransform(p.Position, p.Rotation), direction)
+}
+
+// WorldVertices returns the polygon's vertices rotated and translated into
+// world space.
+func (p *Polygon) WorldVertices() []Vector2D {
+	return NewTransform(p.Position, p.Rotation).ApplyAll(p.Vertices)
 }
 
 type Triangle struct {
@@ -74,40 +51,233 @@ type Triangle struct {
 }
 
 
+// Support for a Triangle is the vertex that has the maximum dot product with the direction.
 func (t *Triangle) Support(direction Vector2D) Vector2D {
-	maxDot := -math.MaxFloat64
-	var supportPoint Vector2D 
-
-	// Convert rotation from degrees to radians.
-	rotationRad := t.Rotation * math.Pi / 180
-	cosTheta := math.Cos(rotationRad)
-	sinTheta := math.Sin(rotationRad)
-
-	// Iterate through all vertices to find the one with the max dot product.
-	for _, v := range t.Vertices {
-		// Apply rotation to the vertex first.
-		rotatedX := v.X*cosTheta - v.Y*sinTheta
-		rotatedY := v.X*sinTheta + v.Y*cosTheta
-		rotatedV := Vector2D{X: rotatedX, Y: rotatedY}
-
-		// Calculate the dot product of the rotated vertex with the direction.
-		dotProduct := rotatedV.Dot(direction)
-
-		// If this is the furthest point so far, store it.
-		if dotProduct > maxDot {
-			maxDot = dotProduct
-			supportPoint = rotatedV
+	return verticesSupport(t.Vertices, NewTransform(t.Position, t.Rotation), direction)
+}
+
+// WorldVertices returns the triangle's vertices rotated and translated into
+// world space.
+func (t *Triangle) WorldVertices() []Vector2D {
+	return NewTransform(t.Position, t.Rotation).ApplyAll(t.Vertices)
+}
+
+// verticesSupport returns the vertex furthest along direction once placed by
+// transform. The direction is taken into local space so only the winner is
+// transformed.
+func verticesSupport(vertices []Vector2D, transform Transform, direction Vector2D) Vector2D {
+	local := transform.ApplyInverseVector(direction)
+	maxDot := math.Inf(-1)
+	var supportPoint Vector2D
+	for _, v := range vertices {
+		if dot := v.Dot(local); dot > maxDot {
+			maxDot = dot
+			supportPoint = v
 		}
 	}
+	return transform.Apply(supportPoint)
+}
+
+// -----------------------------------------------------------------------------
+// Rectangle is a box of the given size centred on its position.
+// -----------------------------------------------------------------------------
+type Rectangle struct {
+	Position Vector2D
+	Width    float64
+	Height   float64
+	Rotation float64 // Angle in degrees 📐
+}
+
+// Box is another name for Rectangle.
+type Box = Rectangle
+
+// Support for a Rectangle is the corner on the side of the direction along
+// each local axis.
+func (r *Rectangle) Support(direction Vector2D) Vector2D {
+	transform := NewTransform(r.Position, r.Rotation)
+	local := transform.ApplyInverseVector(direction)
+	corner := Vector2D{X: r.Width / 2, Y: r.Height / 2}
+	if local.X < 0 {
+		corner.X = -corner.X
+	}
+	if local.Y < 0 {
+		corner.Y = -corner.Y
+	}
+	return transform.Apply(corner)
+}
+
+// Vertices returns the corners of the rectangle relative to its centre,
+// before rotation.
+func (r *Rectangle) Vertices() []Vector2D {
+	halfWidth, halfHeight := r.Width/2, r.Height/2
+	return []Vector2D{
+		{X: -halfWidth, Y: -halfHeight},
+		{X: halfWidth, Y: -halfHeight},
+		{X: halfWidth, Y: halfHeight},
+		{X: -halfWidth, Y: halfHeight},
+	}
+}
+
+// WorldVertices returns the rectangle's corners in world space.
+func (r *Rectangle) WorldVertices() []Vector2D {
+	return NewTransform(r.Position, r.Rotation).ApplyAll(r.Vertices())
+}
+
+// -----------------------------------------------------------------------------
+// Segment is a line segment between two world-space points.
+// -----------------------------------------------------------------------------
+type Segment struct {
+	Start Vector2D
+	End   Vector2D
+}
 
-	// Add the triangle's position to get the absolute world coordinate.
-	return Vector2D{
-		X: supportPoint.X + t.Position.X,
-		Y: supportPoint.Y + t.Position.Y,
+// Support for a Segment is whichever endpoint lies further along the direction.
+func (s *Segment) Support(direction Vector2D) Vector2D {
+	if s.End.Dot(direction) > s.Start.Dot(direction) {
+		return s.End
 	}
+	return s.Start
 }
 
-// GJK is a simplified version for demonstration. A full GJK would handle
-// 3-point simplexes (triangles) to find if the origin is contained.
-// For this example, we'll assume a collision is found after two points.
-// A full implementation requires more complex geometry logic.
+// -----------------------------------------------------------------------------
+// Capsule is a segment along its local X axis rounded by a radius, like a
+// pill. Length is the distance between the centres of the two caps.
+// -----------------------------------------------------------------------------
+type Capsule struct {
+	Position Vector2D
+	Length   float64
+	Radius   float64
+	Rotation float64 // Angle in degrees 📐
+}
+
+// Endpoints returns the centres of the capsule's caps in world space.
+func (c *Capsule) Endpoints() (Vector2D, Vector2D) {
+	transform := NewTransform(c.Position, c.Rotation)
+	return transform.Apply(Vector2D{X: -c.Length / 2}), transform.Apply(Vector2D{X: c.Length / 2})
+}
+
+// Support for a Capsule is the support of its core segment pushed out by the
+// radius.
+func (c *Capsule) Support(direction Vector2D) Vector2D {
+	start, end := c.Endpoints()
+	core := (&Segment{Start: start, End: end}).Support(direction)
+	return core.Add(direction.Normalize().Multiply(c.Radius))
+}
+
+// -----------------------------------------------------------------------------
+// Ellipse has radii along its local X and Y axes.
+// -----------------------------------------------------------------------------
+type Ellipse struct {
+	Position Vector2D
+	RadiusX  float64
+	RadiusY  float64
+	Rotation float64 // Angle in degrees 📐
+}
+
+// Support for an Ellipse is the point where its tangent is perpendicular to
+// the direction: (a²dx, b²dy) / sqrt(a²dx² + b²dy²) in local space.
+func (e *Ellipse) Support(direction Vector2D) Vector2D {
+	transform := NewTransform(e.Position, e.Rotation)
+	local := transform.ApplyInverseVector(direction)
+
+	a2 := e.RadiusX * e.RadiusX
+	b2 := e.RadiusY * e.RadiusY
+	length := math.Sqrt(a2*local.X*local.X + b2*local.Y*local.Y)
+	if length == 0 {
+		return e.Position
+	}
+	point := Vector2D{X: a2 * local.X / length, Y: b2 * local.Y / length}
+	return transform.Apply(point)
+}
+
+// -----------------------------------------------------------------------------
+// TransformedShape places another shape at a position and rotation, treating
+// the wrapped shape's coordinates as local.
+// -----------------------------------------------------------------------------
+type TransformedShape struct {
+	Shape    Shape
+	Position Vector2D
+	Rotation float64 // Angle in degrees 📐
+}
+
+func (t *TransformedShape) Support(direction Vector2D) Vector2D {
+	transform := NewTransform(t.Position, t.Rotation)
+	return transform.Apply(t.Shape.Support(transform.ApplyInverseVector(direction)))
+}
+
+// -----------------------------------------------------------------------------
+// Compound is a group of child shapes, each placed by a transform relative to
+// the compound. Its support function is that of the children's convex hull,
+// so GJK treats a compound as convex; test the world children individually
+// for exact results on concave groups.
+// -----------------------------------------------------------------------------
+type CompoundChild struct {
+	Shape    Shape
+	Position Vector2D // Relative to the compound's position
+	Rotation float64  // Relative to the compound's rotation, in degrees
+}
+
+type Compound struct {
+	Children []CompoundChild
+	Position Vector2D
+	Rotation float64 // Angle in degrees 📐
+}
+
+// WorldChild returns child i placed in world space.
+func (c *Compound) WorldChild(i int) Shape {
+	child := c.Children[i]
+	placement := NewTransform(c.Position, c.Rotation).Mul(NewTransform(child.Position, child.Rotation))
+	return TransformShape(child.Shape, placement)
+}
+
+// WorldChildren returns every child placed in world space.
+func (c *Compound) WorldChildren() []Shape {
+	children := make([]Shape, len(c.Children))
+	for i := range c.Children {
+		children[i] = c.WorldChild(i)
+	}
+	return children
+}
+
+// Support for a Compound is the furthest support point of any child.
+func (c *Compound) Support(direction Vector2D) Vector2D {
+	maxDot := math.Inf(-1)
+	supportPoint := c.Position
+	for i := range c.Children {
+		point := c.WorldChild(i).Support(direction)
+		if dot := point.Dot(direction); dot > maxDot {
+			maxDot = dot
+			supportPoint = point
+		}
+	}
+	return supportPoint
+}
+
+// TransformShape places a shape, given in local coordinates, by a transform.
+// Known shapes keep their concrete type so collision code can specialise on
+// them; anything else is wrapped in a TransformedShape.
+func TransformShape(shape Shape, transform Transform) Shape {
+	place := transform.Apply
+	rotation := transform.Rotation
+
+	switch s := shape.(type) {
+	case *Circle:
+		return &Circle{Center: place(s.Center), Radius: s.Radius}
+	case *Polygon:
+		return &Polygon{Vertices: s.Vertices, Position: place(s.Position), Rotation: rotation + s.Rotation}
+	case *Triangle:
+		return &Triangle{Vertices: s.Vertices, Position: place(s.Position), Rotation: rotation + s.Rotation}
+	case *Rectangle:
+		return &Rectangle{Position: place(s.Position), Width: s.Width, Height: s.Height, Rotation: rotation + s.Rotation}
+	case *Segment:
+		return &Segment{Start: place(s.Start), End: place(s.End)}
+	case *Capsule:
+		return &Capsule{Position: place(s.Position), Length: s.Length, Radius: s.Radius, Rotation: rotation + s.Rotation}
+	case *Ellipse:
+		return &Ellipse{Position: place(s.Position), RadiusX: s.RadiusX, RadiusY: s.RadiusY, Rotation: rotation + s.Rotation}
+	case *Compound:
+		return &Compound{Children: s.Children, Position: place(s.Position), Rotation: rotation + s.Rotation}
+	default:
+		return &TransformedShape{Shape: shape, Position: transform.Position, Rotation: rotation}
+	}
+}
@@ -0,0 +1,89 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func nearVector(a, b Vector2D) bool {
+	return math.Abs(a.X-b.X) <= 1e-9 && math.Abs(a.Y-b.Y) <= 1e-9
+}
+
+func TestSupport(t *testing.T) {
+	right, down, diagonal := Vector2D{X: 1, Y: 0}, Vector2D{X: 0, Y: 1}, Vector2D{X: 1, Y: 1}
+	tests := []struct {
+		name      string
+		shape     Shape
+		direction Vector2D
+		want      Vector2D
+	}{
+		{"circle", &Circle{Center: Vector2D{X: 10, Y: 0}, Radius: 5}, down, Vector2D{X: 10, Y: 5}},
+		{"box", &Box{Position: Vector2D{X: 10, Y: 10}, Width: 20, Height: 10}, diagonal, Vector2D{X: 20, Y: 15}},
+		{"box turned", &Box{Width: 20, Height: 10, Rotation: 90}, diagonal, Vector2D{X: 5, Y: 10}},
+		{"segment", &Segment{Start: Vector2D{X: -5, Y: 3}, End: Vector2D{X: 5, Y: -3}}, right, Vector2D{X: 5, Y: -3}},
+		{"capsule end", &Capsule{Length: 40, Radius: 5}, right, Vector2D{X: 25, Y: 0}},
+		{"capsule corner", &Capsule{Length: 40, Radius: 5}, diagonal, Vector2D{X: 20 + 5/math.Sqrt2, Y: 5 / math.Sqrt2}},
+		{"capsule turned", &Capsule{Position: Vector2D{X: 10, Y: 0}, Length: 40, Radius: 5, Rotation: 90}, down, Vector2D{X: 10, Y: 25}},
+		{"ellipse", &Ellipse{RadiusX: 30, RadiusY: 10}, right, Vector2D{X: 30, Y: 0}},
+		{"ellipse turned", &Ellipse{RadiusX: 30, RadiusY: 10, Rotation: 90}, down, Vector2D{X: 0, Y: 30}},
+		{"ellipse diagonal", &Ellipse{RadiusX: 30, RadiusY: 10}, diagonal, Vector2D{X: 900 / math.Sqrt(1000), Y: 100 / math.Sqrt(1000)}},
+		{
+			"compound",
+			&Compound{
+				Position: Vector2D{X: 100, Y: 0},
+				Rotation: 90,
+				Children: []CompoundChild{
+					{Shape: &Circle{Radius: 5}, Position: Vector2D{X: -20, Y: 0}},
+					{Shape: &Circle{Radius: 5}, Position: Vector2D{X: 20, Y: 0}},
+				},
+			},
+			down, Vector2D{X: 100, Y: 25},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.shape.Support(test.direction); !nearVector(got, test.want) {
+				t.Errorf("Support(%v) = %v, want %v", test.direction, got, test.want)
+			}
+		})
+	}
+}
+
+// dot is a shape the geometry package does not know about.
+type dot struct{ point Vector2D }
+
+func (d *dot) Support(Vector2D) Vector2D { return d.point }
+
+func TestTransformShape(t *testing.T) {
+	transform := NewTransform(Vector2D{X: 50, Y: -20}, 30)
+	shapes := []Shape{
+		&Circle{Center: Vector2D{X: 3, Y: 4}, Radius: 5},
+		&Polygon{Vertices: rectangle(0, 0, 10, 20), Position: Vector2D{X: 1, Y: 2}, Rotation: 10},
+		&Triangle{Vertices: []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 20}}, Rotation: -15},
+		&Box{Position: Vector2D{X: 5, Y: 0}, Width: 20, Height: 10, Rotation: 45},
+		&Segment{Start: Vector2D{X: -5, Y: 3}, End: Vector2D{X: 5, Y: -3}},
+		&Capsule{Position: Vector2D{X: 0, Y: 5}, Length: 40, Radius: 5, Rotation: 20},
+		&Ellipse{RadiusX: 30, RadiusY: 10, Rotation: 60},
+		&Compound{Children: []CompoundChild{{Shape: &Circle{Radius: 5}, Position: Vector2D{X: 20, Y: 0}}}},
+		&dot{point: Vector2D{X: 7, Y: 7}},
+	}
+	directions := []Vector2D{{X: 1, Y: 0}, {X: 0, Y: -1}, {X: -1, Y: 2}}
+
+	for _, shape := range shapes {
+		t.Run(fmt.Sprintf("%T", shape), func(t *testing.T) {
+			placed := TransformShape(shape, transform)
+			// Only unknown shapes are wrapped.
+			_, unknown := shape.(*dot)
+			if _, wrapped := placed.(*TransformedShape); wrapped != unknown {
+				t.Errorf("TransformShape() returned a %T", placed)
+			}
+			for _, direction := range directions {
+				want := transform.Apply(shape.Support(transform.ApplyInverseVector(direction)))
+				if got := placed.Support(direction); !nearVector(got, want) {
+					t.Errorf("Support(%v) = %v, want %v", direction, got, want)
+				}
+			}
+		})
+	}
+}
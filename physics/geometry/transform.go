@@ -0,0 +1,307 @@
+package geometry
+
+import (
+	"math"
+)
+
+// -----------------------------------------------------------------------------
+// Transforms and matrices. Angles are in degrees throughout, like the
+// Rotation fields of the shapes; Radians and Degrees convert at the edges,
+// such as for angular velocities and Vector2D.Rotate.
+// -----------------------------------------------------------------------------
+
+// Radians converts an angle in degrees to radians.
+func Radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// Degrees converts an angle in radians to degrees.
+func Degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+// -----------------------------------------------------------------------------
+// Mat2 is a 2x2 matrix, stored by rows: M01 is row 0, column 1.
+// -----------------------------------------------------------------------------
+type Mat2 struct {
+	M00, M01 float64
+	M10, M11 float64
+}
+
+// IdentityMat2 returns the identity matrix.
+func IdentityMat2() Mat2 {
+	return Mat2{M00: 1, M11: 1}
+}
+
+// RotationMat2 returns the matrix rotating vectors by degrees.
+func RotationMat2(degrees float64) Mat2 {
+	sin, cos := math.Sincos(Radians(degrees))
+	return Mat2{M00: cos, M01: -sin, M10: sin, M11: cos}
+}
+
+// ScaleMat2 returns the matrix scaling X and Y independently.
+func ScaleMat2(scaleX, scaleY float64) Mat2 {
+	return Mat2{M00: scaleX, M11: scaleY}
+}
+
+// Mul returns m * other, which applies other first and then m.
+func (m Mat2) Mul(other Mat2) Mat2 {
+	return Mat2{
+		M00: m.M00*other.M00 + m.M01*other.M10,
+		M01: m.M00*other.M01 + m.M01*other.M11,
+		M10: m.M10*other.M00 + m.M11*other.M10,
+		M11: m.M10*other.M01 + m.M11*other.M11,
+	}
+}
+
+// MulVector returns m * v.
+func (m Mat2) MulVector(v Vector2D) Vector2D {
+	return Vector2D{
+		X: m.M00*v.X + m.M01*v.Y,
+		Y: m.M10*v.X + m.M11*v.Y,
+	}
+}
+
+// Transpose returns the transposed matrix, which is the inverse of a rotation.
+func (m Mat2) Transpose() Mat2 {
+	return Mat2{M00: m.M00, M01: m.M10, M10: m.M01, M11: m.M11}
+}
+
+// Determinant returns the determinant of the matrix.
+func (m Mat2) Determinant() float64 {
+	return m.M00*m.M11 - m.M01*m.M10
+}
+
+// Inverse returns the inverse of the matrix, or false if it is singular.
+func (m Mat2) Inverse() (Mat2, bool) {
+	det := m.Determinant()
+	if det == 0 {
+		return Mat2{}, false
+	}
+	return Mat2{
+		M00: m.M11 / det,
+		M01: -m.M01 / det,
+		M10: -m.M10 / det,
+		M11: m.M00 / det,
+	}, true
+}
+
+// Lerp interpolates each element linearly towards other. Interpolated
+// rotations are not rotations in general; use Transform.Lerp for those.
+func (m Mat2) Lerp(other Mat2, alpha float64) Mat2 {
+	return Mat2{
+		M00: lerp(m.M00, other.M00, alpha),
+		M01: lerp(m.M01, other.M01, alpha),
+		M10: lerp(m.M10, other.M10, alpha),
+		M11: lerp(m.M11, other.M11, alpha),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Mat3 is a 3x3 matrix for affine transforms in homogeneous coordinates,
+// stored by rows. Points have an implicit third coordinate of 1 and vectors
+// one of 0, so only points are translated.
+// -----------------------------------------------------------------------------
+type Mat3 struct {
+	M00, M01, M02 float64
+	M10, M11, M12 float64
+	M20, M21, M22 float64
+}
+
+// IdentityMat3 returns the identity matrix.
+func IdentityMat3() Mat3 {
+	return Mat3{M00: 1, M11: 1, M22: 1}
+}
+
+// NewMat3 returns the affine matrix applying linear and then translation.
+func NewMat3(linear Mat2, translation Vector2D) Mat3 {
+	return Mat3{
+		M00: linear.M00, M01: linear.M01, M02: translation.X,
+		M10: linear.M10, M11: linear.M11, M12: translation.Y,
+		M22: 1,
+	}
+}
+
+// TranslationMat3 returns the matrix translating points by offset.
+func TranslationMat3(offset Vector2D) Mat3 {
+	return NewMat3(IdentityMat2(), offset)
+}
+
+// RotationMat3 returns the matrix rotating about the origin by degrees.
+func RotationMat3(degrees float64) Mat3 {
+	return NewMat3(RotationMat2(degrees), Vector2D{})
+}
+
+// ScaleMat3 returns the matrix scaling about the origin.
+func ScaleMat3(scaleX, scaleY float64) Mat3 {
+	return NewMat3(ScaleMat2(scaleX, scaleY), Vector2D{})
+}
+
+// Mul returns m * other, which applies other first and then m.
+func (m Mat3) Mul(other Mat3) Mat3 {
+	return Mat3{
+		M00: m.M00*other.M00 + m.M01*other.M10 + m.M02*other.M20,
+		M01: m.M00*other.M01 + m.M01*other.M11 + m.M02*other.M21,
+		M02: m.M00*other.M02 + m.M01*other.M12 + m.M02*other.M22,
+		M10: m.M10*other.M00 + m.M11*other.M10 + m.M12*other.M20,
+		M11: m.M10*other.M01 + m.M11*other.M11 + m.M12*other.M21,
+		M12: m.M10*other.M02 + m.M11*other.M12 + m.M12*other.M22,
+		M20: m.M20*other.M00 + m.M21*other.M10 + m.M22*other.M20,
+		M21: m.M20*other.M01 + m.M21*other.M11 + m.M22*other.M21,
+		M22: m.M20*other.M02 + m.M21*other.M12 + m.M22*other.M22,
+	}
+}
+
+// MulPoint transforms a point, including the translation. Projective
+// matrices divide through by the resulting third coordinate.
+func (m Mat3) MulPoint(p Vector2D) Vector2D {
+	x := m.M00*p.X + m.M01*p.Y + m.M02
+	y := m.M10*p.X + m.M11*p.Y + m.M12
+	w := m.M20*p.X + m.M21*p.Y + m.M22
+	if w != 1 && w != 0 {
+		return Vector2D{X: x / w, Y: y / w}
+	}
+	return Vector2D{X: x, Y: y}
+}
+
+// MulVector transforms a direction, ignoring the translation.
+func (m Mat3) MulVector(v Vector2D) Vector2D {
+	return m.Linear().MulVector(v)
+}
+
+// Linear returns the upper-left 2x2 part of the matrix.
+func (m Mat3) Linear() Mat2 {
+	return Mat2{M00: m.M00, M01: m.M01, M10: m.M10, M11: m.M11}
+}
+
+// Translation returns the translation part of an affine matrix.
+func (m Mat3) Translation() Vector2D {
+	return Vector2D{X: m.M02, Y: m.M12}
+}
+
+// Determinant returns the determinant of the matrix.
+func (m Mat3) Determinant() float64 {
+	return m.M00*(m.M11*m.M22-m.M12*m.M21) -
+		m.M01*(m.M10*m.M22-m.M12*m.M20) +
+		m.M02*(m.M10*m.M21-m.M11*m.M20)
+}
+
+// Inverse returns the inverse of the matrix, or false if it is singular.
+func (m Mat3) Inverse() (Mat3, bool) {
+	det := m.Determinant()
+	if det == 0 {
+		return Mat3{}, false
+	}
+	return Mat3{
+		M00: (m.M11*m.M22 - m.M12*m.M21) / det,
+		M01: (m.M02*m.M21 - m.M01*m.M22) / det,
+		M02: (m.M01*m.M12 - m.M02*m.M11) / det,
+		M10: (m.M12*m.M20 - m.M10*m.M22) / det,
+		M11: (m.M00*m.M22 - m.M02*m.M20) / det,
+		M12: (m.M02*m.M10 - m.M00*m.M12) / det,
+		M20: (m.M10*m.M21 - m.M11*m.M20) / det,
+		M21: (m.M01*m.M20 - m.M00*m.M21) / det,
+		M22: (m.M00*m.M11 - m.M01*m.M10) / det,
+	}, true
+}
+
+// Lerp interpolates each element linearly towards other. Use Transform.Lerp
+// to interpolate rigid transforms.
+func (m Mat3) Lerp(other Mat3, alpha float64) Mat3 {
+	return Mat3{
+		M00: lerp(m.M00, other.M00, alpha), M01: lerp(m.M01, other.M01, alpha), M02: lerp(m.M02, other.M02, alpha),
+		M10: lerp(m.M10, other.M10, alpha), M11: lerp(m.M11, other.M11, alpha), M12: lerp(m.M12, other.M12, alpha),
+		M20: lerp(m.M20, other.M20, alpha), M21: lerp(m.M21, other.M21, alpha), M22: lerp(m.M22, other.M22, alpha),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Transform is a rigid placement: a rotation about the origin followed by a
+// translation. It maps a shape's local coordinates into world space.
+// -----------------------------------------------------------------------------
+type Transform struct {
+	Position Vector2D
+	Rotation float64 // Angle in degrees 📐
+}
+
+// NewTransform returns the transform rotating by rotation degrees and then
+// moving to position.
+func NewTransform(position Vector2D, rotation float64) Transform {
+	return Transform{Position: position, Rotation: rotation}
+}
+
+// IdentityTransform returns the transform that leaves points where they are.
+func IdentityTransform() Transform {
+	return Transform{}
+}
+
+// Matrix returns the rotation part of the transform.
+func (t Transform) Matrix() Mat2 {
+	return RotationMat2(t.Rotation)
+}
+
+// Mat3 returns the transform as an affine matrix.
+func (t Transform) Mat3() Mat3 {
+	return NewMat3(t.Matrix(), t.Position)
+}
+
+// Apply maps a local point into world space.
+func (t Transform) Apply(point Vector2D) Vector2D {
+	return t.Matrix().MulVector(point).Add(t.Position)
+}
+
+// ApplyVector rotates a local direction into world space.
+func (t Transform) ApplyVector(v Vector2D) Vector2D {
+	return t.Matrix().MulVector(v)
+}
+
+// ApplyAll maps local points into world space, returning a new slice.
+func (t Transform) ApplyAll(points []Vector2D) []Vector2D {
+	rotation := t.Matrix()
+	world := make([]Vector2D, len(points))
+	for i, p := range points {
+		world[i] = rotation.MulVector(p).Add(t.Position)
+	}
+	return world
+}
+
+// ApplyInverse maps a world point into local space.
+func (t Transform) ApplyInverse(point Vector2D) Vector2D {
+	return t.Matrix().Transpose().MulVector(point.Subtract(t.Position))
+}
+
+// ApplyInverseVector rotates a world direction into local space.
+func (t Transform) ApplyInverseVector(v Vector2D) Vector2D {
+	return t.Matrix().Transpose().MulVector(v)
+}
+
+// Mul returns the transform applying other first and then t, such as placing
+// a child given relative to its parent.
+func (t Transform) Mul(other Transform) Transform {
+	return Transform{
+		Position: t.Apply(other.Position),
+		Rotation: t.Rotation + other.Rotation,
+	}
+}
+
+// Inverse returns the transform undoing t.
+func (t Transform) Inverse() Transform {
+	return Transform{
+		Position: t.ApplyInverseVector(t.Position.Negate()),
+		Rotation: -t.Rotation,
+	}
+}
+
+// Lerp interpolates the position linearly and the rotation along the shorter
+// way round towards other.
+func (t Transform) Lerp(other Transform, alpha float64) Transform {
+	delta := math.Remainder(other.Rotation-t.Rotation, 360)
+	return Transform{
+		Position: t.Position.Lerp(other.Position, alpha),
+		Rotation: t.Rotation + delta*alpha,
+	}
+}
+
+func lerp(a, b, alpha float64) float64 {
+	return a + (b-a)*alpha
+}
@@ -0,0 +1,144 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9
+}
+
+func nearMat3(a, b Mat3) bool {
+	return near(a.M00, b.M00) && near(a.M01, b.M01) && near(a.M02, b.M02) &&
+		near(a.M10, b.M10) && near(a.M11, b.M11) && near(a.M12, b.M12) &&
+		near(a.M20, b.M20) && near(a.M21, b.M21) && near(a.M22, b.M22)
+}
+
+func TestAngleConversion(t *testing.T) {
+	if !near(Radians(180), math.Pi) || !near(Degrees(math.Pi/2), 90) || !near(Degrees(Radians(33)), 33) {
+		t.Errorf("Radians and Degrees do not convert between half turns and pi")
+	}
+}
+
+func TestMat2(t *testing.T) {
+	// Positive angles turn X towards Y, which is clockwise on a Y-down screen.
+	if got := RotationMat2(90).MulVector(Vector2D{X: 1, Y: 0}); !nearVector(got, Vector2D{X: 0, Y: 1}) {
+		t.Errorf("RotationMat2(90) * {1 0} = %v, want {0 1}", got)
+	}
+	scaleThenRotate := RotationMat2(90).Mul(ScaleMat2(2, 3))
+	if got := scaleThenRotate.MulVector(Vector2D{X: 1, Y: 1}); !nearVector(got, Vector2D{X: -3, Y: 2}) {
+		t.Errorf("rotate * scale * {1 1} = %v, want {-3 2}", got)
+	}
+	if !near(scaleThenRotate.Determinant(), 6) {
+		t.Errorf("Determinant() = %v, want 6", scaleThenRotate.Determinant())
+	}
+
+	inverse, ok := scaleThenRotate.Inverse()
+	if !ok {
+		t.Fatalf("Inverse() reported an invertible matrix as singular")
+	}
+	if got := inverse.Mul(scaleThenRotate); !near(got.M00, 1) || !near(got.M01, 0) || !near(got.M10, 0) || !near(got.M11, 1) {
+		t.Errorf("inverse * m = %v, want the identity", got)
+	}
+	rotation := RotationMat2(37)
+	if rotationInverse, _ := rotation.Inverse(); !near(rotationInverse.M01, rotation.Transpose().M01) {
+		t.Errorf("a rotation's inverse is not its transpose")
+	}
+	if _, ok := ScaleMat2(1, 0).Inverse(); ok {
+		t.Errorf("Inverse() of a singular matrix succeeded")
+	}
+	if got := IdentityMat2().Lerp(ScaleMat2(3, 5), 0.5); got != ScaleMat2(2, 3) {
+		t.Errorf("Lerp(0.5) = %v, want a scale of 2 and 3", got)
+	}
+}
+
+func TestMat3(t *testing.T) {
+	m := TranslationMat3(Vector2D{X: 10, Y: 0}).Mul(RotationMat3(90)).Mul(ScaleMat3(2, 2))
+	if got := m.MulPoint(Vector2D{X: 1, Y: 0}); !nearVector(got, Vector2D{X: 10, Y: 2}) {
+		t.Errorf("MulPoint({1 0}) = %v, want {10 2}", got)
+	}
+	if got := m.MulVector(Vector2D{X: 1, Y: 0}); !nearVector(got, Vector2D{X: 0, Y: 2}) {
+		t.Errorf("MulVector({1 0}) = %v, want {0 2} without the translation", got)
+	}
+	if m.Translation() != (Vector2D{X: 10, Y: 0}) || !near(m.Determinant(), 4) {
+		t.Errorf("Translation() = %v, Determinant() = %v", m.Translation(), m.Determinant())
+	}
+
+	inverse, ok := m.Inverse()
+	if !ok {
+		t.Fatalf("Inverse() reported an invertible matrix as singular")
+	}
+	if got := inverse.Mul(m); !nearMat3(got, IdentityMat3()) {
+		t.Errorf("inverse * m = %v, want the identity", got)
+	}
+	if _, ok := ScaleMat3(0, 1).Inverse(); ok {
+		t.Errorf("Inverse() of a singular matrix succeeded")
+	}
+
+	// Projective matrices divide through by w.
+	projective := IdentityMat3()
+	projective.M22 = 2
+	if got := projective.MulPoint(Vector2D{X: 4, Y: 6}); got != (Vector2D{X: 2, Y: 3}) {
+		t.Errorf("projective MulPoint() = %v, want {2 3}", got)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	transform := NewTransform(Vector2D{X: 100, Y: 50}, 90)
+	local := Vector2D{X: 10, Y: 0}
+
+	world := transform.Apply(local)
+	if !nearVector(world, Vector2D{X: 100, Y: 60}) {
+		t.Errorf("Apply(%v) = %v, want {100 60}", local, world)
+	}
+	if got := transform.ApplyInverse(world); !nearVector(got, local) {
+		t.Errorf("ApplyInverse(Apply(p)) = %v, want %v", got, local)
+	}
+	if got := transform.ApplyVector(local); !nearVector(got, Vector2D{X: 0, Y: 10}) {
+		t.Errorf("ApplyVector() = %v, want {0 10}", got)
+	}
+	if got := transform.Mat3().MulPoint(local); !nearVector(got, world) {
+		t.Errorf("Mat3().MulPoint() = %v, want %v", got, world)
+	}
+	if got := transform.ApplyAll([]Vector2D{local, {}}); !nearVector(got[0], world) || got[1] != transform.Position {
+		t.Errorf("ApplyAll() = %v", got)
+	}
+	if got := transform.Inverse().Apply(world); !nearVector(got, local) {
+		t.Errorf("Inverse().Apply() = %v, want %v", got, local)
+	}
+	if got := IdentityTransform().Apply(local); got != local {
+		t.Errorf("IdentityTransform().Apply() = %v", got)
+	}
+
+	// A child 10 to the parent's right, turned a further 90 degrees.
+	child := transform.Mul(NewTransform(local, 90))
+	if !nearVector(child.Position, world) || child.Rotation != 180 {
+		t.Errorf("Mul() = %+v, want the child at %v turned 180", child, world)
+	}
+	if got := child.Apply(local); !nearVector(got, transform.Apply(NewTransform(local, 90).Apply(local))) {
+		t.Errorf("composed transform disagrees with applying both in turn")
+	}
+}
+
+func TestTransformLerp(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to float64
+		want     float64
+	}{
+		{"forward", 0, 90, 45},
+		{"across zero", 350, 10, 360},
+		{"backward across 180", -170, 170, -180},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			from := NewTransform(Vector2D{X: 0, Y: 0}, test.from)
+			to := NewTransform(Vector2D{X: 10, Y: 20}, test.to)
+			got := from.Lerp(to, 0.5)
+			if got.Position != (Vector2D{X: 5, Y: 10}) || !near(got.Rotation, test.want) {
+				t.Errorf("Lerp(0.5) = %+v, want {5 10} at %v degrees", got, test.want)
+			}
+		})
+	}
+}
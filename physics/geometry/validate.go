@@ -0,0 +1,283 @@
+package geometry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// -----------------------------------------------------------------------------
+// Polygon validation and clean-up. Polygon accepts any vertex list, but the
+// collision code expects simple convex outlines and the renderer's scanline
+// fill expects simple ones. These helpers check outlines, normalise their
+// winding and strip or simplify vertices before they are used.
+// -----------------------------------------------------------------------------
+
+// Errors returned by polygon validation. They are wrapped with details, so
+// compare with errors.Is.
+var (
+	ErrTooFewVertices   = errors.New("polygon has fewer than three vertices")
+	ErrDuplicateVertex  = errors.New("polygon has duplicate vertices")
+	ErrDegenerate       = errors.New("polygon has no area")
+	ErrSelfIntersecting = errors.New("polygon is self-intersecting")
+	ErrNotConvex        = errors.New("polygon is not convex")
+)
+
+// ValidatePolygon checks that vertices describe a simple polygon: at least
+// three vertices, no two in a row the same, no crossing edges and a non-zero
+// area. RemoveDuplicates fixes the second.
+func ValidatePolygon(vertices []Vector2D) error {
+	n := len(vertices)
+	if n < 3 {
+		return fmt.Errorf("%w: got %d", ErrTooFewVertices, n)
+	}
+	for i := range vertices {
+		if vertices[i] == vertices[(i+1)%n] {
+			return fmt.Errorf("%w: vertices %d and %d are both %v", ErrDuplicateVertex, i, (i+1)%n, vertices[i])
+		}
+	}
+	if i, j, ok := FindSelfIntersection(vertices); ok {
+		return fmt.Errorf("%w: edge %d crosses edge %d", ErrSelfIntersecting, i, j)
+	}
+	if area := Area(vertices); area <= decomposeEpsilon {
+		return fmt.Errorf("%w: area %g", ErrDegenerate, area)
+	}
+	return nil
+}
+
+// ValidateConvexPolygon checks that vertices describe a simple convex polygon,
+// as needed by the collision code.
+func ValidateConvexPolygon(vertices []Vector2D) error {
+	if err := ValidatePolygon(vertices); err != nil {
+		return err
+	}
+	if i, ok := findReflexVertex(vertices); ok {
+		return fmt.Errorf("%w: reflex vertex %d at %v", ErrNotConvex, i, vertices[i])
+	}
+	return nil
+}
+
+// Validate checks that the polygon is simple and convex.
+func (p *Polygon) Validate() error {
+	return ValidateConvexPolygon(p.Vertices)
+}
+
+// IsConvex reports whether a polygon turns the same way at every vertex.
+// Collinear vertices are allowed; self-intersecting outlines are not convex.
+func IsConvex(vertices []Vector2D) bool {
+	if len(vertices) < 3 {
+		return false
+	}
+	if _, ok := findReflexVertex(vertices); ok {
+		return false
+	}
+	_, _, crossing := FindSelfIntersection(vertices)
+	return !crossing
+}
+
+// findReflexVertex returns a vertex that turns against the polygon's winding.
+func findReflexVertex(vertices []Vector2D) (int, bool) {
+	winding := 1.0
+	if SignedArea(vertices) < 0 {
+		winding = -1
+	}
+	n := len(vertices)
+	for i := range vertices {
+		if Orientation(vertices[(i+n-1)%n], vertices[i], vertices[(i+1)%n])*winding < -decomposeEpsilon {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// FindSelfIntersection returns the first pair of edges that cross or overlap,
+// where edge i runs from vertex i to vertex i+1. Neighbouring edges only count
+// if they fold back over each other. It is O(n²) in the number of vertices.
+func FindSelfIntersection(vertices []Vector2D) (int, int, bool) {
+	n := len(vertices)
+	for i := 0; i < n; i++ {
+		a1, a2 := vertices[i], vertices[(i+1)%n]
+		for j := i + 1; j < n; j++ {
+			b1, b2 := vertices[j], vertices[(j+1)%n]
+			if j == i+1 || (i == 0 && j == n-1) {
+				if foldsBack(a1, a2, b1, b2) {
+					return i, j, true
+				}
+				continue
+			}
+			if segmentsIntersect(a1, a2, b1, b2) {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// IsSelfIntersecting reports whether any two edges of a polygon cross.
+func IsSelfIntersecting(vertices []Vector2D) bool {
+	_, _, ok := FindSelfIntersection(vertices)
+	return ok
+}
+
+// foldsBack reports whether two edges sharing a vertex lie along the same line
+// and overlap.
+func foldsBack(a1, a2, b1, b2 Vector2D) bool {
+	directionA := a2.Subtract(a1)
+	directionB := b2.Subtract(b1)
+	if math.Abs(directionA.Cross(directionB)) > decomposeEpsilon {
+		return false
+	}
+	// Edges that follow on from each other point the same way; folding back
+	// reverses direction.
+	return directionA.Dot(directionB) < 0
+}
+
+// segmentsIntersect reports whether segments a1-a2 and b1-b2 share any point.
+func segmentsIntersect(a1, a2, b1, b2 Vector2D) bool {
+	d1 := Orientation(b1, b2, a1)
+	d2 := Orientation(b1, b2, a2)
+	d3 := Orientation(a1, a2, b1)
+	d4 := Orientation(a1, a2, b2)
+	if ((d1 > decomposeEpsilon && d2 < -decomposeEpsilon) || (d1 < -decomposeEpsilon && d2 > decomposeEpsilon)) &&
+		((d3 > decomposeEpsilon && d4 < -decomposeEpsilon) || (d3 < -decomposeEpsilon && d4 > decomposeEpsilon)) {
+		return true
+	}
+	return (math.Abs(d1) <= decomposeEpsilon && onSegment(b1, b2, a1)) ||
+		(math.Abs(d2) <= decomposeEpsilon && onSegment(b1, b2, a2)) ||
+		(math.Abs(d3) <= decomposeEpsilon && onSegment(a1, a2, b1)) ||
+		(math.Abs(d4) <= decomposeEpsilon && onSegment(a1, a2, b2))
+}
+
+// onSegment reports whether p, known to be collinear with a-b, lies between
+// them.
+func onSegment(a, b, p Vector2D) bool {
+	return p.X >= math.Min(a.X, b.X) && p.X <= math.Max(a.X, b.X) &&
+		p.Y >= math.Min(a.Y, b.Y) && p.Y <= math.Max(a.Y, b.Y)
+}
+
+// IsCounterClockwise reports whether a polygon is wound counter-clockwise (in
+// a Y-up frame), that is whether its signed area is positive.
+func IsCounterClockwise(vertices []Vector2D) bool {
+	return SignedArea(vertices) > 0
+}
+
+// NormalizeWinding returns a copy of the vertices wound counter-clockwise (in
+// a Y-up frame).
+func NormalizeWinding(vertices []Vector2D) []Vector2D {
+	normalized := make([]Vector2D, len(vertices))
+	copy(normalized, vertices)
+	if SignedArea(normalized) < 0 {
+		for i, j := 0, len(normalized)-1; i < j; i, j = i+1, j-1 {
+			normalized[i], normalized[j] = normalized[j], normalized[i]
+		}
+	}
+	return normalized
+}
+
+// RemoveDuplicates returns the vertices without consecutive points closer
+// than tolerance to each other, including the last and first.
+func RemoveDuplicates(vertices []Vector2D, tolerance float64) []Vector2D {
+	cleaned := make([]Vector2D, 0, len(vertices))
+	for _, v := range vertices {
+		if len(cleaned) > 0 && v.Subtract(cleaned[len(cleaned)-1]).Length() <= tolerance {
+			continue
+		}
+		cleaned = append(cleaned, v)
+	}
+	for len(cleaned) > 1 && cleaned[len(cleaned)-1].Subtract(cleaned[0]).Length() <= tolerance {
+		cleaned = cleaned[:len(cleaned)-1]
+	}
+	return cleaned
+}
+
+// RemoveCollinear returns the vertices without those lying within tolerance
+// of the line through their neighbours. Duplicates are removed first.
+func RemoveCollinear(vertices []Vector2D, tolerance float64) []Vector2D {
+	cleaned := RemoveDuplicates(vertices, tolerance)
+	for removed := true; removed && len(cleaned) > 3; {
+		removed = false
+		n := len(cleaned)
+		for i := range cleaned {
+			prev, next := cleaned[(i+n-1)%n], cleaned[(i+1)%n]
+			if distanceToLine(cleaned[i], prev, next) <= tolerance {
+				cleaned = append(cleaned[:i], cleaned[i+1:]...)
+				removed = true
+				break
+			}
+		}
+	}
+	return cleaned
+}
+
+// Simplify reduces a closed outline with the Ramer–Douglas–Peucker algorithm,
+// dropping vertices that lie within tolerance of the simplified outline. The
+// result keeps at least three vertices when the input has them.
+func Simplify(vertices []Vector2D, tolerance float64) []Vector2D {
+	cleaned := RemoveDuplicates(vertices, 0)
+	if len(cleaned) <= 3 {
+		return cleaned
+	}
+
+	// Split the loop at the vertex furthest from the first into two chains.
+	far := 0
+	farthest := -1.0
+	for i, v := range cleaned {
+		if d := v.Subtract(cleaned[0]).LengthSaqured(); d > farthest {
+			far, farthest = i, d
+		}
+	}
+	loop := append(append([]Vector2D{}, cleaned...), cleaned[0])
+	first := simplifyChain(loop[:far+1], tolerance)
+	second := simplifyChain(loop[far:], tolerance)
+	simplified := append(first[:len(first)-1], second[:len(second)-1]...)
+
+	if len(simplified) < 3 {
+		return cleaned
+	}
+	return simplified
+}
+
+// simplifyChain runs Ramer–Douglas–Peucker on an open chain, keeping both ends.
+func simplifyChain(points []Vector2D, tolerance float64) []Vector2D {
+	if len(points) < 3 {
+		return append([]Vector2D{}, points...)
+	}
+
+	start, end := points[0], points[len(points)-1]
+	split := 0
+	maxDistance := -1.0
+	for i := 1; i < len(points)-1; i++ {
+		if d := distanceToSegment(points[i], start, end); d > maxDistance {
+			split, maxDistance = i, d
+		}
+	}
+	if maxDistance <= tolerance {
+		return []Vector2D{start, end}
+	}
+
+	left := simplifyChain(points[:split+1], tolerance)
+	right := simplifyChain(points[split:], tolerance)
+	return append(left[:len(left)-1], right...)
+}
+
+// distanceToLine returns the distance from p to the infinite line through a
+// and b, or to a when they coincide.
+func distanceToLine(p, a, b Vector2D) float64 {
+	direction := b.Subtract(a)
+	length := direction.Length()
+	if length == 0 {
+		return p.Subtract(a).Length()
+	}
+	return math.Abs(direction.Cross(p.Subtract(a))) / length
+}
+
+// distanceToSegment returns the distance from p to the segment a-b.
+func distanceToSegment(p, a, b Vector2D) float64 {
+	direction := b.Subtract(a)
+	lengthSquared := direction.LengthSaqured()
+	if lengthSquared == 0 {
+		return p.Subtract(a).Length()
+	}
+	t := math.Max(0, math.Min(1, p.Subtract(a).Dot(direction)/lengthSquared))
+	return p.Subtract(a.Add(direction.Multiply(t))).Length()
+}
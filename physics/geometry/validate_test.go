@@ -0,0 +1,122 @@
+package geometry
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidatePolygon(t *testing.T) {
+	square := rectangle(0, 0, 10, 10)
+	tests := []struct {
+		name     string
+		vertices []Vector2D
+		simple   error
+		convex   error
+		isConvex bool
+		crossing bool
+	}{
+		{"square", square, nil, nil, true, false},
+		{"square wound the other way", reversed(square), nil, nil, true, false},
+		{"too few", square[:2], ErrTooFewVertices, ErrTooFewVertices, false, false},
+		{"duplicate", []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}}, ErrDuplicateVertex, ErrDuplicateVertex, true, false},
+		{"duplicate wrapping round", []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}, {X: 0, Y: 0}}, ErrDuplicateVertex, ErrDuplicateVertex, true, false},
+		// A flat outline's last edge runs back over its first.
+		{"flat", []Vector2D{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 10, Y: 0}}, ErrSelfIntersecting, ErrSelfIntersecting, false, true},
+		{"bow tie", []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 10}, {X: 10, Y: 0}, {X: 0, Y: 10}}, ErrSelfIntersecting, ErrSelfIntersecting, false, true},
+		{"folds back", []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 0}, {X: 5, Y: 10}}, ErrSelfIntersecting, ErrSelfIntersecting, false, true},
+		{"arrow", []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 5}, {X: 0, Y: 10}, {X: 3, Y: 5}}, nil, ErrNotConvex, false, false},
+		{"collinear vertex", []Vector2D{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}, nil, nil, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := ValidatePolygon(test.vertices); !errors.Is(err, test.simple) {
+				t.Errorf("ValidatePolygon() = %v, want %v", err, test.simple)
+			}
+			if err := ValidateConvexPolygon(test.vertices); !errors.Is(err, test.convex) {
+				t.Errorf("ValidateConvexPolygon() = %v, want %v", err, test.convex)
+			}
+			// Zero-length edges make the shape tests meaningless.
+			if errors.Is(test.simple, ErrTooFewVertices) || errors.Is(test.simple, ErrDuplicateVertex) {
+				return
+			}
+			if got := IsConvex(test.vertices); got != test.isConvex {
+				t.Errorf("IsConvex() = %v, want %v", got, test.isConvex)
+			}
+			if got := IsSelfIntersecting(test.vertices); got != test.crossing {
+				t.Errorf("IsSelfIntersecting() = %v, want %v", got, test.crossing)
+			}
+		})
+	}
+}
+
+func TestPolygonValidate(t *testing.T) {
+	polygon := &Polygon{Vertices: []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 5}, {X: 0, Y: 10}, {X: 3, Y: 5}}}
+	if err := polygon.Validate(); !errors.Is(err, ErrNotConvex) {
+		t.Errorf("Validate() = %v, want ErrNotConvex", err)
+	}
+}
+
+func TestNormalizeWinding(t *testing.T) {
+	clockwise := reversed(rectangle(0, 0, 10, 10))
+	if IsCounterClockwise(clockwise) == IsCounterClockwise(rectangle(0, 0, 10, 10)) {
+		t.Fatalf("a square and its reverse wind the same way")
+	}
+	for _, vertices := range [][]Vector2D{clockwise, reversed(clockwise)} {
+		normalized := NormalizeWinding(vertices)
+		if !IsCounterClockwise(normalized) {
+			t.Errorf("NormalizeWinding(%v) = %v, still clockwise", vertices, normalized)
+		}
+	}
+	if clockwise[0] != (Vector2D{X: 0, Y: 10}) {
+		t.Errorf("NormalizeWinding() changed its argument")
+	}
+}
+
+func TestRemoveDuplicatesAndCollinear(t *testing.T) {
+	vertices := []Vector2D{
+		{X: 0, Y: 0}, {X: 0.001, Y: 0}, {X: 5, Y: 0.001}, {X: 10, Y: 0},
+		{X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0.0005},
+	}
+	if got := RemoveDuplicates(vertices, 0.01); len(got) != 5 {
+		t.Errorf("RemoveDuplicates() = %v, want 5 vertices", got)
+	}
+	got := RemoveCollinear(vertices, 0.01)
+	if len(got) != 4 || ValidateConvexPolygon(got) != nil {
+		t.Errorf("RemoveCollinear() = %v, want the square's four corners", got)
+	}
+	// A triangle is never reduced further.
+	triangle := []Vector2D{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 0.001}}
+	if got := RemoveCollinear(triangle, 0.01); len(got) != 3 {
+		t.Errorf("RemoveCollinear() of a triangle = %v", got)
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	// A 100x100 square with a wobble of at most 0.5 along every edge.
+	outline := make([]Vector2D, 0)
+	for i := 0; i < 10; i++ {
+		wobble := float64(i%2) * 0.5
+		outline = append(outline, Vector2D{X: float64(i) * 10, Y: wobble})
+	}
+	for i := 0; i < 10; i++ {
+		wobble := float64(i%2) * 0.5
+		outline = append(outline, Vector2D{X: 100 - wobble, Y: float64(i) * 10})
+	}
+	for i := 0; i < 10; i++ {
+		outline = append(outline, Vector2D{X: 100 - float64(i)*10, Y: 100})
+	}
+	for i := 0; i < 10; i++ {
+		outline = append(outline, Vector2D{X: 0, Y: 100 - float64(i)*10})
+	}
+
+	if got := Simplify(outline, 1); len(got) != 4 {
+		t.Errorf("Simplify(1) kept %d vertices, want the 4 corners: %v", len(got), got)
+	}
+	if got := Simplify(outline, 0.1); len(got) <= 4 {
+		t.Errorf("Simplify(0.1) kept %d vertices, want the wobbles kept", len(got))
+	}
+	// Everything is within tolerance of a thin sliver, but three vertices stay.
+	if got := Simplify(outline, 1000); len(got) < 3 {
+		t.Errorf("Simplify(1000) kept %d vertices, want at least 3", len(got))
+	}
+}
@@ -42,6 +42,9 @@ func (v Vector2D) Cross(other Vector2D) float64 {
 	return v.X*other.Y - v.Y*other.X
 }
 // Cross3 returns the cross product of three vectors (in 2D, this is a scalar).
+// It ignores the receiver.
+//
+// Deprecated: use Orientation.
 func (v Vector2D) Cross3(vectorA,vectorB,vectorC Vector2D) float64 {
 	return (vectorB.X - vectorA.X) * (vectorC.Y - vectorA.Y) - (vectorB.Y - vectorA.Y) * (vectorC.X - vectorA.X)
 }
@@ -85,7 +88,156 @@ func (v Vector2D) Negate() Vector2D {
 	return Vector2D{X: -v.X, Y: -v.Y}
 }
 
-// Angle returns the angle in radians between two vectors.
+// Angle returns the angle in radians of the direction from vectorA to
+// vectorB. It ignores the receiver.
+//
+// Deprecated: use vectorB.Subtract(vectorA).Heading().
 func (v Vector2D) Angle(vectorA,vectorB  Vector2D) float64 {
 	return math.Atan2(vectorB.Y-vectorA.Y, vectorB.X-vectorA.X)
-}
\ No newline at end of file
+}
+
+// FromAngle returns the unit vector pointing at an angle in radians.
+func FromAngle(angle float64) Vector2D {
+	sin, cos := math.Sincos(angle)
+	return Vector2D{X: cos, Y: sin}
+}
+
+// Orientation returns the cross product of b - a and c - a: positive when a,
+// b, c turn counter-clockwise (in a Y-up frame), zero when collinear.
+func Orientation(a, b, c Vector2D) float64 {
+	return b.Subtract(a).Cross(c.Subtract(a))
+}
+
+// Heading returns the angle of the vector in radians, measured from the X axis.
+func (v Vector2D) Heading() float64 {
+	return math.Atan2(v.Y, v.X)
+}
+
+// AngleTo returns the signed angle in radians that rotates v onto other, in
+// (-π, π].
+func (v Vector2D) AngleTo(other Vector2D) float64 {
+	return math.Atan2(v.Cross(other), v.Dot(other))
+}
+
+// Distance returns the distance between two points.
+func (v Vector2D) Distance(other Vector2D) float64 {
+	return other.Subtract(v).Length()
+}
+
+// DistanceSquared returns the squared distance between two points.
+func (v Vector2D) DistanceSquared(other Vector2D) float64 {
+	return other.Subtract(v).LengthSaqured()
+}
+
+// Lerp interpolates linearly from v to other; t = 0 gives v and t = 1 other.
+func (v Vector2D) Lerp(other Vector2D, t float64) Vector2D {
+	return Vector2D{X: v.X + (other.X-v.X)*t, Y: v.Y + (other.Y-v.Y)*t}
+}
+
+// Slerp interpolates the direction from v to other along the shorter arc and
+// the length linearly, so unit vectors stay unit vectors.
+func (v Vector2D) Slerp(other Vector2D, t float64) Vector2D {
+	length := v.Length() + (other.Length()-v.Length())*t
+	angle := LerpAngle(v.Heading(), other.Heading(), t)
+	return FromAngle(angle).Multiply(length)
+}
+
+// LerpAngle interpolates between two angles in radians the shorter way round.
+func LerpAngle(from, to, t float64) float64 {
+	return from + math.Remainder(to-from, 2*math.Pi)*t
+}
+
+// Reflect mirrors the vector off a surface with the given unit normal, as a
+// bounce would.
+func (v Vector2D) Reflect(normal Vector2D) Vector2D {
+	return v.Subtract(normal.Multiply(2 * v.Dot(normal)))
+}
+
+// Project returns the component of v along onto.
+func (v Vector2D) Project(onto Vector2D) Vector2D {
+	lengthSquared := onto.LengthSaqured()
+	if lengthSquared == 0 {
+		return Vector2D{}
+	}
+	return onto.Multiply(v.Dot(onto) / lengthSquared)
+}
+
+// Reject returns the component of v perpendicular to onto.
+func (v Vector2D) Reject(onto Vector2D) Vector2D {
+	return v.Subtract(v.Project(onto))
+}
+
+// ClampLength shortens the vector to at most maxLength, keeping its direction.
+func (v Vector2D) ClampLength(maxLength float64) Vector2D {
+	lengthSquared := v.LengthSaqured()
+	if lengthSquared <= maxLength*maxLength {
+		return v
+	}
+	return v.Multiply(maxLength / math.Sqrt(lengthSquared))
+}
+
+// ApproxEqual reports whether both components differ by at most epsilon.
+func (v Vector2D) ApproxEqual(other Vector2D, epsilon float64) bool {
+	return math.Abs(v.X-other.X) <= epsilon && math.Abs(v.Y-other.Y) <= epsilon
+}
+
+// IsZero reports whether both components are exactly zero.
+func (v Vector2D) IsZero() bool {
+	return v.X == 0 && v.Y == 0
+}
+
+// Min returns the component-wise minimum of two vectors.
+func (v Vector2D) Min(other Vector2D) Vector2D {
+	return Vector2D{X: math.Min(v.X, other.X), Y: math.Min(v.Y, other.Y)}
+}
+
+// Max returns the component-wise maximum of two vectors.
+func (v Vector2D) Max(other Vector2D) Vector2D {
+	return Vector2D{X: math.Max(v.X, other.X), Y: math.Max(v.Y, other.Y)}
+}
+
+// Clamp limits each component to the range given by min and max.
+func (v Vector2D) Clamp(min, max Vector2D) Vector2D {
+	return v.Max(min).Min(max)
+}
+
+// MultiplyComponents returns the component-wise product of two vectors.
+func (v Vector2D) MultiplyComponents(other Vector2D) Vector2D {
+	return Vector2D{X: v.X * other.X, Y: v.Y * other.Y}
+}
+
+// DivideComponents returns the component-wise quotient of two vectors.
+func (v Vector2D) DivideComponents(other Vector2D) Vector2D {
+	return Vector2D{X: v.X / other.X, Y: v.Y / other.Y}
+}
+
+// Abs returns the vector with both components made non-negative.
+func (v Vector2D) Abs() Vector2D {
+	return Vector2D{X: math.Abs(v.X), Y: math.Abs(v.Y)}
+}
+
+// Floor rounds both components down.
+func (v Vector2D) Floor() Vector2D {
+	return Vector2D{X: math.Floor(v.X), Y: math.Floor(v.Y)}
+}
+
+// Ceil rounds both components up.
+func (v Vector2D) Ceil() Vector2D {
+	return Vector2D{X: math.Ceil(v.X), Y: math.Ceil(v.Y)}
+}
+
+// Round rounds both components to the nearest integer.
+func (v Vector2D) Round() Vector2D {
+	return Vector2D{X: math.Round(v.X), Y: math.Round(v.Y)}
+}
+
+// ToVector2I rounds both components down to integers, which keeps negative
+// coordinates in the right pixel.
+func (v Vector2D) ToVector2I() Vector2I {
+	return Vector2I{X: int(math.Floor(v.X)), Y: int(math.Floor(v.Y))}
+}
+
+// ToGrid returns the cell of a grid of square cells that contains the point.
+func (v Vector2D) ToGrid(cellSize float64) Vector2I {
+	return v.Divide(cellSize).ToVector2I()
+}
@@ -0,0 +1,99 @@
+package geometry
+
+import (
+	"math"
+)
+
+// -----------------------------------------------------------------------------
+// Vector2I is an integer 2D point or vector, for grid cells, tiles and pixels.
+// -----------------------------------------------------------------------------
+type Vector2I struct {
+	X, Y int
+}
+
+// Add two vectors.
+func (v Vector2I) Add(other Vector2I) Vector2I {
+	return Vector2I{X: v.X + other.X, Y: v.Y + other.Y}
+}
+
+// Subtract one vector from another.
+func (v Vector2I) Subtract(other Vector2I) Vector2I {
+	return Vector2I{X: v.X - other.X, Y: v.Y - other.Y}
+}
+
+// Multiply the vector by a scalar.
+func (v Vector2I) Multiply(scalar int) Vector2I {
+	return Vector2I{X: v.X * scalar, Y: v.Y * scalar}
+}
+
+// Negate returns the negated vector.
+func (v Vector2I) Negate() Vector2I {
+	return Vector2I{X: -v.X, Y: -v.Y}
+}
+
+// Dot returns the dot product of two vectors.
+func (v Vector2I) Dot(other Vector2I) int {
+	return v.X*other.X + v.Y*other.Y
+}
+
+// Cross returns the 2D cross product of two vectors.
+func (v Vector2I) Cross(other Vector2I) int {
+	return v.X*other.Y - v.Y*other.X
+}
+
+// LengthSquared returns the squared magnitude of the vector.
+func (v Vector2I) LengthSquared() int {
+	return v.Dot(v)
+}
+
+// Length returns the magnitude of the vector.
+func (v Vector2I) Length() float64 {
+	return math.Sqrt(float64(v.LengthSquared()))
+}
+
+// ManhattanLength returns |X| + |Y|, the number of orthogonal grid steps.
+func (v Vector2I) ManhattanLength() int {
+	return v.Abs().X + v.Abs().Y
+}
+
+// ChebyshevLength returns max(|X|, |Y|), the number of grid steps when
+// diagonal moves are allowed.
+func (v Vector2I) ChebyshevLength() int {
+	abs := v.Abs()
+	if abs.X > abs.Y {
+		return abs.X
+	}
+	return abs.Y
+}
+
+// Abs returns the vector with both components made non-negative.
+func (v Vector2I) Abs() Vector2I {
+	if v.X < 0 {
+		v.X = -v.X
+	}
+	if v.Y < 0 {
+		v.Y = -v.Y
+	}
+	return v
+}
+
+// Min returns the component-wise minimum of two vectors.
+func (v Vector2I) Min(other Vector2I) Vector2I {
+	return Vector2I{X: min(v.X, other.X), Y: min(v.Y, other.Y)}
+}
+
+// Max returns the component-wise maximum of two vectors.
+func (v Vector2I) Max(other Vector2I) Vector2I {
+	return Vector2I{X: max(v.X, other.X), Y: max(v.Y, other.Y)}
+}
+
+// ToVector2D converts the vector to floating point.
+func (v Vector2I) ToVector2D() Vector2D {
+	return Vector2D{X: float64(v.X), Y: float64(v.Y)}
+}
+
+// CellCenter returns the centre of this cell in a grid of square cells, the
+// inverse of Vector2D.ToGrid.
+func (v Vector2I) CellCenter(cellSize float64) Vector2D {
+	return v.ToVector2D().Add(Vector2D{X: 0.5, Y: 0.5}).Multiply(cellSize)
+}
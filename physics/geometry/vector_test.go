@@ -0,0 +1,129 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVectorInterpolation(t *testing.T) {
+	a, b := Vector2D{X: 0, Y: 10}, Vector2D{X: 20, Y: -10}
+	if got := a.Lerp(b, 0.25); got != (Vector2D{X: 5, Y: 5}) {
+		t.Errorf("Lerp(0.25) = %v, want {5 5}", got)
+	}
+	if a.Lerp(b, 0) != a || a.Lerp(b, 1) != b {
+		t.Errorf("Lerp() does not start at v and end at other")
+	}
+
+	// Halfway from right to down is the diagonal, at the average length.
+	got := Vector2D{X: 2, Y: 0}.Slerp(Vector2D{X: 0, Y: 4}, 0.5)
+	if !nearVector(got, FromAngle(math.Pi/4).Multiply(3)) {
+		t.Errorf("Slerp(0.5) = %v, want length 3 at 45 degrees", got)
+	}
+	// Across the ±π seam, the short way round.
+	if angle := LerpAngle(Radians(170), Radians(-170), 0.5); !near(math.Abs(angle), math.Pi) {
+		t.Errorf("LerpAngle() across the seam = %v degrees, want 180", Degrees(angle))
+	}
+}
+
+func TestVectorReflectProject(t *testing.T) {
+	velocity := Vector2D{X: 3, Y: 4}
+	up := Vector2D{X: 0, Y: -1}
+	if got := velocity.Reflect(up); got != (Vector2D{X: 3, Y: -4}) {
+		t.Errorf("Reflect() = %v, want {3 -4}", got)
+	}
+	onto := Vector2D{X: 10, Y: 0}
+	if got := velocity.Project(onto); got != (Vector2D{X: 3, Y: 0}) {
+		t.Errorf("Project() = %v, want {3 0}", got)
+	}
+	if got := velocity.Reject(onto); got != (Vector2D{X: 0, Y: 4}) {
+		t.Errorf("Reject() = %v, want {0 4}", got)
+	}
+	if got := velocity.Project(Vector2D{}); !got.IsZero() {
+		t.Errorf("Project() onto zero = %v, want zero", got)
+	}
+}
+
+func TestVectorClamp(t *testing.T) {
+	v := Vector2D{X: 30, Y: 40}
+	if got := v.ClampLength(10); !nearVector(got, Vector2D{X: 6, Y: 8}) {
+		t.Errorf("ClampLength(10) = %v, want {6 8}", got)
+	}
+	if got := v.ClampLength(100); got != v {
+		t.Errorf("ClampLength(100) = %v, want the vector unchanged", got)
+	}
+	if got := v.Clamp(Vector2D{X: 0, Y: 0}, Vector2D{X: 35, Y: 35}); got != (Vector2D{X: 30, Y: 35}) {
+		t.Errorf("Clamp() = %v, want {30 35}", got)
+	}
+	if got := (Vector2D{X: -1, Y: 5}).Min(Vector2D{X: 2, Y: -3}); got != (Vector2D{X: -1, Y: -3}) {
+		t.Errorf("Min() = %v", got)
+	}
+}
+
+func TestVectorAngles(t *testing.T) {
+	right, down := Vector2D{X: 1, Y: 0}, Vector2D{X: 0, Y: 1}
+	if !near(right.AngleTo(down), math.Pi/2) || !near(down.AngleTo(right), -math.Pi/2) {
+		t.Errorf("AngleTo() between right and down = %v and %v", right.AngleTo(down), down.AngleTo(right))
+	}
+	if !near(right.AngleTo(right.Negate()), math.Pi) {
+		t.Errorf("AngleTo() the opposite way = %v, want π", right.AngleTo(right.Negate()))
+	}
+	if got := right.Rotate(right.AngleTo(down)); !nearVector(got, down) {
+		t.Errorf("rotating by AngleTo() gives %v, want %v", got, down)
+	}
+	if !near(Vector2D{X: -1, Y: 0}.Heading(), math.Pi) || !nearVector(FromAngle(math.Pi/2), down) {
+		t.Errorf("Heading() and FromAngle() disagree")
+	}
+}
+
+func TestVectorApproxEqual(t *testing.T) {
+	v := Vector2D{X: 1, Y: 2}
+	if !v.ApproxEqual(Vector2D{X: 1.05, Y: 1.95}, 0.1) {
+		t.Errorf("ApproxEqual() rejects vectors within epsilon")
+	}
+	if v.ApproxEqual(Vector2D{X: 1, Y: 2.2}, 0.1) {
+		t.Errorf("ApproxEqual() accepts vectors further apart than epsilon")
+	}
+	if (Vector2D{X: 0, Y: 1e-300}).IsZero() || !(Vector2D{}).IsZero() {
+		t.Errorf("IsZero() is not exact")
+	}
+}
+
+func TestVectorGrid(t *testing.T) {
+	tests := []struct {
+		point Vector2D
+		cell  Vector2I
+	}{
+		{Vector2D{X: 5, Y: 5}, Vector2I{X: 0, Y: 0}},
+		{Vector2D{X: 35, Y: 12}, Vector2I{X: 1, Y: 0}},
+		{Vector2D{X: -0.5, Y: -33}, Vector2I{X: -1, Y: -2}},
+	}
+	for _, test := range tests {
+		cell := test.point.ToGrid(32)
+		if cell != test.cell {
+			t.Errorf("ToGrid(%v) = %v, want %v", test.point, cell, test.cell)
+			continue
+		}
+		if center := cell.CellCenter(32); center.ToGrid(32) != cell {
+			t.Errorf("CellCenter(%v) = %v, outside the cell", cell, center)
+		}
+	}
+	if got := (Vector2D{X: -0.5, Y: 2.5}).ToVector2I(); got != (Vector2I{X: -1, Y: 2}) {
+		t.Errorf("ToVector2I() = %v, want {-1 2}", got)
+	}
+}
+
+func TestVector2I(t *testing.T) {
+	v := Vector2I{X: -3, Y: 4}
+	if v.ManhattanLength() != 7 || v.ChebyshevLength() != 4 || v.LengthSquared() != 25 || v.Length() != 5 {
+		t.Errorf("lengths of %v are wrong", v)
+	}
+	if got := v.Add(Vector2I{X: 1, Y: 1}).Multiply(2); got != (Vector2I{X: -4, Y: 10}) {
+		t.Errorf("(v + {1 1}) * 2 = %v", got)
+	}
+	if v.Dot(Vector2I{X: 1, Y: 1}) != 1 || v.Cross(Vector2I{X: 1, Y: 0}) != -4 {
+		t.Errorf("Dot() or Cross() is wrong")
+	}
+	if got := v.Max(Vector2I{X: 0, Y: 0}); got != (Vector2I{X: 0, Y: 4}) {
+		t.Errorf("Max() = %v", got)
+	}
+}
@@ -0,0 +1,246 @@
+package joint
+
+import (
+	"math"
+
+	"2d_game_engine/physics/body"
+)
+
+// DistanceJoint keeps two anchor points within a range of distances. When the
+// minimum and maximum lengths are equal the joint is a rigid rod. Otherwise the
+// anchors move freely between the limits, optionally pulled towards the rest
+// length by a spring.
+type DistanceJoint struct {
+	jointBase
+	length    float64 // Rest length of the spring (pixels)
+	minLength float64
+	maxLength float64
+	stiffness float64 // Spring stiffness, zero for no spring
+	damping   float64 // Spring damping
+
+	// Solver state.
+	u             Vector2D // Unit vector from anchor A to anchor B
+	currentLength float64
+	mass          float64
+	softMass      float64
+	gamma         float64
+	bias          float64
+	impulse       float64
+	lowerImpulse  float64
+	upperImpulse  float64
+}
+
+// NewDistanceJoint creates a rigid rod between two world-space anchors, keeping
+// their current distance.
+func NewDistanceJoint(bodyA, bodyB *body.Body, anchorA, anchorB Vector2D) *DistanceJoint {
+	length := math.Max(anchorB.Subtract(anchorA).Length(), linearSlop)
+	return &DistanceJoint{
+		jointBase: newJointBase(bodyA, bodyB, anchorA, anchorB),
+		length:    length,
+		minLength: length,
+		maxLength: length,
+	}
+}
+
+// NewRopeJoint creates a rope between two world-space anchors. The anchors may
+// move closer together but never further apart than maxLength.
+func NewRopeJoint(bodyA, bodyB *body.Body, anchorA, anchorB Vector2D, maxLength float64) *DistanceJoint {
+	return &DistanceJoint{
+		jointBase: newJointBase(bodyA, bodyB, anchorA, anchorB),
+		length:    maxLength,
+		minLength: 0,
+		maxLength: maxLength,
+	}
+}
+
+// NewSpringJoint creates a spring between two world-space anchors with its rest
+// length at their current distance. Stiffness is in force per pixel and
+// damping in force per pixel per second.
+func NewSpringJoint(bodyA, bodyB *body.Body, anchorA, anchorB Vector2D, stiffness, damping float64) *DistanceJoint {
+	return &DistanceJoint{
+		jointBase: newJointBase(bodyA, bodyB, anchorA, anchorB),
+		length:    anchorB.Subtract(anchorA).Length(),
+		minLength: 0,
+		maxLength: math.Inf(1),
+		stiffness: stiffness,
+		damping:   damping,
+	}
+}
+
+func (j *DistanceJoint) GetLength() float64 {
+	return j.length
+}
+
+func (j *DistanceJoint) GetMinLength() float64 {
+	return j.minLength
+}
+
+func (j *DistanceJoint) GetMaxLength() float64 {
+	return j.maxLength
+}
+
+func (j *DistanceJoint) GetStiffness() float64 {
+	return j.stiffness
+}
+
+func (j *DistanceJoint) GetDamping() float64 {
+	return j.damping
+}
+
+// GetCurrentLength returns the distance between the anchors.
+func (j *DistanceJoint) GetCurrentLength() float64 {
+	return j.GetAnchorB().Subtract(j.GetAnchorA()).Length()
+}
+
+// SetLength sets the rest length of the spring, or the rod length of a rigid
+// joint.
+func (j *DistanceJoint) SetLength(length float64) {
+	rigid := j.minLength == j.maxLength
+	j.length = math.Max(length, linearSlop)
+	if rigid {
+		j.minLength, j.maxLength = j.length, j.length
+	}
+	j.impulse = 0
+	j.wake()
+}
+
+// SetLimits sets the range the distance between the anchors may vary in. Equal
+// limits make the joint rigid.
+func (j *DistanceJoint) SetLimits(minLength, maxLength float64) {
+	j.minLength = math.Max(minLength, 0)
+	j.maxLength = math.Max(maxLength, j.minLength)
+	j.lowerImpulse = 0
+	j.upperImpulse = 0
+	j.wake()
+}
+
+func (j *DistanceJoint) SetStiffness(stiffness float64) {
+	j.stiffness = stiffness
+}
+
+func (j *DistanceJoint) SetDamping(damping float64) {
+	j.damping = damping
+}
+
+func (j *DistanceJoint) InitVelocityConstraints(deltaTime float64) {
+	j.prepareBodies()
+
+	d := positionOf(j.bodyB).Add(j.rB).Subtract(positionOf(j.bodyA)).Subtract(j.rA)
+	j.currentLength = d.Length()
+	if j.currentLength > linearSlop {
+		j.u = d.Divide(j.currentLength)
+	} else {
+		j.u = Vector2D{}
+	}
+
+	crossA := j.rA.Cross(j.u)
+	crossB := j.rB.Cross(j.u)
+	inverseMass := j.mA + j.iA*crossA*crossA + j.mB + j.iB*crossB*crossB
+	j.mass = 0
+	if inverseMass != 0 {
+		j.mass = 1 / inverseMass
+	}
+
+	j.gamma = 0
+	j.bias = 0
+	j.softMass = j.mass
+	if j.stiffness > 0 && j.minLength < j.maxLength {
+		// Soft constraint: gamma softens the mass, bias pulls towards the rest length.
+		gamma := deltaTime * (j.damping + deltaTime*j.stiffness)
+		if gamma != 0 {
+			j.gamma = 1 / gamma
+		}
+		j.bias = (j.currentLength - j.length) * deltaTime * j.stiffness * j.gamma
+		inverseMass += j.gamma
+		j.softMass = 0
+		if inverseMass != 0 {
+			j.softMass = 1 / inverseMass
+		}
+	} else {
+		j.impulse = 0
+	}
+
+	// Warm start.
+	impulse := j.u.Multiply(j.impulse + j.lowerImpulse - j.upperImpulse)
+	j.applyImpulse(impulse, j.rA.Cross(impulse), j.rB.Cross(impulse))
+}
+
+func (j *DistanceJoint) SolveVelocityConstraints(deltaTime float64) {
+	if j.minLength == j.maxLength {
+		cdot := j.u.Dot(j.relativeVelocity())
+		impulse := -j.mass * cdot
+		j.impulse += impulse
+		j.applyAxialImpulse(impulse)
+		return
+	}
+
+	if j.stiffness > 0 {
+		cdot := j.u.Dot(j.relativeVelocity())
+		impulse := -j.softMass * (cdot + j.bias + j.gamma*j.impulse)
+		j.impulse += impulse
+		j.applyAxialImpulse(impulse)
+	}
+
+	// Lower limit: push apart, with speculative bias while still separated.
+	{
+		c := j.currentLength - j.minLength
+		bias := math.Max(0, c) / deltaTime
+		cdot := j.u.Dot(j.relativeVelocity())
+		impulse := -j.mass * (cdot + bias)
+		oldImpulse := j.lowerImpulse
+		j.lowerImpulse = math.Max(0, oldImpulse+impulse)
+		j.applyAxialImpulse(j.lowerImpulse - oldImpulse)
+	}
+
+	// Upper limit: pull together.
+	if !math.IsInf(j.maxLength, 1) {
+		c := j.maxLength - j.currentLength
+		bias := math.Max(0, c) / deltaTime
+		cdot := -j.u.Dot(j.relativeVelocity())
+		impulse := -j.mass * (cdot + bias)
+		oldImpulse := j.upperImpulse
+		j.upperImpulse = math.Max(0, oldImpulse+impulse)
+		j.applyAxialImpulse(-(j.upperImpulse - oldImpulse))
+	}
+}
+
+// applyAxialImpulse applies an impulse along the joint axis.
+func (j *DistanceJoint) applyAxialImpulse(impulse float64) {
+	p := j.u.Multiply(impulse)
+	j.applyImpulse(p, j.rA.Cross(p), j.rB.Cross(p))
+}
+
+func (j *DistanceJoint) SolvePositionConstraints() bool {
+	j.prepareBodies()
+
+	d := positionOf(j.bodyB).Add(j.rB).Subtract(positionOf(j.bodyA)).Subtract(j.rA)
+	length := d.Length()
+	u := Vector2D{}
+	if length > 0 {
+		u = d.Divide(length)
+	}
+
+	var c float64
+	switch {
+	case j.minLength == j.maxLength:
+		c = length - j.minLength
+	case length < j.minLength:
+		c = length - j.minLength
+	case length > j.maxLength:
+		c = length - j.maxLength
+	default:
+		return true
+	}
+	c = clamp(c, -maxLinearCorrection, maxLinearCorrection)
+
+	crossA := j.rA.Cross(u)
+	crossB := j.rB.Cross(u)
+	inverseMass := j.mA + j.iA*crossA*crossA + j.mB + j.iB*crossB*crossB
+	if inverseMass == 0 {
+		return true
+	}
+
+	impulse := u.Multiply(-c / inverseMass)
+	j.moveBodies(impulse, j.rA.Cross(impulse), j.rB.Cross(impulse))
+	return math.Abs(c) < linearSlop
+}
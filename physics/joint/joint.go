@@ -0,0 +1,224 @@
+package joint
+
+import (
+	"math"
+
+	"2d_game_engine/physics/body"
+	"2d_game_engine/physics/geometry"
+)
+
+type Vector2D = geometry.Vector2D
+
+const (
+	// linearSlop is the positional error (pixels) joints tolerate.
+	linearSlop = 0.5
+	// maxLinearCorrection limits how far (pixels) one position iteration moves a body.
+	maxLinearCorrection = 20.0
+)
+
+var (
+	// angularSlop is the angular error (radians) joints tolerate.
+	angularSlop = geometry.Radians(2)
+	// maxAngularCorrection limits how far (radians) one position iteration turns a body.
+	maxAngularCorrection = geometry.Radians(8)
+)
+
+// Joint constrains the relative motion of two bodies. Joints are solved by
+// the physics world alongside contacts, with the same sequential impulse
+// approach: velocity constraints every velocity iteration, followed by
+// position constraints that remove drift.
+type Joint interface {
+	// GetBodyA returns the first body. It may be nil for joints attached to
+	// the world, such as MouseJoint.
+	GetBodyA() *body.Body
+	GetBodyB() *body.Body
+	// GetCollideConnected reports whether the two bodies may still collide.
+	GetCollideConnected() bool
+
+	// InitVelocityConstraints prepares the joint for a step and applies the
+	// impulses accumulated during the previous step (warm starting).
+	InitVelocityConstraints(deltaTime float64)
+	// SolveVelocityConstraints runs one velocity iteration.
+	SolveVelocityConstraints(deltaTime float64)
+	// SolvePositionConstraints runs one position iteration and reports
+	// whether the position error is within tolerance.
+	SolvePositionConstraints() bool
+}
+
+// jointBase holds the anchors and per-step solver data shared by all joints.
+type jointBase struct {
+	bodyA            *body.Body
+	bodyB            *body.Body
+	localAnchorA     Vector2D // Relative to bodyA's position, in bodyA's unrotated frame
+	localAnchorB     Vector2D // Relative to bodyB's position, in bodyB's unrotated frame
+	collideConnected bool
+
+	// Solver data refreshed every step.
+	rA, rB Vector2D // World-space offsets from each body's position to its anchor
+	mA, mB float64  // Inverse masses
+	iA, iB float64  // Inverse inertias
+}
+
+// newJointBase anchors both bodies at world-space points using their current
+// positions and angles. A nil body anchors to the world.
+func newJointBase(bodyA, bodyB *body.Body, anchorA, anchorB Vector2D) jointBase {
+	return jointBase{
+		bodyA:        bodyA,
+		bodyB:        bodyB,
+		localAnchorA: toLocal(bodyA, anchorA),
+		localAnchorB: toLocal(bodyB, anchorB),
+	}
+}
+
+func (j *jointBase) GetBodyA() *body.Body {
+	return j.bodyA
+}
+
+func (j *jointBase) GetBodyB() *body.Body {
+	return j.bodyB
+}
+
+func (j *jointBase) GetCollideConnected() bool {
+	return j.collideConnected
+}
+
+func (j *jointBase) SetCollideConnected(collideConnected bool) {
+	j.collideConnected = collideConnected
+}
+
+// GetAnchorA returns the joint's anchor on bodyA in world space.
+func (j *jointBase) GetAnchorA() Vector2D {
+	return toWorld(j.bodyA, j.localAnchorA)
+}
+
+// GetAnchorB returns the joint's anchor on bodyB in world space.
+func (j *jointBase) GetAnchorB() Vector2D {
+	return toWorld(j.bodyB, j.localAnchorB)
+}
+
+// prepareBodies caches the anchor offsets and inverse masses for this step.
+func (j *jointBase) prepareBodies() {
+	j.rA = rotationOf(j.bodyA).MulVector(j.localAnchorA)
+	j.rB = rotationOf(j.bodyB).MulVector(j.localAnchorB)
+	j.mA, j.iA = inverseMasses(j.bodyA)
+	j.mB, j.iB = inverseMasses(j.bodyB)
+}
+
+// applyImpulse applies a linear impulse P to bodyB and -P to bodyA, with the
+// given angular impulses (LA is subtracted from bodyA, LB added to bodyB).
+func (j *jointBase) applyImpulse(impulse Vector2D, angularA, angularB float64) {
+	if j.bodyA != nil {
+		j.bodyA.ApplyConstraintImpulse(impulse.Negate(), -angularA)
+	}
+	if j.bodyB != nil {
+		j.bodyB.ApplyConstraintImpulse(impulse, angularB)
+	}
+}
+
+// moveBodies applies a position-level impulse, translating and rotating the
+// bodies directly.
+func (j *jointBase) moveBodies(impulse Vector2D, angularA, angularB float64) {
+	if j.bodyA != nil && j.mA+j.iA > 0 {
+		j.bodyA.SetPosition(j.bodyA.GetPosition().Subtract(impulse.Multiply(j.mA)))
+		j.bodyA.SetAngle(j.bodyA.GetAngle() - geometry.Degrees(j.iA*angularA))
+	}
+	if j.bodyB != nil && j.mB+j.iB > 0 {
+		j.bodyB.SetPosition(j.bodyB.GetPosition().Add(impulse.Multiply(j.mB)))
+		j.bodyB.SetAngle(j.bodyB.GetAngle() + geometry.Degrees(j.iB*angularB))
+	}
+}
+
+// positionOf returns a body's position, or the origin for the world.
+func positionOf(b *body.Body) Vector2D {
+	if b == nil {
+		return Vector2D{}
+	}
+	return b.GetPosition()
+}
+
+// angleOf returns a body's angle in radians, or zero for the world.
+func angleOf(b *body.Body) float64 {
+	if b == nil {
+		return 0
+	}
+	return geometry.Radians(b.GetAngle())
+}
+
+// rotationOf returns the matrix turning a body's unrotated frame into world
+// space, or the identity for the world.
+func rotationOf(b *body.Body) geometry.Mat2 {
+	if b == nil {
+		return geometry.IdentityMat2()
+	}
+	return geometry.RotationMat2(b.GetAngle())
+}
+
+func velocityOf(b *body.Body) Vector2D {
+	if b == nil {
+		return Vector2D{}
+	}
+	return b.GetVelocity()
+}
+
+func angularVelocityOf(b *body.Body) float64 {
+	if b == nil {
+		return 0
+	}
+	return b.GetAngularVelocity()
+}
+
+// inverseMasses returns the inverse mass and inertia of a body. The world and
+// static bodies cannot be moved by joints.
+func inverseMasses(b *body.Body) (float64, float64) {
+	if b == nil || b.GetIsStatic() {
+		return 0, 0
+	}
+	return b.GetInverseMass(), b.GetInverseInertia()
+}
+
+// toLocal converts a world point into a body's unrotated frame.
+func toLocal(b *body.Body, point Vector2D) Vector2D {
+	return rotationOf(b).Transpose().MulVector(point.Subtract(positionOf(b)))
+}
+
+// toWorld converts a point in a body's unrotated frame into world space.
+func toWorld(b *body.Body, local Vector2D) Vector2D {
+	return positionOf(b).Add(rotationOf(b).MulVector(local))
+}
+
+// crossScalar returns the cross product of an angular velocity and a vector.
+func crossScalar(w float64, r Vector2D) Vector2D {
+	return Vector2D{X: -w * r.Y, Y: w * r.X}
+}
+
+// relativeVelocity returns the velocity of bodyB's anchor relative to bodyA's.
+func (j *jointBase) relativeVelocity() Vector2D {
+	velocityA := velocityOf(j.bodyA).Add(crossScalar(angularVelocityOf(j.bodyA), j.rA))
+	velocityB := velocityOf(j.bodyB).Add(crossScalar(angularVelocityOf(j.bodyB), j.rB))
+	return velocityB.Subtract(velocityA)
+}
+
+// solve22 solves the symmetric 2x2 system [k11 k12; k12 k22] x = b.
+func solve22(k11, k12, k22 float64, b Vector2D) Vector2D {
+	det := k11*k22 - k12*k12
+	if det != 0 {
+		det = 1 / det
+	}
+	return Vector2D{
+		X: det * (k22*b.X - k12*b.Y),
+		Y: det * (k11*b.Y - k12*b.X),
+	}
+}
+
+func clamp(value, low, high float64) float64 {
+	return math.Max(low, math.Min(value, high))
+}
+
+// wake wakes both bodies so changes to motors and targets take effect.
+func (j *jointBase) wake() {
+	for _, b := range []*body.Body{j.bodyA, j.bodyB} {
+		if b != nil && b.GetIsSleeping() {
+			b.SetIsSleeping(false)
+		}
+	}
+}
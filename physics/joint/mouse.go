@@ -0,0 +1,122 @@
+package joint
+
+import (
+	"math"
+
+	"2d_game_engine/physics/body"
+)
+
+// MouseJoint drags a point on a body towards a target with a soft spring,
+// typically to pick up bodies with the mouse. Its force is limited so the body
+// still reacts to collisions while being dragged. It has no bodyA.
+type MouseJoint struct {
+	jointBase
+	target       Vector2D
+	frequency    float64 // Spring frequency in hertz
+	dampingRatio float64 // 1 is critically damped
+	maxForce     float64
+
+	// Solver state.
+	impulse  Vector2D
+	gamma    float64
+	bias     Vector2D
+	k11, k12 float64
+	k22      float64
+}
+
+// NewMouseJoint attaches a world-space point on a body to a target, initially
+// the point itself. maxForce limits how hard the joint pulls.
+func NewMouseJoint(b *body.Body, point Vector2D, maxForce float64) *MouseJoint {
+	return &MouseJoint{
+		jointBase:    newJointBase(nil, b, point, point),
+		target:       point,
+		frequency:    5,
+		dampingRatio: 0.7,
+		maxForce:     maxForce,
+	}
+}
+
+func (j *MouseJoint) GetTarget() Vector2D {
+	return j.target
+}
+
+func (j *MouseJoint) GetFrequency() float64 {
+	return j.frequency
+}
+
+func (j *MouseJoint) GetDampingRatio() float64 {
+	return j.dampingRatio
+}
+
+func (j *MouseJoint) GetMaxForce() float64 {
+	return j.maxForce
+}
+
+// SetTarget moves the point the body is dragged towards, waking the body.
+func (j *MouseJoint) SetTarget(target Vector2D) {
+	if target != j.target {
+		j.target = target
+		j.wake()
+	}
+}
+
+func (j *MouseJoint) SetFrequency(frequency float64) {
+	j.frequency = frequency
+}
+
+func (j *MouseJoint) SetDampingRatio(dampingRatio float64) {
+	j.dampingRatio = dampingRatio
+}
+
+func (j *MouseJoint) SetMaxForce(maxForce float64) {
+	j.maxForce = maxForce
+}
+
+func (j *MouseJoint) InitVelocityConstraints(deltaTime float64) {
+	j.prepareBodies()
+
+	// Spring stiffness and damping from the body's mass, so the feel does not
+	// depend on how heavy the body is.
+	mass := j.bodyB.GetMass()
+	omega := 2 * math.Pi * j.frequency
+	damping := 2 * mass * j.dampingRatio * omega
+	stiffness := mass * omega * omega
+
+	j.gamma = deltaTime * (damping + deltaTime*stiffness)
+	if j.gamma != 0 {
+		j.gamma = 1 / j.gamma
+	}
+	beta := deltaTime * stiffness * j.gamma
+
+	rB := j.rB
+	j.k11 = j.mB + j.iB*rB.Y*rB.Y + j.gamma
+	j.k12 = -j.iB * rB.X * rB.Y
+	j.k22 = j.mB + j.iB*rB.X*rB.X + j.gamma
+
+	j.bias = positionOf(j.bodyB).Add(rB).Subtract(j.target).Multiply(beta)
+
+	// Warm start.
+	j.applyImpulse(j.impulse, 0, rB.Cross(j.impulse))
+}
+
+func (j *MouseJoint) SolveVelocityConstraints(deltaTime float64) {
+	cdot := j.relativeVelocity()
+	rhs := cdot.Add(j.bias).Add(j.impulse.Multiply(j.gamma)).Negate()
+	impulse := solve22(j.k11, j.k12, j.k22, rhs)
+
+	oldImpulse := j.impulse
+	j.impulse = j.impulse.Add(impulse)
+	maxImpulse := deltaTime * j.maxForce
+	if j.impulse.LengthSaqured() > maxImpulse*maxImpulse {
+		j.impulse = j.impulse.Normalize().Multiply(maxImpulse)
+	}
+	impulse = j.impulse.Subtract(oldImpulse)
+
+	j.applyImpulse(impulse, 0, j.rB.Cross(impulse))
+}
+
+// SolvePositionConstraints does nothing: the spring alone pulls the body
+// towards the target.
+func (j *MouseJoint) SolvePositionConstraints() bool {
+	return true
+}
@@ -0,0 +1,268 @@
+package joint
+
+import (
+	"math"
+
+	"2d_game_engine/physics/body"
+)
+
+// PrismaticJoint lets bodyB slide along an axis fixed in bodyA without
+// rotating relative to it. The translation can be limited and driven by a
+// motor, which suits pistons, elevators and sliding doors.
+type PrismaticJoint struct {
+	jointBase
+	localAxisA     Vector2D // Unit axis in bodyA's unrotated frame
+	referenceAngle float64
+
+	enableLimit      bool
+	lowerTranslation float64 // Pixels
+	upperTranslation float64 // Pixels
+
+	enableMotor   bool
+	motorSpeed    float64 // Pixels per second
+	maxMotorForce float64
+
+	// Solver state.
+	axis, perp   Vector2D
+	a1, a2       float64
+	s1, s2       float64
+	k11, k12     float64
+	k22          float64
+	axialMass    float64
+	translation  float64
+	impulse      Vector2D // Perpendicular and angular impulses
+	motorImpulse float64
+	lowerImpulse float64
+	upperImpulse float64
+}
+
+// NewPrismaticJoint joins two bodies at a world-space anchor, letting them
+// slide along a world-space axis.
+func NewPrismaticJoint(bodyA, bodyB *body.Body, anchor, axis Vector2D) *PrismaticJoint {
+	return &PrismaticJoint{
+		jointBase:      newJointBase(bodyA, bodyB, anchor, anchor),
+		localAxisA:     rotationOf(bodyA).Transpose().MulVector(axis.Normalize()),
+		referenceAngle: angleOf(bodyB) - angleOf(bodyA),
+	}
+}
+
+// GetAxis returns the sliding axis in world space.
+func (j *PrismaticJoint) GetAxis() Vector2D {
+	return rotationOf(j.bodyA).MulVector(j.localAxisA)
+}
+
+// GetJointTranslation returns how far anchor B has moved from anchor A along
+// the axis.
+func (j *PrismaticJoint) GetJointTranslation() float64 {
+	return j.GetAnchorB().Subtract(j.GetAnchorA()).Dot(j.GetAxis())
+}
+
+// GetJointSpeed returns the relative speed of the anchors along the axis.
+func (j *PrismaticJoint) GetJointSpeed() float64 {
+	rA := rotationOf(j.bodyA).MulVector(j.localAnchorA)
+	rB := rotationOf(j.bodyB).MulVector(j.localAnchorB)
+	axis := j.GetAxis()
+	d := positionOf(j.bodyB).Add(rB).Subtract(positionOf(j.bodyA)).Subtract(rA)
+	velocityA := velocityOf(j.bodyA).Add(crossScalar(angularVelocityOf(j.bodyA), rA))
+	velocityB := velocityOf(j.bodyB).Add(crossScalar(angularVelocityOf(j.bodyB), rB))
+	return d.Dot(crossScalar(angularVelocityOf(j.bodyA), axis)) + axis.Dot(velocityB.Subtract(velocityA))
+}
+
+func (j *PrismaticJoint) GetEnableLimit() bool {
+	return j.enableLimit
+}
+
+// GetLimits returns the lower and upper translations in pixels.
+func (j *PrismaticJoint) GetLimits() (float64, float64) {
+	return j.lowerTranslation, j.upperTranslation
+}
+
+func (j *PrismaticJoint) GetEnableMotor() bool {
+	return j.enableMotor
+}
+
+func (j *PrismaticJoint) GetMotorSpeed() float64 {
+	return j.motorSpeed
+}
+
+func (j *PrismaticJoint) GetMaxMotorForce() float64 {
+	return j.maxMotorForce
+}
+
+// GetMotorForce returns the force the motor applied during the last step.
+func (j *PrismaticJoint) GetMotorForce(deltaTime float64) float64 {
+	return j.motorImpulse / deltaTime
+}
+
+func (j *PrismaticJoint) SetEnableLimit(enableLimit bool) {
+	if enableLimit != j.enableLimit {
+		j.enableLimit = enableLimit
+		j.lowerImpulse = 0
+		j.upperImpulse = 0
+		j.wake()
+	}
+}
+
+// SetLimits sets the lower and upper translations in pixels.
+func (j *PrismaticJoint) SetLimits(lower, upper float64) {
+	j.lowerTranslation = math.Min(lower, upper)
+	j.upperTranslation = math.Max(lower, upper)
+	j.lowerImpulse = 0
+	j.upperImpulse = 0
+	j.wake()
+}
+
+func (j *PrismaticJoint) SetEnableMotor(enableMotor bool) {
+	j.enableMotor = enableMotor
+	j.wake()
+}
+
+// SetMotorSpeed sets the target speed along the axis in pixels per second.
+func (j *PrismaticJoint) SetMotorSpeed(speed float64) {
+	j.motorSpeed = speed
+	j.wake()
+}
+
+func (j *PrismaticJoint) SetMaxMotorForce(force float64) {
+	j.maxMotorForce = force
+	j.wake()
+}
+
+// prepareAxes computes the world axes and their lever arms for the current
+// body positions.
+func (j *PrismaticJoint) prepareAxes() {
+	d := positionOf(j.bodyB).Add(j.rB).Subtract(positionOf(j.bodyA)).Subtract(j.rA)
+
+	j.axis = j.GetAxis()
+	j.a1 = d.Add(j.rA).Cross(j.axis)
+	j.a2 = j.rB.Cross(j.axis)
+	j.axialMass = j.mA + j.mB + j.iA*j.a1*j.a1 + j.iB*j.a2*j.a2
+	if j.axialMass > 0 {
+		j.axialMass = 1 / j.axialMass
+	}
+
+	j.perp = j.axis.Perp()
+	j.s1 = d.Add(j.rA).Cross(j.perp)
+	j.s2 = j.rB.Cross(j.perp)
+	j.k11 = j.mA + j.mB + j.iA*j.s1*j.s1 + j.iB*j.s2*j.s2
+	j.k12 = j.iA*j.s1 + j.iB*j.s2
+	j.k22 = j.iA + j.iB
+	if j.k22 == 0 {
+		// Neither body can rotate.
+		j.k22 = 1
+	}
+
+	j.translation = j.axis.Dot(d)
+}
+
+func (j *PrismaticJoint) InitVelocityConstraints(deltaTime float64) {
+	j.prepareBodies()
+	j.prepareAxes()
+
+	if !j.enableMotor {
+		j.motorImpulse = 0
+	}
+	if !j.enableLimit {
+		j.lowerImpulse = 0
+		j.upperImpulse = 0
+	}
+
+	// Warm start.
+	axialImpulse := j.motorImpulse + j.lowerImpulse - j.upperImpulse
+	p := j.perp.Multiply(j.impulse.X).Add(j.axis.Multiply(axialImpulse))
+	angularA := j.impulse.X*j.s1 + j.impulse.Y + axialImpulse*j.a1
+	angularB := j.impulse.X*j.s2 + j.impulse.Y + axialImpulse*j.a2
+	j.applyImpulse(p, angularA, angularB)
+}
+
+// axialSpeed returns the relative speed along the axis, including rotation.
+func (j *PrismaticJoint) axialSpeed() float64 {
+	return j.axis.Dot(velocityOf(j.bodyB).Subtract(velocityOf(j.bodyA))) +
+		j.a2*angularVelocityOf(j.bodyB) - j.a1*angularVelocityOf(j.bodyA)
+}
+
+// applyAxialImpulse applies an impulse along the axis.
+func (j *PrismaticJoint) applyAxialImpulse(impulse float64) {
+	j.applyImpulse(j.axis.Multiply(impulse), impulse*j.a1, impulse*j.a2)
+}
+
+func (j *PrismaticJoint) SolveVelocityConstraints(deltaTime float64) {
+	if j.enableMotor {
+		impulse := j.axialMass * (j.motorSpeed - j.axialSpeed())
+		oldImpulse := j.motorImpulse
+		maxImpulse := deltaTime * j.maxMotorForce
+		j.motorImpulse = clamp(oldImpulse+impulse, -maxImpulse, maxImpulse)
+		j.applyAxialImpulse(j.motorImpulse - oldImpulse)
+	}
+
+	if j.enableLimit {
+		// Lower limit.
+		{
+			c := j.translation - j.lowerTranslation
+			impulse := -j.axialMass * (j.axialSpeed() + math.Max(c, 0)/deltaTime)
+			oldImpulse := j.lowerImpulse
+			j.lowerImpulse = math.Max(oldImpulse+impulse, 0)
+			j.applyAxialImpulse(j.lowerImpulse - oldImpulse)
+		}
+
+		// Upper limit.
+		{
+			c := j.upperTranslation - j.translation
+			impulse := -j.axialMass * (-j.axialSpeed() + math.Max(c, 0)/deltaTime)
+			oldImpulse := j.upperImpulse
+			j.upperImpulse = math.Max(oldImpulse+impulse, 0)
+			j.applyAxialImpulse(-(j.upperImpulse - oldImpulse))
+		}
+	}
+
+	// Keep the anchors on the axis and the relative angle fixed.
+	wA, wB := angularVelocityOf(j.bodyA), angularVelocityOf(j.bodyB)
+	cdot := Vector2D{
+		X: j.perp.Dot(velocityOf(j.bodyB).Subtract(velocityOf(j.bodyA))) + j.s2*wB - j.s1*wA,
+		Y: wB - wA,
+	}
+	impulse := solve22(j.k11, j.k12, j.k22, cdot.Negate())
+	j.impulse = j.impulse.Add(impulse)
+
+	p := j.perp.Multiply(impulse.X)
+	j.applyImpulse(p, impulse.X*j.s1+impulse.Y, impulse.X*j.s2+impulse.Y)
+}
+
+func (j *PrismaticJoint) SolvePositionConstraints() bool {
+	j.prepareBodies()
+	j.prepareAxes()
+	linearError := 0.0
+
+	if j.enableLimit {
+		c := 0.0
+		switch {
+		case math.Abs(j.upperTranslation-j.lowerTranslation) < 2*linearSlop:
+			c = j.translation - j.lowerTranslation
+		case j.translation <= j.lowerTranslation:
+			c = math.Min(j.translation-j.lowerTranslation+linearSlop, 0)
+		case j.translation >= j.upperTranslation:
+			c = math.Max(j.translation-j.upperTranslation-linearSlop, 0)
+		}
+		c = clamp(c, -maxLinearCorrection, maxLinearCorrection)
+		linearError = math.Abs(c)
+
+		impulse := -j.axialMass * c
+		j.moveBodies(j.axis.Multiply(impulse), impulse*j.a1, impulse*j.a2)
+		j.prepareBodies()
+		j.prepareAxes()
+	}
+
+	d := positionOf(j.bodyB).Add(j.rB).Subtract(positionOf(j.bodyA)).Subtract(j.rA)
+	c := Vector2D{
+		X: j.perp.Dot(d),
+		Y: angleOf(j.bodyB) - angleOf(j.bodyA) - j.referenceAngle,
+	}
+	linearError = math.Max(linearError, math.Abs(c.X))
+	angularError := math.Abs(c.Y)
+
+	impulse := solve22(j.k11, j.k12, j.k22, c).Negate()
+	p := j.perp.Multiply(impulse.X)
+	j.moveBodies(p, impulse.X*j.s1+impulse.Y, impulse.X*j.s2+impulse.Y)
+
+	return linearError <= linearSlop && angularError <= angularSlop
+}
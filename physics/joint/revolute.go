@@ -0,0 +1,222 @@
+package joint
+
+import (
+	"math"
+
+	"2d_game_engine/physics/body"
+)
+
+// RevoluteJoint pins two bodies together at a shared point, leaving them free
+// to rotate about it. The relative angle can be limited, and a motor can drive
+// the rotation, which makes it suitable for hinges, doors and wheels.
+type RevoluteJoint struct {
+	jointBase
+	referenceAngle float64 // bodyB's angle relative to bodyA when created (radians)
+
+	enableLimit bool
+	lowerAngle  float64 // Radians
+	upperAngle  float64 // Radians
+
+	enableMotor    bool
+	motorSpeed     float64 // Radians per second
+	maxMotorTorque float64
+
+	// Solver state.
+	linearImpulse Vector2D
+	motorImpulse  float64
+	lowerImpulse  float64
+	upperImpulse  float64
+	axialMass     float64
+	angle         float64
+}
+
+// NewRevoluteJoint pins two bodies together at a world-space anchor.
+func NewRevoluteJoint(bodyA, bodyB *body.Body, anchor Vector2D) *RevoluteJoint {
+	return &RevoluteJoint{
+		jointBase:      newJointBase(bodyA, bodyB, anchor, anchor),
+		referenceAngle: angleOf(bodyB) - angleOf(bodyA),
+	}
+}
+
+// GetJointAngle returns bodyB's rotation relative to bodyA in radians, measured
+// from their relative angle when the joint was created.
+func (j *RevoluteJoint) GetJointAngle() float64 {
+	return angleOf(j.bodyB) - angleOf(j.bodyA) - j.referenceAngle
+}
+
+// GetJointSpeed returns bodyB's angular velocity relative to bodyA.
+func (j *RevoluteJoint) GetJointSpeed() float64 {
+	return angularVelocityOf(j.bodyB) - angularVelocityOf(j.bodyA)
+}
+
+func (j *RevoluteJoint) GetEnableLimit() bool {
+	return j.enableLimit
+}
+
+// GetLimits returns the lower and upper joint angles in radians.
+func (j *RevoluteJoint) GetLimits() (float64, float64) {
+	return j.lowerAngle, j.upperAngle
+}
+
+func (j *RevoluteJoint) GetEnableMotor() bool {
+	return j.enableMotor
+}
+
+func (j *RevoluteJoint) GetMotorSpeed() float64 {
+	return j.motorSpeed
+}
+
+func (j *RevoluteJoint) GetMaxMotorTorque() float64 {
+	return j.maxMotorTorque
+}
+
+// GetMotorTorque returns the torque the motor applied during the last step.
+func (j *RevoluteJoint) GetMotorTorque(deltaTime float64) float64 {
+	return j.motorImpulse / deltaTime
+}
+
+func (j *RevoluteJoint) SetEnableLimit(enableLimit bool) {
+	if enableLimit != j.enableLimit {
+		j.enableLimit = enableLimit
+		j.lowerImpulse = 0
+		j.upperImpulse = 0
+		j.wake()
+	}
+}
+
+// SetLimits sets the lower and upper joint angles in radians.
+func (j *RevoluteJoint) SetLimits(lower, upper float64) {
+	j.lowerAngle = math.Min(lower, upper)
+	j.upperAngle = math.Max(lower, upper)
+	j.lowerImpulse = 0
+	j.upperImpulse = 0
+	j.wake()
+}
+
+func (j *RevoluteJoint) SetEnableMotor(enableMotor bool) {
+	j.enableMotor = enableMotor
+	j.wake()
+}
+
+// SetMotorSpeed sets the target relative angular velocity in radians per second.
+func (j *RevoluteJoint) SetMotorSpeed(speed float64) {
+	j.motorSpeed = speed
+	j.wake()
+}
+
+func (j *RevoluteJoint) SetMaxMotorTorque(torque float64) {
+	j.maxMotorTorque = torque
+	j.wake()
+}
+
+// fixedRotation reports whether neither body can rotate, in which case the
+// angular constraints have nothing to do.
+func (j *RevoluteJoint) fixedRotation() bool {
+	return j.iA+j.iB == 0
+}
+
+func (j *RevoluteJoint) InitVelocityConstraints(deltaTime float64) {
+	j.prepareBodies()
+
+	j.axialMass = j.iA + j.iB
+	if j.axialMass > 0 {
+		j.axialMass = 1 / j.axialMass
+	}
+	j.angle = j.GetJointAngle()
+
+	if !j.enableMotor || j.fixedRotation() {
+		j.motorImpulse = 0
+	}
+	if !j.enableLimit || j.fixedRotation() {
+		j.lowerImpulse = 0
+		j.upperImpulse = 0
+	}
+
+	// Warm start.
+	axialImpulse := j.motorImpulse + j.lowerImpulse - j.upperImpulse
+	p := j.linearImpulse
+	j.applyImpulse(p, j.rA.Cross(p)+axialImpulse, j.rB.Cross(p)+axialImpulse)
+}
+
+func (j *RevoluteJoint) SolveVelocityConstraints(deltaTime float64) {
+	if j.enableMotor && !j.fixedRotation() {
+		cdot := j.GetJointSpeed() - j.motorSpeed
+		impulse := -j.axialMass * cdot
+		oldImpulse := j.motorImpulse
+		maxImpulse := deltaTime * j.maxMotorTorque
+		j.motorImpulse = clamp(oldImpulse+impulse, -maxImpulse, maxImpulse)
+		impulse = j.motorImpulse - oldImpulse
+		j.applyImpulse(Vector2D{}, impulse, impulse)
+	}
+
+	if j.enableLimit && !j.fixedRotation() {
+		// Lower limit.
+		{
+			c := j.angle - j.lowerAngle
+			cdot := j.GetJointSpeed()
+			impulse := -j.axialMass * (cdot + math.Max(c, 0)/deltaTime)
+			oldImpulse := j.lowerImpulse
+			j.lowerImpulse = math.Max(oldImpulse+impulse, 0)
+			impulse = j.lowerImpulse - oldImpulse
+			j.applyImpulse(Vector2D{}, impulse, impulse)
+		}
+
+		// Upper limit.
+		{
+			c := j.upperAngle - j.angle
+			cdot := -j.GetJointSpeed()
+			impulse := -j.axialMass * (cdot + math.Max(c, 0)/deltaTime)
+			oldImpulse := j.upperImpulse
+			j.upperImpulse = math.Max(oldImpulse+impulse, 0)
+			impulse = j.upperImpulse - oldImpulse
+			j.applyImpulse(Vector2D{}, -impulse, -impulse)
+		}
+	}
+
+	// Point to point constraint.
+	cdot := j.relativeVelocity()
+	k11, k12, k22 := j.pointMass()
+	impulse := solve22(k11, k12, k22, cdot.Negate())
+	j.linearImpulse = j.linearImpulse.Add(impulse)
+	j.applyImpulse(impulse, j.rA.Cross(impulse), j.rB.Cross(impulse))
+}
+
+// pointMass returns the effective mass matrix of the point constraint.
+func (j *RevoluteJoint) pointMass() (float64, float64, float64) {
+	k11 := j.mA + j.mB + j.rA.Y*j.rA.Y*j.iA + j.rB.Y*j.rB.Y*j.iB
+	k12 := -j.rA.Y*j.rA.X*j.iA - j.rB.Y*j.rB.X*j.iB
+	k22 := j.mA + j.mB + j.rA.X*j.rA.X*j.iA + j.rB.X*j.rB.X*j.iB
+	return k11, k12, k22
+}
+
+func (j *RevoluteJoint) SolvePositionConstraints() bool {
+	j.prepareBodies()
+	angularError := 0.0
+
+	if j.enableLimit && !j.fixedRotation() {
+		angle := j.GetJointAngle()
+		c := 0.0
+		switch {
+		case math.Abs(j.upperAngle-j.lowerAngle) < 2*angularSlop:
+			c = clamp(angle-j.lowerAngle, -maxAngularCorrection, maxAngularCorrection)
+		case angle <= j.lowerAngle:
+			c = clamp(angle-j.lowerAngle+angularSlop, -maxAngularCorrection, 0)
+		case angle >= j.upperAngle:
+			c = clamp(angle-j.upperAngle-angularSlop, 0, maxAngularCorrection)
+		}
+		angularError = math.Abs(c)
+
+		impulse := -j.axialMass * c
+		j.moveBodies(Vector2D{}, impulse, impulse)
+		j.prepareBodies()
+	}
+
+	// Point to point constraint.
+	c := positionOf(j.bodyB).Add(j.rB).Subtract(positionOf(j.bodyA)).Subtract(j.rA)
+	positionError := c.Length()
+	k11, k12, k22 := j.pointMass()
+	impulse := solve22(k11, k12, k22, c).Negate()
+	j.moveBodies(impulse, j.rA.Cross(impulse), j.rB.Cross(impulse))
+
+	return positionError <= linearSlop && angularError <= angularSlop
+}
@@ -0,0 +1,116 @@
+package joint
+
+import (
+	"math"
+
+	"2d_game_engine/physics/body"
+)
+
+// WeldJoint glues two bodies together at a point, removing all relative
+// motion. A non-zero stiffness lets the weld bend like a spring around the
+// relative angle it was created with.
+type WeldJoint struct {
+	jointBase
+	referenceAngle float64
+	stiffness      float64 // Angular stiffness, zero for a rigid weld
+	damping        float64 // Angular damping
+
+	// Solver state.
+	linearImpulse  Vector2D
+	angularImpulse float64
+	axialMass      float64
+	gamma          float64
+	bias           float64
+}
+
+// NewWeldJoint welds two bodies together at a world-space anchor.
+func NewWeldJoint(bodyA, bodyB *body.Body, anchor Vector2D) *WeldJoint {
+	return &WeldJoint{
+		jointBase:      newJointBase(bodyA, bodyB, anchor, anchor),
+		referenceAngle: angleOf(bodyB) - angleOf(bodyA),
+	}
+}
+
+func (j *WeldJoint) GetStiffness() float64 {
+	return j.stiffness
+}
+
+func (j *WeldJoint) GetDamping() float64 {
+	return j.damping
+}
+
+func (j *WeldJoint) SetStiffness(stiffness float64) {
+	j.stiffness = stiffness
+}
+
+func (j *WeldJoint) SetDamping(damping float64) {
+	j.damping = damping
+}
+
+func (j *WeldJoint) InitVelocityConstraints(deltaTime float64) {
+	j.prepareBodies()
+
+	inverseAxialMass := j.iA + j.iB
+	j.gamma = 0
+	j.bias = 0
+	if j.stiffness > 0 && inverseAxialMass > 0 {
+		c := angleOf(j.bodyB) - angleOf(j.bodyA) - j.referenceAngle
+		gamma := deltaTime * (j.damping + deltaTime*j.stiffness)
+		if gamma != 0 {
+			j.gamma = 1 / gamma
+		}
+		j.bias = c * deltaTime * j.stiffness * j.gamma
+		inverseAxialMass += j.gamma
+	}
+	j.axialMass = 0
+	if inverseAxialMass > 0 {
+		j.axialMass = 1 / inverseAxialMass
+	}
+
+	// Warm start.
+	p := j.linearImpulse
+	j.applyImpulse(p, j.rA.Cross(p)+j.angularImpulse, j.rB.Cross(p)+j.angularImpulse)
+}
+
+func (j *WeldJoint) SolveVelocityConstraints(deltaTime float64) {
+	// Angular constraint, soft when the weld has stiffness.
+	cdot := angularVelocityOf(j.bodyB) - angularVelocityOf(j.bodyA)
+	angularImpulse := -j.axialMass * (cdot + j.bias + j.gamma*j.angularImpulse)
+	j.angularImpulse += angularImpulse
+	j.applyImpulse(Vector2D{}, angularImpulse, angularImpulse)
+
+	// Point to point constraint.
+	k11, k12, k22 := j.pointMass()
+	impulse := solve22(k11, k12, k22, j.relativeVelocity().Negate())
+	j.linearImpulse = j.linearImpulse.Add(impulse)
+	j.applyImpulse(impulse, j.rA.Cross(impulse), j.rB.Cross(impulse))
+}
+
+// pointMass returns the effective mass matrix of the point constraint.
+func (j *WeldJoint) pointMass() (float64, float64, float64) {
+	k11 := j.mA + j.mB + j.rA.Y*j.rA.Y*j.iA + j.rB.Y*j.rB.Y*j.iB
+	k12 := -j.rA.Y*j.rA.X*j.iA - j.rB.Y*j.rB.X*j.iB
+	k22 := j.mA + j.mB + j.rA.X*j.rA.X*j.iA + j.rB.X*j.rB.X*j.iB
+	return k11, k12, k22
+}
+
+func (j *WeldJoint) SolvePositionConstraints() bool {
+	j.prepareBodies()
+
+	angularError := 0.0
+	if j.stiffness == 0 && j.iA+j.iB > 0 {
+		c := angleOf(j.bodyB) - angleOf(j.bodyA) - j.referenceAngle
+		angularError = math.Abs(c)
+		impulse := -clamp(c, -maxAngularCorrection, maxAngularCorrection) / (j.iA + j.iB)
+		j.moveBodies(Vector2D{}, impulse, impulse)
+		j.prepareBodies()
+	}
+
+	c := positionOf(j.bodyB).Add(j.rB).Subtract(positionOf(j.bodyA)).Subtract(j.rA)
+	positionError := c.Length()
+	k11, k12, k22 := j.pointMass()
+	impulse := solve22(k11, k12, k22, c).Negate()
+	j.moveBodies(impulse, j.rA.Cross(impulse), j.rB.Cross(impulse))
+
+	return positionError <= linearSlop && angularError <= angularSlop
+}
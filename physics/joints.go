@@ -0,0 +1,101 @@
+package physics
+
+import (
+	"2d_game_engine/physics/joint"
+)
+
+//-----------------------------------------------------------------------------
+// Joints are solved together with contacts. Each body keeps a list of the
+// joints attached to it, used to filter collisions between connected bodies
+// and to keep them in the same sleeping island.
+//-----------------------------------------------------------------------------
+
+// AddJoint adds a joint to the world. Its bodies should be added as well.
+func (w *World) AddJoint(j joint.Joint) {
+	w.joints = append(w.joints, j)
+	for _, b := range jointBodies(j) {
+		w.jointEdges[b] = append(w.jointEdges[b], j)
+		if b.GetIsSleeping() {
+			w.wakeIsland(b)
+		}
+	}
+}
+
+// RemoveJoint removes a joint from the world, returning false if it was not
+// found. Its bodies are woken so they react to being released.
+func (w *World) RemoveJoint(j joint.Joint) bool {
+	for i, other := range w.joints {
+		if other == j {
+			w.joints = append(w.joints[:i], w.joints[i+1:]...)
+			for _, b := range jointBodies(j) {
+				w.jointEdges[b] = removeJoint(w.jointEdges[b], j)
+				if len(w.jointEdges[b]) == 0 {
+					delete(w.jointEdges, b)
+				}
+				if b.GetIsSleeping() {
+					w.wakeIsland(b)
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (w *World) GetJoints() []joint.Joint {
+	return w.joints
+}
+
+// GetBodyJoints returns the joints attached to a body.
+func (w *World) GetBodyJoints(b *Body) []joint.Joint {
+	return w.jointEdges[b]
+}
+
+// jointsAllowCollision reports whether every joint between two bodies lets
+// them collide.
+func (w *World) jointsAllowCollision(bodyA, bodyB *Body) bool {
+	for _, j := range w.jointEdges[bodyA] {
+		if j.GetCollideConnected() {
+			continue
+		}
+		if (j.GetBodyA() == bodyA && j.GetBodyB() == bodyB) || (j.GetBodyA() == bodyB && j.GetBodyB() == bodyA) {
+			return false
+		}
+	}
+	return true
+}
+
+// activeJoints returns the joints with at least one body being simulated.
+func (w *World) activeJoints() []joint.Joint {
+	active := make([]joint.Joint, 0, len(w.joints))
+	for _, j := range w.joints {
+		for _, b := range jointBodies(j) {
+			if isActive(b) {
+				active = append(active, j)
+				break
+			}
+		}
+	}
+	return active
+}
+
+// jointBodies returns the non-nil bodies of a joint.
+func jointBodies(j joint.Joint) []*Body {
+	bodies := make([]*Body, 0, 2)
+	if b := j.GetBodyA(); b != nil {
+		bodies = append(bodies, b)
+	}
+	if b := j.GetBodyB(); b != nil {
+		bodies = append(bodies, b)
+	}
+	return bodies
+}
+
+func removeJoint(joints []joint.Joint, j joint.Joint) []joint.Joint {
+	for i, other := range joints {
+		if other == j {
+			return append(joints[:i], joints[i+1:]...)
+		}
+	}
+	return joints
+}
@@ -0,0 +1,202 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics/body"
+	"2d_game_engine/physics/joint"
+)
+
+// newJointWorld returns a world without sleeping holding a static anchor
+// body at the origin.
+func newJointWorld() (*World, *Body) {
+	world := NewWorld()
+	world.SetSleepingEnabled(false)
+	anchor := body.FromRectangle(Vector2D{}, 10, 10)
+	anchor.SetIsStatic(true)
+	world.AddBody(anchor)
+	return world, anchor
+}
+
+func TestDistanceJointKeepsLength(t *testing.T) {
+	world, anchor := newJointWorld()
+	bob := body.FromCircle(Vector2D{X: 100, Y: 0}, 5)
+	world.AddBody(bob)
+	rod := joint.NewDistanceJoint(anchor, bob, anchor.GetPosition(), bob.GetPosition())
+	world.AddJoint(rod)
+
+	lowest := math.Inf(-1)
+	for i := 0; i < 180; i++ {
+		world.Step(1.0 / 60)
+		if length := bob.GetPosition().Length(); math.Abs(length-100) > 1 {
+			t.Fatalf("step %d: pendulum length %v, want 100", i, length)
+		}
+		lowest = math.Max(lowest, bob.GetPosition().Y)
+	}
+	if lowest < 99 {
+		t.Errorf("pendulum only swung down to y=%v", lowest)
+	}
+}
+
+func TestRopeJointOnlyLimitsStretch(t *testing.T) {
+	world, anchor := newJointWorld()
+	bob := body.FromCircle(Vector2D{X: 0, Y: 20}, 5)
+	world.AddBody(bob)
+	world.AddJoint(joint.NewRopeJoint(anchor, bob, anchor.GetPosition(), bob.GetPosition(), 100))
+
+	// The bob falls freely until the rope goes taut, then hangs from it.
+	stepFor(world, 0.2)
+	if y := bob.GetPosition().Y; y <= 20 || y >= 99 {
+		t.Errorf("bob at y=%v after 0.2 s, want it falling on a slack rope", y)
+	}
+	stepFor(world, 3)
+	if y := bob.GetPosition().Y; math.Abs(y-100) > 1 {
+		t.Errorf("bob hangs at y=%v, want the rope's length of 100", y)
+	}
+}
+
+func TestSpringJointSettles(t *testing.T) {
+	world, anchor := newJointWorld()
+	bob := body.FromCircle(Vector2D{X: 0, Y: 100}, 5)
+	bob.SetFrictionAir(0)
+	world.AddBody(bob)
+	const stiffness, damping = 5.0, 0.5
+	world.AddJoint(joint.NewSpringJoint(anchor, bob, anchor.GetPosition(), bob.GetPosition(), stiffness, damping))
+
+	// The spring stretches until it holds the bob's weight: k·x = m·g.
+	stepFor(world, 10)
+	want := 100 + bob.GetMass()*DefaultGravity.Y/stiffness
+	if y := bob.GetPosition().Y; math.Abs(y-want) > 1 {
+		t.Errorf("bob settles at y=%v, want %v", y, want)
+	}
+}
+
+func TestRevoluteJointHinge(t *testing.T) {
+	world, anchor := newJointWorld()
+	bar := body.FromRectangle(Vector2D{X: 50, Y: 0}, 100, 10)
+	world.AddBody(bar)
+	hinge := joint.NewRevoluteJoint(anchor, bar, Vector2D{})
+	hinge.SetEnableLimit(true)
+	hinge.SetLimits(-math.Pi/4, math.Pi/4)
+	world.AddJoint(hinge)
+
+	for i := 0; i < 120; i++ {
+		world.Step(1.0 / 60)
+		if drift := hinge.GetAnchorB().Subtract(hinge.GetAnchorA()).Length(); drift > 1 {
+			t.Fatalf("step %d: hinge anchors %v apart", i, drift)
+		}
+	}
+	// The bar swings down (clockwise on screen) until it hits the limit.
+	if angle := hinge.GetJointAngle(); math.Abs(angle-math.Pi/4) > 0.05 {
+		t.Errorf("bar rests at %v radians, want the upper limit %v", angle, math.Pi/4)
+	}
+}
+
+func TestRevoluteJointMotor(t *testing.T) {
+	world, anchor := newJointWorld()
+	world.SetGravity(Vector2D{})
+	wheel := body.FromCircle(Vector2D{}, 20)
+	world.AddBody(wheel)
+	motor := joint.NewRevoluteJoint(anchor, wheel, Vector2D{})
+	motor.SetEnableMotor(true)
+	motor.SetMotorSpeed(2)
+	motor.SetMaxMotorTorque(1e9)
+	world.AddJoint(motor)
+
+	stepFor(world, 1)
+	if speed := motor.GetJointSpeed(); math.Abs(speed-2) > 0.01 {
+		t.Errorf("wheel spins at %v rad/s, want the motor's 2", speed)
+	}
+}
+
+func TestWeldJointHoldsBodiesTogether(t *testing.T) {
+	world := NewWorld()
+	world.SetSleepingEnabled(false)
+	left := body.FromRectangle(Vector2D{X: 0, Y: 0}, 20, 20)
+	right := body.FromRectangle(Vector2D{X: 20, Y: 0}, 20, 20)
+	world.AddBody(left)
+	world.AddBody(right)
+	world.AddJoint(joint.NewWeldJoint(left, right, Vector2D{X: 10, Y: 0}))
+
+	// Spin the pair; the weld keeps them side by side.
+	left.SetAngularVelocity(3)
+	stepFor(world, 1)
+	offset := right.GetPosition().Subtract(left.GetPosition())
+	if math.Abs(offset.Length()-20) > 0.5 {
+		t.Errorf("welded bodies %v apart, want 20", offset.Length())
+	}
+	if turn := right.GetAngle() - left.GetAngle(); math.Abs(turn) > 1 {
+		t.Errorf("welded bodies turned %v degrees relative to each other", turn)
+	}
+}
+
+func TestPrismaticJointSlidesAlongAxis(t *testing.T) {
+	world, anchor := newJointWorld()
+	slider := body.FromRectangle(Vector2D{X: 0, Y: 0}, 10, 10)
+	world.AddBody(slider)
+	// A rail sloping down to the right at 45 degrees, 50 pixels long.
+	axis := Vector2D{X: 1, Y: 1}.Normalize()
+	rail := joint.NewPrismaticJoint(anchor, slider, Vector2D{}, axis)
+	rail.SetEnableLimit(true)
+	rail.SetLimits(0, 50)
+	world.AddJoint(rail)
+
+	stepFor(world, 2)
+	position := slider.GetPosition()
+	if off := math.Abs(position.Cross(axis)); off > 0.5 {
+		t.Errorf("slider left the rail by %v", off)
+	}
+	if translation := rail.GetJointTranslation(); math.Abs(translation-50) > 1 {
+		t.Errorf("slider stopped at %v along the rail, want the limit of 50", translation)
+	}
+	if math.Abs(slider.GetAngle()) > 1 {
+		t.Errorf("slider turned %v degrees", slider.GetAngle())
+	}
+}
+
+func TestMouseJointDragsBody(t *testing.T) {
+	world := NewWorld()
+	world.SetGravity(Vector2D{})
+	world.SetSleepingEnabled(false)
+	box := body.FromRectangle(Vector2D{}, 20, 20)
+	world.AddBody(box)
+	mouse := joint.NewMouseJoint(box, Vector2D{}, 1000*box.GetMass())
+	world.AddJoint(mouse)
+
+	mouse.SetTarget(Vector2D{X: 100, Y: 50})
+	stepFor(world, 3)
+	if distance := box.GetPosition().Subtract(Vector2D{X: 100, Y: 50}).Length(); distance > 1 {
+		t.Errorf("box is %v from the mouse after three seconds", distance)
+	}
+}
+
+func TestJointedBodiesDoNotCollide(t *testing.T) {
+	world := NewWorld()
+	world.SetGravity(Vector2D{})
+	a := body.FromRectangle(Vector2D{X: 0, Y: 0}, 20, 20)
+	b := body.FromRectangle(Vector2D{X: 15, Y: 0}, 20, 20)
+	world.AddBody(a)
+	world.AddBody(b)
+	rope := joint.NewRopeJoint(a, b, a.GetPosition(), b.GetPosition(), 100)
+	world.AddJoint(rope)
+	started := touching(world)
+
+	world.Step(1.0 / 60)
+	if started[pairOf(a, b)] {
+		t.Errorf("overlapping jointed bodies collided")
+	}
+	if len(world.GetBodyJoints(a)) != 1 {
+		t.Errorf("GetBodyJoints() = %v, want the rope", world.GetBodyJoints(a))
+	}
+
+	// Removing a body takes its joints with it, and the bodies collide again.
+	if !world.RemoveBody(b) || len(world.GetJoints()) != 0 || len(world.GetBodyJoints(a)) != 0 {
+		t.Fatalf("removing a body left joints %v", world.GetJoints())
+	}
+	world.AddBody(b)
+	world.Step(1.0 / 60)
+	if !started[pairOf(a, b)] {
+		t.Errorf("bodies did not collide once the joint was gone")
+	}
+}
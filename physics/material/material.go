@@ -0,0 +1,183 @@
+// Package material describes what bodies are made of: how dense they are, how
+// much they grip and how much they bounce, and how two materials in contact
+// combine those values.
+package material
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// CombineRule decides how the friction or restitution of two touching
+// materials is combined into one value for the contact.
+type CombineRule int
+
+// When two materials ask for different rules, the later one in this list
+// wins. Max comes last, so a grippy or bouncy material gets its way whatever
+// it touches; Min only wins against Average, which is why Ice is still
+// slippery on Wood but takes Rubber's grip on Rubber.
+const (
+	CombineAverage CombineRule = iota
+	CombineMin
+	CombineMultiply
+	CombineMax
+)
+
+var combineRuleNames = [...]string{
+	CombineAverage:  "average",
+	CombineMin:      "min",
+	CombineMultiply: "multiply",
+	CombineMax:      "max",
+}
+
+func (r CombineRule) String() string {
+	if r < 0 || int(r) >= len(combineRuleNames) {
+		return fmt.Sprintf("CombineRule(%d)", int(r))
+	}
+	return combineRuleNames[r]
+}
+
+// MarshalText writes the rule's name, so rules appear as strings in data
+// files.
+func (r CombineRule) MarshalText() ([]byte, error) {
+	if r < 0 || int(r) >= len(combineRuleNames) {
+		return nil, fmt.Errorf("unknown combine rule %d", int(r))
+	}
+	return []byte(combineRuleNames[r]), nil
+}
+
+// UnmarshalText reads a rule by name: "average", "min", "max" or "multiply".
+func (r *CombineRule) UnmarshalText(text []byte) error {
+	name := strings.ToLower(strings.TrimSpace(string(text)))
+	for rule, ruleName := range combineRuleNames {
+		if name == ruleName {
+			*r = CombineRule(rule)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown combine rule %q", text)
+}
+
+// Combine applies the rule to a pair of values.
+func (r CombineRule) Combine(a, b float64) float64 {
+	switch r {
+	case CombineMin:
+		return math.Min(a, b)
+	case CombineMax:
+		return math.Max(a, b)
+	case CombineMultiply:
+		return a * b
+	default:
+		return (a + b) / 2
+	}
+}
+
+// PairRule returns the rule used between materials asking for a and b.
+func PairRule(a, b CombineRule) CombineRule {
+	if b > a {
+		return b
+	}
+	return a
+}
+
+// Material holds the surface and mass properties shared by bodies made of
+// the same stuff. Density is mass per square pixel; friction and frictionAir
+// follow the body's conventions.
+type Material struct {
+	Name               string      `json:"name,omitempty"`
+	Density            float64     `json:"density"`
+	Friction           float64     `json:"friction"`
+	FrictionStatic     float64     `json:"frictionStatic"`
+	FrictionAir        float64     `json:"frictionAir"`
+	Restitution        float64     `json:"restitution"`
+	FrictionCombine    CombineRule `json:"frictionCombine"`
+	RestitutionCombine CombineRule `json:"restitutionCombine"`
+}
+
+// validate reports the first property of a material that is negative or NaN.
+func (m Material) validate() error {
+	properties := []struct {
+		name  string
+		value float64
+	}{
+		{"density", m.Density},
+		{"friction", m.Friction},
+		{"frictionStatic", m.FrictionStatic},
+		{"frictionAir", m.FrictionAir},
+		{"restitution", m.Restitution},
+	}
+	for _, property := range properties {
+		if math.IsNaN(property.value) || property.value < 0 {
+			return fmt.Errorf("%s must not be negative or NaN, got %v", property.name, property.value)
+		}
+	}
+	return nil
+}
+
+// Default is what new bodies are made of. Its combine rules give the
+// engine's usual behaviour: the slipperier surface sets the friction and the
+// bouncier one sets the restitution.
+var Default = Material{
+	Name:               "default",
+	Density:            0.001,
+	Friction:           0.1,
+	FrictionStatic:     0.5,
+	FrictionAir:        0.01,
+	Restitution:        0,
+	FrictionCombine:    CombineMin,
+	RestitutionCombine: CombineMax,
+}
+
+// Presets for common materials, relative to Default as roughly the density
+// of water.
+var (
+	Wood = Material{
+		Name: "wood", Density: 0.0007, Friction: 0.4, FrictionStatic: 0.6, FrictionAir: 0.01,
+		Restitution: 0.2, FrictionCombine: CombineAverage, RestitutionCombine: CombineAverage,
+	}
+	Metal = Material{
+		Name: "metal", Density: 0.0078, Friction: 0.3, FrictionStatic: 0.5, FrictionAir: 0.01,
+		Restitution: 0.1, FrictionCombine: CombineAverage, RestitutionCombine: CombineAverage,
+	}
+	Stone = Material{
+		Name: "stone", Density: 0.0025, Friction: 0.6, FrictionStatic: 0.8, FrictionAir: 0.01,
+		Restitution: 0.05, FrictionCombine: CombineAverage, RestitutionCombine: CombineAverage,
+	}
+	Glass = Material{
+		Name: "glass", Density: 0.0025, Friction: 0.2, FrictionStatic: 0.4, FrictionAir: 0.01,
+		Restitution: 0.3, FrictionCombine: CombineAverage, RestitutionCombine: CombineAverage,
+	}
+	Ice = Material{
+		Name: "ice", Density: 0.0009, Friction: 0.02, FrictionStatic: 0.05, FrictionAir: 0.01,
+		Restitution: 0.05, FrictionCombine: CombineMin, RestitutionCombine: CombineAverage,
+	}
+	Rubber = Material{
+		Name: "rubber", Density: 0.0011, Friction: 0.8, FrictionStatic: 1.0, FrictionAir: 0.01,
+		Restitution: 0.8, FrictionCombine: CombineMax, RestitutionCombine: CombineMax,
+	}
+)
+
+// Presets lists the built-in materials.
+func Presets() []Material {
+	return []Material{Default, Wood, Metal, Stone, Glass, Ice, Rubber}
+}
+
+// Pair is the friction and restitution used by a contact between two
+// materials.
+type Pair struct {
+	Friction       float64
+	FrictionStatic float64
+	Restitution    float64
+}
+
+// Combine works out the contact values between materials a and b.
+func Combine(a, b Material) Pair {
+	friction := PairRule(a.FrictionCombine, b.FrictionCombine)
+	restitution := PairRule(a.RestitutionCombine, b.RestitutionCombine)
+	return Pair{
+		Friction:       friction.Combine(a.Friction, b.Friction),
+		FrictionStatic: friction.Combine(a.FrictionStatic, b.FrictionStatic),
+		Restitution:    restitution.Combine(a.Restitution, b.Restitution),
+	}
+}
@@ -0,0 +1,93 @@
+package material
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPairRule(t *testing.T) {
+	tests := []struct {
+		a, b, want CombineRule
+	}{
+		{CombineAverage, CombineAverage, CombineAverage},
+		{CombineAverage, CombineMin, CombineMin},
+		{CombineMin, CombineMultiply, CombineMultiply},
+		{CombineMax, CombineMin, CombineMax},
+		{CombineMultiply, CombineMax, CombineMax},
+	}
+	for _, test := range tests {
+		if got := PairRule(test.a, test.b); got != test.want {
+			t.Errorf("PairRule(%v, %v) = %v, want %v", test.a, test.b, got, test.want)
+		}
+		if got := PairRule(test.b, test.a); got != test.want {
+			t.Errorf("PairRule(%v, %v) = %v, want %v", test.b, test.a, got, test.want)
+		}
+	}
+}
+
+func TestCombine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Material
+		want Pair
+	}{
+		{"wood on wood", Wood, Wood, Pair{Friction: 0.4, FrictionStatic: 0.6, Restitution: 0.2}},
+		{"wood on stone averages", Wood, Stone, Pair{Friction: 0.5, FrictionStatic: 0.7, Restitution: 0.125}},
+		{"ice on wood is slippery", Ice, Wood, Pair{Friction: 0.02, FrictionStatic: 0.05, Restitution: 0.125}},
+		{"ice on rubber takes rubber's grip", Ice, Rubber, Pair{Friction: 0.8, FrictionStatic: 1.0, Restitution: 0.8}},
+		{"default on default", Default, Default, Pair{Friction: 0.1, FrictionStatic: 0.5, Restitution: 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, got := range []Pair{Combine(test.a, test.b), Combine(test.b, test.a)} {
+				if !pairNear(got, test.want) {
+					t.Errorf("Combine() = %+v, want %+v", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func pairNear(a, b Pair) bool {
+	near := func(x, y float64) bool { return x-y < 1e-12 && y-x < 1e-12 }
+	return near(a.Friction, b.Friction) && near(a.FrictionStatic, b.FrictionStatic) && near(a.Restitution, b.Restitution)
+}
+
+func TestCombineRuleText(t *testing.T) {
+	for _, rule := range []CombineRule{CombineAverage, CombineMin, CombineMultiply, CombineMax} {
+		text, err := rule.MarshalText()
+		if err != nil {
+			t.Fatalf("%v.MarshalText() error: %v", rule, err)
+		}
+		var back CombineRule
+		if err := back.UnmarshalText(text); err != nil || back != rule {
+			t.Errorf("UnmarshalText(%q) = %v, %v; want %v", text, back, err, rule)
+		}
+	}
+
+	var rule CombineRule
+	if err := rule.UnmarshalText([]byte(" MAX ")); err != nil || rule != CombineMax {
+		t.Errorf("UnmarshalText(\" MAX \") = %v, %v; want max", rule, err)
+	}
+	if err := rule.UnmarshalText([]byte("harmonic")); err == nil {
+		t.Errorf("UnmarshalText(\"harmonic\") did not fail")
+	}
+	if _, err := CombineRule(9).MarshalText(); err == nil {
+		t.Errorf("MarshalText of an unknown rule did not fail")
+	}
+}
+
+func TestMaterialJSON(t *testing.T) {
+	data, err := json.Marshal(Ice)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if !strings.Contains(string(data), `"frictionCombine":"min"`) {
+		t.Errorf("Marshal() = %s, want the combine rule by name", data)
+	}
+	var back Material
+	if err := json.Unmarshal(data, &back); err != nil || back != Ice {
+		t.Errorf("Unmarshal() = %+v, %v; want %+v", back, err, Ice)
+	}
+}
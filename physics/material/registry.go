@@ -0,0 +1,169 @@
+package material
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Registry looks materials up by name so bodies and level data can refer to
+// them as "ice" or "rubber".
+type Registry struct {
+	materials map[string]Material
+}
+
+// NewRegistry creates a registry holding the presets.
+func NewRegistry() *Registry {
+	r := &Registry{materials: make(map[string]Material)}
+	for _, m := range Presets() {
+		r.Register(m)
+	}
+	return r
+}
+
+// Register adds a material under its name, replacing any with the same name.
+// Names are not case sensitive.
+func (r *Registry) Register(m Material) {
+	r.materials[strings.ToLower(m.Name)] = m
+}
+
+// Get returns the material with the given name.
+func (r *Registry) Get(name string) (Material, bool) {
+	m, ok := r.materials[strings.ToLower(name)]
+	return m, ok
+}
+
+// MustGet returns the material with the given name, panicking if there is
+// none. It suits names fixed in code.
+func (r *Registry) MustGet(name string) Material {
+	m, ok := r.Get(name)
+	if !ok {
+		panic(fmt.Sprintf("material %q is not registered", name))
+	}
+	return m
+}
+
+// Names returns the registered names in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.materials))
+	for name := range r.materials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//-----------------------------------------------------------------------------
+// Loading
+//-----------------------------------------------------------------------------
+
+// LoadJSON registers the materials in a JSON object keyed by name:
+//
+//	{
+//	  "mud":        {"base": "stone", "friction": 0.9, "restitution": 0},
+//	  "trampoline": {"restitution": 1.2, "restitutionCombine": "max"}
+//	}
+//
+// Each material starts as a copy of its base, which may be registered already
+// or defined in the same file, or of Default, and overrides the fields it
+// lists. Densities, frictions and restitutions must not be negative or NaN,
+// and no two names may differ only in case. Nothing is registered if the
+// data is invalid.
+func (r *Registry) LoadJSON(reader io.Reader) error {
+	var entries map[string]json.RawMessage
+	if err := json.NewDecoder(reader).Decode(&entries); err != nil {
+		return fmt.Errorf("failed to decode materials: %w", err)
+	}
+
+	loaded := make(map[string]Material, len(entries))
+	var resolve func(name string, visiting map[string]bool) (Material, error)
+	resolve = func(name string, visiting map[string]bool) (Material, error) {
+		if m, ok := loaded[name]; ok {
+			return m, nil
+		}
+		if visiting[name] {
+			return Material{}, fmt.Errorf("material %q is part of an inheritance cycle", name)
+		}
+		visiting[name] = true
+
+		raw := entries[name]
+		var header struct {
+			Base string `json:"base"`
+		}
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return Material{}, fmt.Errorf("material %q: %w", name, err)
+		}
+
+		m := Default
+		if header.Base != "" {
+			base := strings.ToLower(header.Base)
+			if _, inFile := entries[base]; inFile {
+				var err error
+				if m, err = resolve(base, visiting); err != nil {
+					return Material{}, err
+				}
+			} else if registered, ok := r.Get(base); ok {
+				m = registered
+			} else {
+				return Material{}, fmt.Errorf("material %q: unknown base %q", name, header.Base)
+			}
+		}
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return Material{}, fmt.Errorf("material %q: %w", name, err)
+		}
+		m.Name = name
+		if err := m.validate(); err != nil {
+			return Material{}, fmt.Errorf("material %q: %w", name, err)
+		}
+		loaded[name] = m
+		return m, nil
+	}
+
+	// Keys are matched case-insensitively, like registered names, so two
+	// keys differing only in case would define the same material twice.
+	keys := make([]string, 0, len(entries))
+	for name := range entries {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	lowered := make(map[string]json.RawMessage, len(entries))
+	original := make(map[string]string, len(entries))
+	for _, name := range keys {
+		key := strings.ToLower(name)
+		if other, ok := original[key]; ok {
+			return fmt.Errorf("materials %q and %q differ only in case", other, name)
+		}
+		lowered[key] = entries[name]
+		original[key] = name
+	}
+	entries = lowered
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := resolve(name, make(map[string]bool)); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		r.Register(loaded[name])
+	}
+	return nil
+}
+
+// LoadFile registers the materials in a JSON file. See LoadJSON.
+func (r *Registry) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open materials: %w", err)
+	}
+	defer file.Close()
+	return r.LoadJSON(file)
+}
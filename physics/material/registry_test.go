@@ -0,0 +1,100 @@
+package material
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegistryPresets(t *testing.T) {
+	registry := NewRegistry()
+	want := []string{"default", "glass", "ice", "metal", "rubber", "stone", "wood"}
+	if got := registry.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	if got, ok := registry.Get("Rubber"); !ok || got != Rubber {
+		t.Errorf("Get(\"Rubber\") = %+v, %v; want the preset", got, ok)
+	}
+	if _, ok := registry.Get("cheese"); ok {
+		t.Errorf("Get(\"cheese\") found a material")
+	}
+}
+
+func TestRegistryMustGetPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustGet of an unknown name did not panic")
+		}
+	}()
+	NewRegistry().MustGet("cheese")
+}
+
+func TestRegistryLoadJSON(t *testing.T) {
+	registry := NewRegistry()
+	err := registry.LoadJSON(strings.NewReader(`{
+		"Mud":        {"base": "stone", "friction": 0.9, "restitution": 0},
+		"wet mud":    {"base": "mud", "friction": 0.3},
+		"trampoline": {"restitution": 1.2, "restitutionCombine": "max"}
+	}`))
+	if err != nil {
+		t.Fatalf("LoadJSON() error: %v", err)
+	}
+
+	mud := registry.MustGet("mud")
+	if mud.Name != "mud" || mud.Friction != 0.9 || mud.Restitution != 0 || mud.Density != Stone.Density {
+		t.Errorf("mud = %+v, want stone with friction 0.9 and no bounce", mud)
+	}
+	wet := registry.MustGet("wet mud")
+	if wet.Friction != 0.3 || wet.Density != Stone.Density {
+		t.Errorf("wet mud = %+v, want mud with friction 0.3", wet)
+	}
+	trampoline := registry.MustGet("trampoline")
+	if trampoline.Restitution != 1.2 || trampoline.RestitutionCombine != CombineMax || trampoline.Density != Default.Density {
+		t.Errorf("trampoline = %+v, want default with restitution 1.2 combined by max", trampoline)
+	}
+}
+
+func TestRegistryLoadJSONRejectsBadData(t *testing.T) {
+	tests := []struct {
+		name, data, want string
+	}{
+		{"syntax", `{"mud": `, "failed to decode"},
+		{"unknown base", `{"mud": {"base": "clay"}}`, `unknown base "clay"`},
+		{"cycle", `{"a": {"base": "b"}, "b": {"base": "a"}}`, "inheritance cycle"},
+		{"bad rule", `{"mud": {"frictionCombine": "harmonic"}}`, "unknown combine rule"},
+		{"negative density", `{"mud": {"density": -1}}`, `material "mud": density`},
+		{"negative friction", `{"mud": {"friction": -0.5}}`, `material "mud": friction`},
+		{"negative restitution", `{"mud": {"restitution": -0.1}}`, `material "mud": restitution`},
+		{"names differing in case", `{"Mud": {"friction": 0.9}, "mud": {"friction": 0.2}}`, `"Mud" and "mud" differ only in case`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := NewRegistry()
+			err := registry.LoadJSON(strings.NewReader(test.data))
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("LoadJSON() error %v, want one mentioning %q", err, test.want)
+			}
+			if len(registry.Names()) != len(Presets()) {
+				t.Errorf("a failed load registered %v", registry.Names())
+			}
+		})
+	}
+}
+
+func TestRegistryLoadJSONRejectsNaNBase(t *testing.T) {
+	// JSON has no NaN, but a base registered in code can carry one.
+	registry := NewRegistry()
+	broken := Default
+	broken.Name = "broken"
+	broken.Friction = math.NaN()
+	registry.Register(broken)
+
+	err := registry.LoadJSON(strings.NewReader(`{"mud": {"base": "broken"}}`))
+	if err == nil || !strings.Contains(err.Error(), `material "mud": friction`) {
+		t.Fatalf("LoadJSON() error %v, want one naming mud's friction", err)
+	}
+	if _, ok := registry.Get("mud"); ok {
+		t.Errorf("a failed load registered mud")
+	}
+}
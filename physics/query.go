@@ -0,0 +1,173 @@
+package physics
+
+import (
+	"sort"
+
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/geometry"
+)
+
+//-----------------------------------------------------------------------------
+// World queries: ray casts, shape casts and overlap tests, narrowed down by
+// the broad phase. Every query takes a collision filter that bodies must pass
+// (use collision.DefaultFilter to match everything). Casts ignore sensors,
+// overlap queries include them.
+//-----------------------------------------------------------------------------
+
+type AABB = geometry.AABB
+
+// RaycastHit is a body hit by a ray.
+type RaycastHit struct {
+	Body     *Body
+	Point    Vector2D
+	Normal   Vector2D
+	Fraction float64 // Distance along the ray, from 0 at the start to 1 at the end
+}
+
+// ShapeCastHit is a body hit by a moving shape.
+type ShapeCastHit struct {
+	Body     *Body
+	Point    Vector2D // Closest point on the body at impact
+	Normal   Vector2D // Surface normal of the body, facing the cast shape
+	Fraction float64  // Fraction of the translation travelled before impact
+}
+
+// RayCast returns the closest body hit by the segment from -> to.
+func (w *World) RayCast(from, to Vector2D, filter collision.Filter) (RaycastHit, bool) {
+	w.updateBroadPhase()
+
+	var closest RaycastHit
+	found := false
+	w.broadPhase.RayCast(from, to, func(id int, maxFraction float64) float64 {
+		b := w.bodyByID[id]
+		if !w.queryMatches(b, filter) || b.GetIsSensor() {
+			return maxFraction
+		}
+		hit, ok := collision.RayCast(b.GetWorldShape(), from, to)
+		if !ok || hit.Fraction > maxFraction {
+			return maxFraction
+		}
+		if found && hit.Fraction == closest.Fraction && closest.Body.GetID() < id {
+			return maxFraction
+		}
+		closest = RaycastHit{Body: b, Point: hit.Point, Normal: hit.Normal, Fraction: hit.Fraction}
+		found = true
+		return hit.Fraction
+	})
+	return closest, found
+}
+
+// RayCastAll returns every body hit by the segment from -> to, nearest first.
+func (w *World) RayCastAll(from, to Vector2D, filter collision.Filter) []RaycastHit {
+	w.updateBroadPhase()
+
+	hits := make([]RaycastHit, 0)
+	w.broadPhase.RayCast(from, to, func(id int, maxFraction float64) float64 {
+		b := w.bodyByID[id]
+		if !w.queryMatches(b, filter) || b.GetIsSensor() {
+			return maxFraction
+		}
+		if hit, ok := collision.RayCast(b.GetWorldShape(), from, to); ok {
+			hits = append(hits, RaycastHit{Body: b, Point: hit.Point, Normal: hit.Normal, Fraction: hit.Fraction})
+		}
+		return maxFraction
+	})
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Fraction != hits[j].Fraction {
+			return hits[i].Fraction < hits[j].Fraction
+		}
+		return hits[i].Body.GetID() < hits[j].Body.GetID()
+	})
+	return hits
+}
+
+// shapeCastSeparating is the largest approach (translation along the contact
+// normal) at which a shape cast from touching counts as moving away.
+const shapeCastSeparating = 1e-9
+
+// ShapeCast moves a world-space shape by translation and returns the first
+// body it touches. Bodies the shape already overlaps, or already touches while
+// moving away from or along them, are ignored.
+func (w *World) ShapeCast(shape geometry.Shape, translation Vector2D, filter collision.Filter) (ShapeCastHit, bool) {
+	return w.shapeCast(shape, translation, filter, nil)
+}
+
+// shapeCast is ShapeCast, skipping the body ignore.
+func (w *World) shapeCast(shape geometry.Shape, translation Vector2D, filter collision.Filter, ignore *Body) (ShapeCastHit, bool) {
+	w.updateBroadPhase()
+
+	start := geometry.ShapeBounds(shape)
+	end := start.Translate(translation)
+
+	var closest ShapeCastHit
+	found := false
+	w.broadPhase.Query(start.Union(end), func(id int) bool {
+		b := w.bodyByID[id]
+		if b == ignore || !w.queryMatches(b, filter) || b.GetIsSensor() {
+			return true
+		}
+		result := collision.ShapeCast(shape, translation, b.GetWorldShape())
+		if !result.Hit || result.Overlapping {
+			return true
+		}
+		if result.Time == 0 && translation.Dot(result.Normal) <= shapeCastSeparating {
+			// Already touching but moving away or along the surface.
+			return true
+		}
+		if found && (result.Time > closest.Fraction || (result.Time == closest.Fraction && closest.Body.GetID() < id)) {
+			return true
+		}
+		closest = ShapeCastHit{Body: b, Point: result.PointB, Normal: result.Normal.Negate(), Fraction: result.Time}
+		found = true
+		return true
+	})
+	return closest, found
+}
+
+// QueryPoint returns the bodies containing a point, ordered by ID.
+func (w *World) QueryPoint(point Vector2D, filter collision.Filter) []*Body {
+	bounds := AABB{Min: point, Max: point}
+	return w.queryBodies(bounds, filter, func(b *Body) bool {
+		return collision.ContainsPoint(b.GetWorldShape(), point)
+	})
+}
+
+// QueryAABB returns the bodies whose bounds overlap a box, ordered by ID.
+func (w *World) QueryAABB(bounds AABB, filter collision.Filter) []*Body {
+	return w.queryBodies(bounds, filter, func(b *Body) bool {
+		return b.Bounds().Overlaps(bounds)
+	})
+}
+
+// QueryShape returns the bodies overlapping a world-space shape, ordered by
+// ID. A circle makes an explosion radius query.
+func (w *World) QueryShape(shape geometry.Shape, filter collision.Filter) []*Body {
+	return w.queryBodies(geometry.ShapeBounds(shape), filter, func(b *Body) bool {
+		return collision.TestOverlap(shape, b.GetWorldShape())
+	})
+}
+
+// queryBodies collects the bodies in bounds that pass the filter and test.
+func (w *World) queryBodies(bounds AABB, filter collision.Filter, test func(b *Body) bool) []*Body {
+	w.updateBroadPhase()
+
+	bodies := make([]*Body, 0)
+	w.broadPhase.Query(bounds, func(id int) bool {
+		b := w.bodyByID[id]
+		if w.queryMatches(b, filter) && test(b) {
+			bodies = append(bodies, b)
+		}
+		return true
+	})
+
+	sort.Slice(bodies, func(i, j int) bool {
+		return bodies[i].GetID() < bodies[j].GetID()
+	})
+	return bodies
+}
+
+// queryMatches reports whether a broad phase result may be returned by a query.
+func (w *World) queryMatches(b *Body, filter collision.Filter) bool {
+	return b != nil && b.GetShape() != nil && collision.ShouldCollide(filter, b.GetCollisionFilter())
+}
@@ -0,0 +1,150 @@
+package physics
+
+import (
+	"testing"
+
+	"2d_game_engine/physics/body"
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/geometry"
+)
+
+// cupOutline is a cup 60 wide and 60 tall, open at the top (Y down), with a
+// notch 20 wide whose floor is 20 above its base.
+var cupOutline = []Vector2D{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 40}, {X: 40, Y: 40}, {X: 40, Y: 0}, {X: 60, Y: 0}, {X: 60, Y: 60}, {X: 0, Y: 60}}
+
+func newCupWorld(t *testing.T) (*World, *Body) {
+	t.Helper()
+	world := NewWorld()
+	cup, err := body.FromConcavePolygon(Vector2D{}, cupOutline)
+	if err != nil {
+		t.Fatalf("FromConcavePolygon() error: %v", err)
+	}
+	// Put the outline's corner at the origin.
+	cup.SetPosition(geometry.Centroid(cupOutline))
+	cup.SetIsStatic(true)
+	world.AddBody(cup)
+	return world, cup
+}
+
+func TestShapeCastIntoConcaveBody(t *testing.T) {
+	world, cup := newCupWorld(t)
+	box := &geometry.Polygon{
+		Vertices: []Vector2D{{X: -5, Y: -5}, {X: 5, Y: -5}, {X: 5, Y: 5}, {X: -5, Y: 5}},
+		Position: Vector2D{X: 30, Y: -50},
+	}
+	hit, ok := world.ShapeCast(box, Vector2D{X: 0, Y: 200}, collision.DefaultFilter)
+	if !ok || hit.Body != cup {
+		t.Fatalf("ShapeCast() = %+v, %v; want a hit on the cup", hit, ok)
+	}
+	if travelled := hit.Fraction * 200; travelled < 84.5 || travelled > 85 {
+		t.Errorf("travelled %v pixels, want just under 85 to the notch floor", travelled)
+	}
+	if hit.Normal.Y > -0.99 {
+		t.Errorf("normal = %v, want up", hit.Normal)
+	}
+}
+
+// newRowWorld returns a row of static bodies along the X axis: a box at 100,
+// a ball at 200, a sensor at 150 and a box at 250 in a second category.
+func newRowWorld() (world *World, box, ball, sensor, other *Body) {
+	world = NewWorld()
+	box = body.FromRectangle(Vector2D{X: 100, Y: 0}, 20, 20)
+	ball = body.FromCircle(Vector2D{X: 200, Y: 0}, 10)
+	sensor = body.FromRectangle(Vector2D{X: 150, Y: 0}, 20, 20)
+	sensor.SetIsSensor(true)
+	other = body.FromRectangle(Vector2D{X: 250, Y: 0}, 20, 20)
+	other.SetCollisionFilter(collision.Filter{Category: 2, Mask: 0xFFFFFFFF})
+	for _, b := range []*Body{box, ball, sensor, other} {
+		b.SetIsStatic(true)
+		world.AddBody(b)
+	}
+	return world, box, ball, sensor, other
+}
+
+// firstCategoryOnly collides with the first category alone.
+var firstCategoryOnly = collision.Filter{Category: 1, Mask: 1}
+
+func TestRayCastFindsNearestBody(t *testing.T) {
+	world, box, _, _, _ := newRowWorld()
+	hit, ok := world.RayCast(Vector2D{}, Vector2D{X: 400, Y: 0}, collision.DefaultFilter)
+	if !ok || hit.Body != box {
+		t.Fatalf("RayCast() = %+v, %v; want a hit on the box", hit, ok)
+	}
+	if hit.Fraction != 90.0/400 || hit.Point != (Vector2D{X: 90, Y: 0}) || hit.Normal != (Vector2D{X: -1, Y: 0}) {
+		t.Errorf("RayCast() = %+v, want the box's left face at x=90", hit)
+	}
+
+	// Rays cast the other way find the far end first.
+	if hit, ok := world.RayCast(Vector2D{X: 400, Y: 0}, Vector2D{}, firstCategoryOnly); !ok || hit.Point != (Vector2D{X: 210, Y: 0}) {
+		t.Errorf("RayCast() backwards = %+v, %v; want the ball's right side", hit, ok)
+	}
+	if _, ok := world.RayCast(Vector2D{X: 0, Y: 50}, Vector2D{X: 400, Y: 50}, collision.DefaultFilter); ok {
+		t.Errorf("a ray passing below the row hit something")
+	}
+}
+
+func TestRayCastAllSkipsSensorsAndFilteredBodies(t *testing.T) {
+	world, box, ball, _, other := newRowWorld()
+	tests := []struct {
+		name   string
+		filter collision.Filter
+		want   []*Body
+	}{
+		{"default filter", collision.DefaultFilter, []*Body{box, ball, other}},
+		{"first category only", firstCategoryOnly, []*Body{box, ball}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hits := world.RayCastAll(Vector2D{}, Vector2D{X: 400, Y: 0}, test.filter)
+			if len(hits) != len(test.want) {
+				t.Fatalf("RayCastAll() hit %d bodies, want %d", len(hits), len(test.want))
+			}
+			for i, hit := range hits {
+				if hit.Body != test.want[i] {
+					t.Errorf("hit %d is body %d, want body %d", i, hit.Body.GetID(), test.want[i].GetID())
+				}
+				if i > 0 && hit.Fraction < hits[i-1].Fraction {
+					t.Errorf("hits are not ordered nearest first")
+				}
+			}
+		})
+	}
+}
+
+func TestOverlapQueries(t *testing.T) {
+	world, box, ball, sensor, other := newRowWorld()
+	tests := []struct {
+		name string
+		got  []*Body
+		want []*Body
+	}{
+		{"point in box", world.QueryPoint(Vector2D{X: 100, Y: 5}, collision.DefaultFilter), []*Body{box}},
+		{"point in sensor", world.QueryPoint(Vector2D{X: 150, Y: 0}, collision.DefaultFilter), []*Body{sensor}},
+		{"point between bodies", world.QueryPoint(Vector2D{X: 125, Y: 0}, collision.DefaultFilter), []*Body{}},
+		{"aabb", world.QueryAABB(AABB{Min: Vector2D{X: 90, Y: -5}, Max: Vector2D{X: 260, Y: 5}}, collision.DefaultFilter), []*Body{box, ball, sensor, other}},
+		{"aabb filtered", world.QueryAABB(AABB{Min: Vector2D{X: 90, Y: -5}, Max: Vector2D{X: 260, Y: 5}}, firstCategoryOnly), []*Body{box, ball, sensor}},
+		{"explosion", world.QueryShape(&geometry.Circle{Center: Vector2D{X: 175, Y: 0}, Radius: 20}, collision.DefaultFilter), []*Body{ball, sensor}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if len(test.got) != len(test.want) {
+				t.Fatalf("query found %d bodies, want %d", len(test.got), len(test.want))
+			}
+			for i := range test.got {
+				if test.got[i] != test.want[i] {
+					t.Errorf("result %d is body %d, want body %d", i, test.got[i].GetID(), test.want[i].GetID())
+				}
+			}
+		})
+	}
+}
+
+func TestQueryPointInConcaveBody(t *testing.T) {
+	world, cup := newCupWorld(t)
+	if bodies := world.QueryPoint(Vector2D{X: 30, Y: 20}, collision.DefaultFilter); len(bodies) != 0 {
+		t.Errorf("QueryPoint() in the cup's notch = %v, want nothing", bodies)
+	}
+	if bodies := world.QueryPoint(Vector2D{X: 30, Y: 50}, collision.DefaultFilter); len(bodies) != 1 || bodies[0] != cup {
+		t.Errorf("QueryPoint() in the cup's base = %v, want the cup", bodies)
+	}
+}
@@ -0,0 +1,135 @@
+package physics
+
+//-----------------------------------------------------------------------------
+// Sleeping: bodies at rest are grouped into islands of touching or jointed
+// bodies that fall asleep and wake up together.
+//-----------------------------------------------------------------------------
+
+// island is a group of bodies that were touching or jointed when they fell asleep.
+type island struct {
+	bodies []*Body
+}
+
+// canSleep reports whether a body takes part in sleeping at all.
+func canSleep(b *Body) bool {
+	return !b.GetIsStatic() && b.GetShape() != nil
+}
+
+// wakeTouchedIslands wakes every sleeping island touched by, or jointed to, an
+// awake body and returns true if any body woke up.
+func (w *World) wakeTouchedIslands() bool {
+	woke := false
+	wakeTouched := func(bodyA, bodyB *Body) {
+		for _, pair := range [2][2]*Body{{bodyA, bodyB}, {bodyB, bodyA}} {
+			sleeper, other := pair[0], pair[1]
+			if sleeper.GetIsSleeping() && !other.GetIsSleeping() && !other.GetIsStatic() {
+				w.wakeIsland(sleeper)
+				woke = true
+			}
+		}
+	}
+	for _, c := range w.solverContacts {
+		wakeTouched(c.bodyA, c.bodyB)
+	}
+	for _, j := range w.joints {
+		if j.GetBodyA() != nil && j.GetBodyB() != nil {
+			wakeTouched(j.GetBodyA(), j.GetBodyB())
+		}
+	}
+
+	// Bodies woken directly, for example by a force, take their island along.
+	for _, b := range w.bodies {
+		if isl, ok := w.islands[b.GetID()]; ok && !b.GetIsSleeping() {
+			w.wakeIsland(b)
+			woke = woke || len(isl.bodies) > 1
+		}
+	}
+	return woke
+}
+
+// wakeIsland wakes a body together with the island it fell asleep in.
+func (w *World) wakeIsland(b *Body) {
+	isl, ok := w.islands[b.GetID()]
+	if !ok {
+		b.SetIsSleeping(false)
+		return
+	}
+	for _, member := range isl.bodies {
+		if member.GetIsSleeping() {
+			member.SetIsSleeping(false)
+		}
+		delete(w.islands, member.GetID())
+	}
+}
+
+// updateSleeping tracks the motion of awake bodies and puts islands to sleep
+// once all their bodies have been quiet for timeToSleep seconds.
+func (w *World) updateSleeping(deltaTime float64) {
+	// Union-find over the awake bodies, linked by their contacts and joints.
+	index := make(map[int]int, len(w.bodies))
+	parent := make([]int, 0, len(w.bodies))
+	awake := make([]*Body, 0, len(w.bodies))
+	for _, b := range w.bodies {
+		if canSleep(b) && !b.GetIsSleeping() {
+			index[b.GetID()] = len(awake)
+			parent = append(parent, len(awake))
+			awake = append(awake, b)
+		}
+	}
+
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	union := func(bodyA, bodyB *Body) {
+		i, okA := index[bodyA.GetID()]
+		j, okB := index[bodyB.GetID()]
+		if okA && okB {
+			parent[find(i)] = find(j)
+		}
+	}
+
+	for _, c := range w.solverContacts {
+		union(c.bodyA, c.bodyB)
+	}
+	for _, j := range w.joints {
+		if j.GetBodyA() != nil && j.GetBodyB() != nil {
+			union(j.GetBodyA(), j.GetBodyB())
+		}
+	}
+
+	// An island may sleep once its most recently active body has been quiet
+	// long enough.
+	quietTime := make(map[int]float64)
+	for i, b := range awake {
+		quiet := b.UpdateMotion(deltaTime, w.sleepThreshold)
+		root := find(i)
+		if current, ok := quietTime[root]; !ok || quiet < current {
+			quietTime[root] = quiet
+		}
+	}
+
+	members := make(map[int][]*Body)
+	roots := make([]int, 0)
+	for i, b := range awake {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], b)
+	}
+
+	for _, root := range roots {
+		if quietTime[root] < w.timeToSleep {
+			continue
+		}
+		isl := &island{bodies: members[root]}
+		for _, b := range isl.bodies {
+			b.SetIsSleeping(true)
+			w.islands[b.GetID()] = isl
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package physics
+
+import (
+	"testing"
+
+	"2d_game_engine/physics/body"
+)
+
+// newTowerScene returns a floor with two boxes stacked on it and a ball far
+// off to one side.
+func newTowerScene() (*World, *Body, *Body, *Body) {
+	world := NewWorld()
+	floor := body.FromRectangle(Vector2D{X: 0, Y: 20}, 1000, 40)
+	floor.SetIsStatic(true)
+	world.AddBody(floor)
+
+	bottom := body.FromRectangle(Vector2D{X: 0, Y: -10}, 20, 20)
+	top := body.FromRectangle(Vector2D{X: 0, Y: -30}, 20, 20)
+	ball := body.FromCircle(Vector2D{X: 300, Y: -10}, 10)
+	world.AddBody(bottom)
+	world.AddBody(top)
+	world.AddBody(ball)
+	return world, bottom, top, ball
+}
+
+func stepFor(world *World, seconds float64) {
+	for i := 0; i < int(seconds*60); i++ {
+		world.Step(1.0 / 60)
+	}
+}
+
+func TestRestingBodiesFallAsleep(t *testing.T) {
+	world, bottom, top, ball := newTowerScene()
+	stepFor(world, 3)
+	for name, b := range map[string]*Body{"bottom": bottom, "top": top, "ball": ball} {
+		if !b.GetIsSleeping() {
+			t.Errorf("%s is still awake after three seconds at rest", name)
+		}
+	}
+
+	// Sleeping bodies stay put.
+	position := top.GetPosition()
+	stepFor(world, 1)
+	if top.GetPosition() != position {
+		t.Errorf("sleeping box moved from %v to %v", position, top.GetPosition())
+	}
+}
+
+func TestSleepingDisabled(t *testing.T) {
+	world, bottom, top, _ := newTowerScene()
+	stepFor(world, 3)
+	world.SetSleepingEnabled(false)
+	if bottom.GetIsSleeping() || top.GetIsSleeping() {
+		t.Fatalf("disabling sleeping left bodies asleep")
+	}
+	stepFor(world, 3)
+	if bottom.GetIsSleeping() || top.GetIsSleeping() {
+		t.Errorf("bodies fell asleep with sleeping disabled")
+	}
+}
+
+func TestForceWakesIsland(t *testing.T) {
+	world, bottom, top, ball := newTowerScene()
+	stepFor(world, 3)
+
+	// Pushing the bottom box wakes the box resting on it, but not the ball.
+	bottom.ApplyForce(Vector2D{X: 50, Y: 0}, bottom.GetPosition())
+	world.Step(1.0 / 60)
+	if bottom.GetIsSleeping() || top.GetIsSleeping() {
+		t.Errorf("pushing the bottom box left its island asleep")
+	}
+	if !ball.GetIsSleeping() {
+		t.Errorf("pushing the tower woke the ball, which is not touching it")
+	}
+}
+
+func TestImpactWakesIsland(t *testing.T) {
+	world, bottom, top, ball := newTowerScene()
+	stepFor(world, 3)
+
+	thrown := body.FromCircle(Vector2D{X: -100, Y: -30}, 5)
+	thrown.SetVelocity(Vector2D{X: 600, Y: 0})
+	world.AddBody(thrown)
+	stepFor(world, 0.25)
+
+	if bottom.GetIsSleeping() || top.GetIsSleeping() {
+		t.Errorf("a body hitting the tower left it asleep")
+	}
+	if top.GetVelocity().X <= 0 && top.GetPosition().X <= 0 {
+		t.Errorf("the tower was not pushed by the impact")
+	}
+	if !ball.GetIsSleeping() {
+		t.Errorf("the impact woke the ball, which is not touching the tower")
+	}
+}
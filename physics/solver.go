@@ -0,0 +1,264 @@
+package physics
+
+import (
+	"math"
+
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/geometry"
+	"2d_game_engine/physics/material"
+)
+
+const (
+	// restitutionThreshold is the approach speed (pixels per second, about
+	// 1 m/s at the default gravity scale) below which contacts are treated as
+	// inelastic so bodies can come to rest.
+	restitutionThreshold = 100.0
+	// positionCorrection is the fraction of the remaining penetration
+	// resolved by each position iteration.
+	positionCorrection = 0.2
+	// maxPositionCorrection caps how far (pixels) a single position
+	// iteration may move a contact, avoiding overshoot on deep overlaps.
+	maxPositionCorrection = 8.0
+	// warmStartTolerance is the distance (pixels) within which a contact
+	// point is considered the same as one from the previous step.
+	warmStartTolerance = 2.0
+)
+
+// pairKey identifies a pair of bodies by their IDs, lowest first.
+type pairKey struct {
+	a, b int
+}
+
+func newPairKey(bodyA, bodyB *Body) pairKey {
+	if bodyA.GetID() > bodyB.GetID() {
+		return pairKey{a: bodyB.GetID(), b: bodyA.GetID()}
+	}
+	return pairKey{a: bodyA.GetID(), b: bodyB.GetID()}
+}
+
+// contactPoint is a manifold point along with the impulses accumulated for it.
+type contactPoint struct {
+	point          Vector2D
+	depth          float64
+	rA, rB         Vector2D // Offsets from each body's position
+	angleA, angleB float64  // Body angles when the offsets were taken
+	normalMass     float64
+	tangentMass    float64
+	normalImpulse  float64
+	tangentImpulse float64
+	velocityBias   float64
+}
+
+// contact is the persistent solver state between two touching bodies.
+type contact struct {
+	bodyA, bodyB   *Body
+	normal         Vector2D
+	points         []contactPoint
+	friction       float64
+	frictionStatic float64
+	restitution    float64
+	slop           float64
+	sensor         bool // Sensor contacts raise events but are not solved
+}
+
+// newContact builds a contact from a manifold, carrying over the impulses of
+// matching points from the previous step so the solver can warm start.
+func newContact(bodyA, bodyB *Body, manifold collision.Manifold, previous *contact) *contact {
+	pair := material.Combine(bodyA.GetMaterial(), bodyB.GetMaterial())
+	c := &contact{
+		bodyA:          bodyA,
+		bodyB:          bodyB,
+		normal:         manifold.Normal,
+		points:         make([]contactPoint, len(manifold.Contacts)),
+		friction:       pair.Friction,
+		frictionStatic: pair.FrictionStatic,
+		restitution:    pair.Restitution,
+		slop:           math.Max(bodyA.GetSlop(), bodyB.GetSlop()),
+	}
+
+	for i, point := range manifold.Contacts {
+		c.points[i] = contactPoint{point: point.Point, depth: point.Depth}
+		if previous == nil {
+			continue
+		}
+		for _, old := range previous.points {
+			if nearPoint(old.point, point.Point) {
+				c.points[i].normalImpulse = old.normalImpulse
+				c.points[i].tangentImpulse = old.tangentImpulse
+				break
+			}
+		}
+	}
+
+	return c
+}
+
+// inverseMasses returns the inverse mass and inertia of a body, treating
+// static bodies as immovable.
+func inverseMasses(b *Body) (float64, float64) {
+	if b.GetIsStatic() {
+		return 0, 0
+	}
+	return b.GetInverseMass(), b.GetInverseInertia()
+}
+
+// crossScalar returns the cross product of a scalar angular velocity and a vector.
+func crossScalar(w float64, r Vector2D) Vector2D {
+	return Vector2D{X: -w * r.Y, Y: w * r.X}
+}
+
+// relativeVelocity returns the velocity of bodyB relative to bodyA at a contact point.
+func (c *contact) relativeVelocity(cp *contactPoint) Vector2D {
+	velocityA := c.bodyA.GetVelocity().Add(crossScalar(c.bodyA.GetAngularVelocity(), cp.rA))
+	velocityB := c.bodyB.GetVelocity().Add(crossScalar(c.bodyB.GetAngularVelocity(), cp.rB))
+	return velocityB.Subtract(velocityA)
+}
+
+func (c *contact) tangent() Vector2D {
+	return Vector2D{X: c.normal.Y, Y: -c.normal.X}
+}
+
+// applyImpulse pushes the two bodies apart by an impulse acting on bodyB.
+func (c *contact) applyImpulse(impulse Vector2D, cp *contactPoint) {
+	c.bodyA.ApplyImpulse(impulse.Negate(), c.bodyA.GetPosition().Add(cp.rA))
+	c.bodyB.ApplyImpulse(impulse, c.bodyB.GetPosition().Add(cp.rB))
+}
+
+// prepare computes the effective masses and restitution bias of each point.
+func (c *contact) prepare() {
+	if FixedPoint {
+		c.prepareFixed()
+		return
+	}
+
+	inverseMassA, inverseInertiaA := inverseMasses(c.bodyA)
+	inverseMassB, inverseInertiaB := inverseMasses(c.bodyB)
+	tangent := c.tangent()
+
+	for i := range c.points {
+		cp := &c.points[i]
+		cp.rA = cp.point.Subtract(c.bodyA.GetPosition())
+		cp.rB = cp.point.Subtract(c.bodyB.GetPosition())
+		cp.angleA = c.bodyA.GetAngle()
+		cp.angleB = c.bodyB.GetAngle()
+
+		rnA := cp.rA.Cross(c.normal)
+		rnB := cp.rB.Cross(c.normal)
+		kNormal := inverseMassA + inverseMassB + inverseInertiaA*rnA*rnA + inverseInertiaB*rnB*rnB
+		if kNormal > 0 {
+			cp.normalMass = 1 / kNormal
+		}
+
+		rtA := cp.rA.Cross(tangent)
+		rtB := cp.rB.Cross(tangent)
+		kTangent := inverseMassA + inverseMassB + inverseInertiaA*rtA*rtA + inverseInertiaB*rtB*rtB
+		if kTangent > 0 {
+			cp.tangentMass = 1 / kTangent
+		}
+
+		// Bounce only when approaching fast enough.
+		cp.velocityBias = 0
+		approach := c.relativeVelocity(cp).Dot(c.normal)
+		if approach < -restitutionThreshold {
+			cp.velocityBias = -c.restitution * approach
+		}
+	}
+}
+
+// warmStart re-applies the impulses accumulated during the previous step.
+func (c *contact) warmStart() {
+	if FixedPoint {
+		c.warmStartFixed()
+		return
+	}
+
+	tangent := c.tangent()
+	for i := range c.points {
+		cp := &c.points[i]
+		impulse := c.normal.Multiply(cp.normalImpulse).Add(tangent.Multiply(cp.tangentImpulse))
+		c.applyImpulse(impulse, cp)
+	}
+}
+
+// solveVelocity runs one sequential impulse iteration over the contact,
+// friction first and then the non-penetration constraint.
+func (c *contact) solveVelocity() {
+	if FixedPoint {
+		c.solveVelocityFixed()
+		return
+	}
+
+	tangent := c.tangent()
+
+	for i := range c.points {
+		cp := &c.points[i]
+
+		// Coulomb friction: stick while within the static cone, otherwise slide.
+		vt := c.relativeVelocity(cp).Dot(tangent)
+		lambda := -vt * cp.tangentMass
+		newImpulse := cp.tangentImpulse + lambda
+		if math.Abs(newImpulse) > c.frictionStatic*cp.normalImpulse {
+			maxFriction := c.friction * cp.normalImpulse
+			newImpulse = math.Max(-maxFriction, math.Min(newImpulse, maxFriction))
+		}
+		lambda = newImpulse - cp.tangentImpulse
+		cp.tangentImpulse = newImpulse
+		c.applyImpulse(tangent.Multiply(lambda), cp)
+	}
+
+	for i := range c.points {
+		cp := &c.points[i]
+
+		vn := c.relativeVelocity(cp).Dot(c.normal)
+		lambda := -cp.normalMass * (vn - cp.velocityBias)
+		newImpulse := math.Max(cp.normalImpulse+lambda, 0)
+		lambda = newImpulse - cp.normalImpulse
+		cp.normalImpulse = newImpulse
+		c.applyImpulse(c.normal.Multiply(lambda), cp)
+	}
+}
+
+// solvePosition pushes the bodies apart along the normal to remove
+// penetration beyond the slop, moving and rotating them directly. The
+// translation applied is accumulated in each body's positionImpulse.
+func (c *contact) solvePosition() {
+	if FixedPoint {
+		c.solvePositionFixed()
+		return
+	}
+
+	inverseMassA, inverseInertiaA := inverseMasses(c.bodyA)
+	inverseMassB, inverseInertiaB := inverseMasses(c.bodyB)
+	if inverseMassA+inverseMassB == 0 {
+		return
+	}
+
+	for i := range c.points {
+		cp := &c.points[i]
+
+		// Follow the contact point as the bodies have moved since detection.
+		rA := geometry.RotationMat2(c.bodyA.GetAngle() - cp.angleA).MulVector(cp.rA)
+		rB := geometry.RotationMat2(c.bodyB.GetAngle() - cp.angleB).MulVector(cp.rB)
+		pointA := c.bodyA.GetPosition().Add(rA)
+		pointB := c.bodyB.GetPosition().Add(rB)
+		depth := cp.depth - pointB.Subtract(pointA).Dot(c.normal)
+
+		correction := math.Min((depth-c.slop)*positionCorrection, maxPositionCorrection)
+		if correction <= 0 {
+			continue
+		}
+
+		rnA := rA.Cross(c.normal)
+		rnB := rB.Cross(c.normal)
+		k := inverseMassA + inverseMassB + inverseInertiaA*rnA*rnA + inverseInertiaB*rnB*rnB
+		impulse := c.normal.Multiply(correction / k)
+
+		c.bodyA.SetPosition(c.bodyA.GetPosition().Subtract(impulse.Multiply(inverseMassA)))
+		c.bodyA.SetPositionImpulse(c.bodyA.GetPositionImpulse().Subtract(impulse.Multiply(inverseMassA)))
+		c.bodyA.SetAngle(c.bodyA.GetAngle() - geometry.Degrees(inverseInertiaA*rA.Cross(impulse)))
+
+		c.bodyB.SetPosition(c.bodyB.GetPosition().Add(impulse.Multiply(inverseMassB)))
+		c.bodyB.SetPositionImpulse(c.bodyB.GetPositionImpulse().Add(impulse.Multiply(inverseMassB)))
+		c.bodyB.SetAngle(c.bodyB.GetAngle() + geometry.Degrees(inverseInertiaB*rB.Cross(impulse)))
+	}
+}
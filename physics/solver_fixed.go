@@ -0,0 +1,228 @@
+package physics
+
+import (
+	"2d_game_engine/physics/fixed"
+)
+
+//-----------------------------------------------------------------------------
+// The contact solver in fixed point, used instead of the float64 methods in
+// solver.go when the engine is built with -tags fixedpoint. Each method reads
+// the contact and body state as Q32.32 numbers, runs the same sequential
+// impulse steps in the fixed package and writes the results back, so the
+// float64 fields only ever hold values on the fixed-point grid.
+//-----------------------------------------------------------------------------
+
+var (
+	fixedRestitutionThreshold  = fixed.FromFloat(restitutionThreshold)
+	fixedPositionCorrection    = fixed.FromFloat(positionCorrection)
+	fixedMaxPositionCorrection = fixed.FromFloat(maxPositionCorrection)
+	fixedWarmStartTolerance    = fixed.FromFloat(warmStartTolerance)
+)
+
+// fixedBody is a body's velocity and inverse masses in fixed point, for the
+// duration of one solver method.
+type fixedBody struct {
+	body            *Body
+	velocity        fixed.Vector
+	angularVelocity fixed.Fixed
+	inverseMass     fixed.Fixed
+	inverseInertia  fixed.Fixed
+}
+
+func newFixedBody(b *Body) fixedBody {
+	inverseMass, inverseInertia := inverseMasses(b)
+	return fixedBody{
+		body:            b,
+		velocity:        fixed.VectorFromFloat(b.GetVelocity()),
+		angularVelocity: fixed.FromFloat(b.GetAngularVelocity()),
+		inverseMass:     fixed.FromFloat(inverseMass),
+		inverseInertia:  fixed.FromFloat(inverseInertia),
+	}
+}
+
+// pointVelocity returns the velocity of the body at an offset from its position.
+func (b *fixedBody) pointVelocity(r fixed.Vector) fixed.Vector {
+	spin := fixed.Vector{X: b.angularVelocity.Neg().Mul(r.Y), Y: b.angularVelocity.Mul(r.X)}
+	return b.velocity.Add(spin)
+}
+
+// applyImpulse is Body.ApplyImpulse for an impulse at an offset from the
+// body's position. Static bodies have no inverse mass, so they do not move.
+func (b *fixedBody) applyImpulse(impulse, r fixed.Vector) {
+	b.velocity = b.velocity.Add(impulse.Multiply(b.inverseMass))
+	b.angularVelocity = b.angularVelocity.Add(r.Cross(impulse).Mul(b.inverseInertia))
+}
+
+// store writes the velocities back to the body.
+func (b *fixedBody) store() {
+	if b.body.GetIsStatic() {
+		return
+	}
+	b.body.SetVelocity(b.velocity.Float())
+	b.body.SetAngularVelocity(b.angularVelocity.Float())
+}
+
+// move is the position counterpart of applyImpulse: it shifts and turns the
+// body directly, accumulating the shift in its positionImpulse.
+func (b *fixedBody) move(impulse, r, position fixed.Vector, angle fixed.Fixed) {
+	if b.body.GetIsStatic() {
+		return
+	}
+	shift := impulse.Multiply(b.inverseMass)
+	b.body.SetPosition(position.Add(shift).Float())
+	b.body.SetPositionImpulse(fixed.VectorFromFloat(b.body.GetPositionImpulse()).Add(shift).Float())
+	b.body.SetAngle(angle.Add(fixed.Degrees(r.Cross(impulse).Mul(b.inverseInertia))).Float())
+}
+
+// effectiveMass returns the inverse of the mass the two bodies present to an
+// impulse along a direction at the given offsets, or zero if they are both
+// immovable.
+func effectiveMass(a, b *fixedBody, rA, rB, direction fixed.Vector) fixed.Fixed {
+	rnA := rA.Cross(direction)
+	rnB := rB.Cross(direction)
+	k := a.inverseMass.Add(b.inverseMass).
+		Add(a.inverseInertia.Mul(rnA).Mul(rnA)).
+		Add(b.inverseInertia.Mul(rnB).Mul(rnB))
+	if k <= 0 {
+		return 0
+	}
+	return fixed.One.Div(k)
+}
+
+// nearPoint reports whether a contact point is close enough to one from the
+// previous step to take over its impulses.
+func nearPoint(a, b Vector2D) bool {
+	if FixedPoint {
+		offset := fixed.VectorFromFloat(a).Subtract(fixed.VectorFromFloat(b))
+		return offset.LengthSquared() < fixedWarmStartTolerance.Mul(fixedWarmStartTolerance)
+	}
+	return a.Subtract(b).LengthSaqured() < warmStartTolerance*warmStartTolerance
+}
+
+// prepareFixed is prepare in fixed point.
+func (c *contact) prepareFixed() {
+	a, b := newFixedBody(c.bodyA), newFixedBody(c.bodyB)
+	normal := fixed.VectorFromFloat(c.normal)
+	tangent := fixed.Vector{X: normal.Y, Y: normal.X.Neg()}
+	positionA := fixed.VectorFromFloat(c.bodyA.GetPosition())
+	positionB := fixed.VectorFromFloat(c.bodyB.GetPosition())
+	restitution := fixed.FromFloat(c.restitution)
+
+	for i := range c.points {
+		cp := &c.points[i]
+		point := fixed.VectorFromFloat(cp.point)
+		rA, rB := point.Subtract(positionA), point.Subtract(positionB)
+		cp.rA, cp.rB = rA.Float(), rB.Float()
+		cp.angleA = c.bodyA.GetAngle()
+		cp.angleB = c.bodyB.GetAngle()
+
+		cp.normalMass = effectiveMass(&a, &b, rA, rB, normal).Float()
+		cp.tangentMass = effectiveMass(&a, &b, rA, rB, tangent).Float()
+
+		// Bounce only when approaching fast enough.
+		cp.velocityBias = 0
+		approach := b.pointVelocity(rB).Subtract(a.pointVelocity(rA)).Dot(normal)
+		if approach < fixedRestitutionThreshold.Neg() {
+			cp.velocityBias = restitution.Mul(approach).Neg().Float()
+		}
+	}
+}
+
+// warmStartFixed is warmStart in fixed point.
+func (c *contact) warmStartFixed() {
+	a, b := newFixedBody(c.bodyA), newFixedBody(c.bodyB)
+	normal := fixed.VectorFromFloat(c.normal)
+	tangent := fixed.Vector{X: normal.Y, Y: normal.X.Neg()}
+
+	for i := range c.points {
+		cp := &c.points[i]
+		impulse := normal.Multiply(fixed.FromFloat(cp.normalImpulse)).
+			Add(tangent.Multiply(fixed.FromFloat(cp.tangentImpulse)))
+		a.applyImpulse(impulse.Negate(), fixed.VectorFromFloat(cp.rA))
+		b.applyImpulse(impulse, fixed.VectorFromFloat(cp.rB))
+	}
+	a.store()
+	b.store()
+}
+
+// solveVelocityFixed is solveVelocity in fixed point.
+func (c *contact) solveVelocityFixed() {
+	a, b := newFixedBody(c.bodyA), newFixedBody(c.bodyB)
+	normal := fixed.VectorFromFloat(c.normal)
+	tangent := fixed.Vector{X: normal.Y, Y: normal.X.Neg()}
+	friction := fixed.FromFloat(c.friction)
+	frictionStatic := fixed.FromFloat(c.frictionStatic)
+
+	for i := range c.points {
+		cp := &c.points[i]
+		rA, rB := fixed.VectorFromFloat(cp.rA), fixed.VectorFromFloat(cp.rB)
+		normalImpulse := fixed.FromFloat(cp.normalImpulse)
+		tangentImpulse := fixed.FromFloat(cp.tangentImpulse)
+
+		// Coulomb friction: stick while within the static cone, otherwise slide.
+		vt := b.pointVelocity(rB).Subtract(a.pointVelocity(rA)).Dot(tangent)
+		lambda := vt.Mul(fixed.FromFloat(cp.tangentMass)).Neg()
+		newImpulse := tangentImpulse.Add(lambda)
+		if newImpulse.Abs() > frictionStatic.Mul(normalImpulse) {
+			maxFriction := friction.Mul(normalImpulse)
+			newImpulse = newImpulse.Clamp(maxFriction.Neg(), maxFriction)
+		}
+		lambda = newImpulse.Sub(tangentImpulse)
+		cp.tangentImpulse = newImpulse.Float()
+		a.applyImpulse(tangent.Multiply(lambda).Negate(), rA)
+		b.applyImpulse(tangent.Multiply(lambda), rB)
+	}
+
+	for i := range c.points {
+		cp := &c.points[i]
+		rA, rB := fixed.VectorFromFloat(cp.rA), fixed.VectorFromFloat(cp.rB)
+		normalImpulse := fixed.FromFloat(cp.normalImpulse)
+
+		vn := b.pointVelocity(rB).Subtract(a.pointVelocity(rA)).Dot(normal)
+		lambda := fixed.FromFloat(cp.normalMass).Mul(vn.Sub(fixed.FromFloat(cp.velocityBias))).Neg()
+		newImpulse := normalImpulse.Add(lambda).Max(0)
+		lambda = newImpulse.Sub(normalImpulse)
+		cp.normalImpulse = newImpulse.Float()
+		a.applyImpulse(normal.Multiply(lambda).Negate(), rA)
+		b.applyImpulse(normal.Multiply(lambda), rB)
+	}
+
+	a.store()
+	b.store()
+}
+
+// solvePositionFixed is solvePosition in fixed point.
+func (c *contact) solvePositionFixed() {
+	a, b := newFixedBody(c.bodyA), newFixedBody(c.bodyB)
+	if a.inverseMass.Add(b.inverseMass) == 0 {
+		return
+	}
+	normal := fixed.VectorFromFloat(c.normal)
+	slop := fixed.FromFloat(c.slop)
+
+	for i := range c.points {
+		cp := &c.points[i]
+		positionA := fixed.VectorFromFloat(c.bodyA.GetPosition())
+		positionB := fixed.VectorFromFloat(c.bodyB.GetPosition())
+		angleA := fixed.FromFloat(c.bodyA.GetAngle())
+		angleB := fixed.FromFloat(c.bodyB.GetAngle())
+
+		// Follow the contact point as the bodies have moved since detection.
+		rA := fixed.NewRotation(angleA.Sub(fixed.FromFloat(cp.angleA))).Apply(fixed.VectorFromFloat(cp.rA))
+		rB := fixed.NewRotation(angleB.Sub(fixed.FromFloat(cp.angleB))).Apply(fixed.VectorFromFloat(cp.rB))
+		pointA := positionA.Add(rA)
+		pointB := positionB.Add(rB)
+		depth := fixed.FromFloat(cp.depth).Sub(pointB.Subtract(pointA).Dot(normal))
+
+		correction := depth.Sub(slop).Mul(fixedPositionCorrection).Min(fixedMaxPositionCorrection)
+		if correction <= 0 {
+			continue
+		}
+
+		k := effectiveMass(&a, &b, rA, rB, normal)
+		impulse := normal.Multiply(correction.Mul(k))
+
+		a.move(impulse.Negate(), rA, positionA, angleA)
+		b.move(impulse, rB, positionB, angleB)
+	}
+}
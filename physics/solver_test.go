@@ -0,0 +1,153 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics/body"
+)
+
+// newCollidingBalls returns a world without gravity holding two balls on the
+// X axis, a moving towards b.
+func newCollidingBalls(restitution, speed float64) (*World, *Body, *Body) {
+	world := NewWorld()
+	world.SetGravity(Vector2D{})
+	world.SetSleepingEnabled(false)
+
+	a := body.FromCircle(Vector2D{X: 0, Y: 0}, 10)
+	b := body.FromCircle(Vector2D{X: 40, Y: 0}, 10)
+	for _, ball := range []*Body{a, b} {
+		ball.SetFrictionAir(0)
+		ball.SetFriction(0)
+		ball.SetRestitution(restitution)
+	}
+	a.SetVelocity(Vector2D{X: speed, Y: 0})
+	world.AddBody(a)
+	world.AddBody(b)
+	return world, a, b
+}
+
+func momentum(bodies ...*Body) Vector2D {
+	total := Vector2D{}
+	for _, b := range bodies {
+		total = total.Add(b.GetVelocity().Multiply(b.GetMass()))
+	}
+	return total
+}
+
+func TestSolverRestitution(t *testing.T) {
+	tests := []struct {
+		name         string
+		restitution  float64
+		speed        float64
+		wantA, wantB float64
+	}{
+		// Equal masses swap velocities when perfectly elastic.
+		{"elastic", 1, 600, 0, 600},
+		{"inelastic", 0, 600, 300, 300},
+		{"half", 0.5, 600, 150, 450},
+		// Below the restitution threshold contacts don't bounce.
+		{"slow", 1, 60, 30, 30},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			world, a, b := newCollidingBalls(test.restitution, test.speed)
+			before := momentum(a, b)
+			for i := 0; i < 60; i++ {
+				world.Step(1.0 / 60)
+			}
+			if got := a.GetVelocity().X; math.Abs(got-test.wantA) > 1 {
+				t.Errorf("a moves at %v, want %v", got, test.wantA)
+			}
+			if got := b.GetVelocity().X; math.Abs(got-test.wantB) > 1 {
+				t.Errorf("b moves at %v, want %v", got, test.wantB)
+			}
+			if after := momentum(a, b); after.Subtract(before).Length() > 1e-6*before.Length() {
+				t.Errorf("momentum went from %v to %v", before, after)
+			}
+		})
+	}
+}
+
+func TestSolverConservesMomentumUnequalMasses(t *testing.T) {
+	world, a, b := newCollidingBalls(1, 600)
+	b.SetMass(3 * a.GetMass())
+	before := momentum(a, b)
+	for i := 0; i < 60; i++ {
+		world.Step(1.0 / 60)
+	}
+	// An elastic collision with a body three times heavier sends the light
+	// one back at half its speed and the heavy one forward at half.
+	if got := a.GetVelocity().X; math.Abs(got+300) > 1 {
+		t.Errorf("light ball moves at %v, want -300", got)
+	}
+	if got := b.GetVelocity().X; math.Abs(got-300) > 1 {
+		t.Errorf("heavy ball moves at %v, want 300", got)
+	}
+	if after := momentum(a, b); after.Subtract(before).Length() > 1e-6*before.Length() {
+		t.Errorf("momentum went from %v to %v", before, after)
+	}
+}
+
+// slideBox sends a box sliding along a floor and returns how far it went in
+// two seconds and its final speed.
+func slideBox(friction float64) (float64, float64) {
+	world := NewWorld()
+	world.SetSleepingEnabled(false)
+	floor := body.FromRectangle(Vector2D{X: 0, Y: 20}, 4000, 40)
+	floor.SetIsStatic(true)
+	floor.SetFriction(friction)
+	world.AddBody(floor)
+
+	box := body.FromRectangle(Vector2D{X: 0, Y: -10}, 20, 20)
+	box.SetFriction(friction)
+	box.SetFrictionAir(0)
+	box.SetVelocity(Vector2D{X: 300, Y: 0})
+	world.AddBody(box)
+
+	for i := 0; i < 120; i++ {
+		world.Step(1.0 / 60)
+	}
+	return box.GetPosition().X, math.Abs(box.GetVelocity().X)
+}
+
+func TestSolverFriction(t *testing.T) {
+	distance, speed := slideBox(0)
+	if speed < 299 {
+		t.Errorf("box on a frictionless floor slowed to %v", speed)
+	}
+	if distance < 590 {
+		t.Errorf("box on a frictionless floor only slid %v", distance)
+	}
+
+	// Kinetic friction μg decelerates the box: 300 px/s at 0.5·980 px/s²
+	// stops in about 0.6 s, after about 92 px.
+	distance, speed = slideBox(0.5)
+	if speed > 1 {
+		t.Errorf("box with friction still moving at %v", speed)
+	}
+	if distance < 80 || distance > 105 {
+		t.Errorf("box with friction slid %v, want about 92", distance)
+	}
+}
+
+func TestSolverBallComesToRest(t *testing.T) {
+	world := NewWorld()
+	floor := body.FromRectangle(Vector2D{X: 0, Y: 20}, 400, 40)
+	floor.SetIsStatic(true)
+	world.AddBody(floor)
+	ball := body.FromCircle(Vector2D{X: 0, Y: -200}, 10)
+	world.AddBody(ball)
+
+	// A ball with no restitution lands without bouncing. It may sink in for
+	// the step it lands, but the position iterations push it back out.
+	for i := 0; i < 120; i++ {
+		world.Step(1.0 / 60)
+		if i > 60 && ball.GetVelocity().Y < -1 {
+			t.Fatalf("step %d: ball bounced up at %v", i, ball.GetVelocity().Y)
+		}
+	}
+	if y := ball.GetPosition().Y; y > -10+ball.GetSlop()+0.5 || y < -10.5 {
+		t.Errorf("ball rests at y=%v, want -10 give or take the slop", y)
+	}
+}
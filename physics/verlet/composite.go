@@ -0,0 +1,126 @@
+package verlet
+
+import (
+	"math"
+)
+
+// Composite is a group of particles and constraints built together, such as
+// a rope or a piece of cloth, with what is needed to draw it.
+type Composite struct {
+	Particles   []*Particle
+	Constraints []Constraint
+	// Outline lists the particles around the edge of a closed shape, such as
+	// a blob, in order, so it can be filled. It is nil for open shapes.
+	Outline []*Particle
+	// Columns and Rows give the grid size of cloth, whose particles are
+	// stored row by row. They are zero for other shapes.
+	Columns, Rows int
+}
+
+// NewRope creates a rope of segments links from start to end, pinned at
+// start.
+func NewRope(start, end Vector2D, segments int) *Composite {
+	segments = max(segments, 1)
+	c := &Composite{}
+	for i := 0; i <= segments; i++ {
+		c.Particles = append(c.Particles, NewParticle(start.Lerp(end, float64(i)/float64(segments))))
+	}
+	for i := 1; i <= segments; i++ {
+		c.Constraints = append(c.Constraints, NewDistanceConstraint(c.Particles[i-1], c.Particles[i]))
+	}
+	c.Particles[0].Pin()
+	return c
+}
+
+// NewCloth creates a rectangle of cloth hanging from its top edge, with
+// columns by rows particles (at least two of each). Every particle on the
+// top row is pinned, like a banner on a pole.
+func NewCloth(topLeft Vector2D, width, height float64, columns, rows int) *Composite {
+	columns, rows = max(columns, 2), max(rows, 2)
+	c := &Composite{Columns: columns, Rows: rows}
+	spacingX := width / float64(columns-1)
+	spacingY := height / float64(rows-1)
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			position := topLeft.Add(Vector2D{X: float64(column) * spacingX, Y: float64(row) * spacingY})
+			c.Particles = append(c.Particles, NewParticle(position))
+		}
+	}
+
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			p := c.At(column, row)
+			if column > 0 {
+				c.Constraints = append(c.Constraints, NewDistanceConstraint(c.At(column-1, row), p))
+			}
+			if row > 0 {
+				c.Constraints = append(c.Constraints, NewDistanceConstraint(c.At(column, row-1), p))
+			}
+		}
+	}
+	for column := 0; column < columns; column++ {
+		c.At(column, 0).Pin()
+	}
+	return c
+}
+
+// NewBlob creates a jelly blob: a ring of points particles around a centre
+// particle. The ring holds its edge lengths and bends back into shape, while
+// soft spokes to the centre let it squash.
+func NewBlob(center Vector2D, radius float64, points int) *Composite {
+	points = max(points, 3)
+	c := &Composite{}
+	middle := NewParticle(center)
+	for i := 0; i < points; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(points)
+		p := NewParticle(center.Add(Vector2D{X: math.Cos(angle), Y: math.Sin(angle)}.Multiply(radius)))
+		c.Particles = append(c.Particles, p)
+		c.Outline = append(c.Outline, p)
+	}
+	c.Particles = append(c.Particles, middle)
+
+	for i, p := range c.Outline {
+		next := c.Outline[(i+1)%points]
+		c.Constraints = append(c.Constraints, NewDistanceConstraint(p, next))
+
+		spoke := NewDistanceConstraint(p, middle)
+		spoke.SetStiffness(0.1)
+		c.Constraints = append(c.Constraints, spoke)
+
+		bend := NewAngleConstraint(c.Outline[(i+points-1)%points], p, next)
+		bend.SetStiffness(0.2)
+		c.Constraints = append(c.Constraints, bend)
+	}
+	return c
+}
+
+// At returns the cloth particle at a column and row.
+func (c *Composite) At(column, row int) *Particle {
+	return c.Particles[row*c.Columns+column]
+}
+
+// SetStiffness sets the stiffness of every distance constraint.
+func (c *Composite) SetStiffness(stiffness float64) {
+	for _, constraint := range c.Constraints {
+		if distance, ok := constraint.(*DistanceConstraint); ok {
+			distance.SetStiffness(stiffness)
+		}
+	}
+}
+
+// SetTearRatio lets every distance constraint tear when stretched to ratio
+// times its rest length. Zero disables tearing.
+func (c *Composite) SetTearRatio(ratio float64) {
+	for _, constraint := range c.Constraints {
+		if distance, ok := constraint.(*DistanceConstraint); ok {
+			distance.SetTearRatio(ratio)
+		}
+	}
+}
+
+// SetRadius sets the collision radius of every particle.
+func (c *Composite) SetRadius(radius float64) {
+	for _, p := range c.Particles {
+		p.SetRadius(radius)
+	}
+}
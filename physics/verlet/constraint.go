@@ -0,0 +1,194 @@
+package verlet
+
+import (
+	"math"
+)
+
+// Constraint moves particles towards satisfying a condition. Each step the
+// system solves every constraint a number of times, so stiffness depends on
+// both the constraint's stiffness and the iteration count.
+type Constraint interface {
+	Solve()
+	// IsBroken reports whether the constraint has torn and should be
+	// removed.
+	IsBroken() bool
+}
+
+//-----------------------------------------------------------------------------
+// Distance
+//-----------------------------------------------------------------------------
+
+// DistanceConstraint keeps two particles at a fixed distance, like a stick.
+// It tears when stretched past its tear length.
+type DistanceConstraint struct {
+	a, b       *Particle
+	length     float64 // Rest length (pixels)
+	stiffness  float64 // Fraction of the error corrected per iteration, 0 to 1
+	tearLength float64 // Length at which it breaks, zero for never
+	broken     bool
+}
+
+// NewDistanceConstraint joins two particles at their current distance.
+func NewDistanceConstraint(a, b *Particle) *DistanceConstraint {
+	return &DistanceConstraint{
+		a:         a,
+		b:         b,
+		length:    b.position.Subtract(a.position).Length(),
+		stiffness: 1,
+	}
+}
+
+func (c *DistanceConstraint) GetParticles() (*Particle, *Particle) {
+	return c.a, c.b
+}
+
+func (c *DistanceConstraint) GetLength() float64 {
+	return c.length
+}
+
+func (c *DistanceConstraint) GetStiffness() float64 {
+	return c.stiffness
+}
+
+func (c *DistanceConstraint) GetTearLength() float64 {
+	return c.tearLength
+}
+
+func (c *DistanceConstraint) SetLength(length float64) {
+	c.length = length
+}
+
+// SetStiffness sets the fraction of the error corrected per iteration, from
+// 0 (slack) to 1 (rigid).
+func (c *DistanceConstraint) SetStiffness(stiffness float64) {
+	c.stiffness = math.Max(0, math.Min(stiffness, 1))
+}
+
+// SetTearRatio makes the constraint tear when stretched to ratio times its
+// rest length. Zero disables tearing.
+func (c *DistanceConstraint) SetTearRatio(ratio float64) {
+	c.tearLength = c.length * ratio
+}
+
+// Break tears the constraint, as if cut.
+func (c *DistanceConstraint) Break() {
+	c.broken = true
+}
+
+func (c *DistanceConstraint) IsBroken() bool {
+	return c.broken
+}
+
+func (c *DistanceConstraint) Solve() {
+	if c.broken {
+		return
+	}
+	delta := c.b.position.Subtract(c.a.position)
+	distance := delta.Length()
+	if c.tearLength > 0 && distance > c.tearLength {
+		c.broken = true
+		return
+	}
+	inverseMassA, inverseMassB := c.a.GetInverseMass(), c.b.GetInverseMass()
+	total := inverseMassA + inverseMassB
+	if distance < 1e-9 || total == 0 {
+		return
+	}
+
+	// Move each end along the stick in proportion to its inverse mass.
+	correction := delta.Multiply(c.stiffness * (distance - c.length) / (distance * total))
+	c.a.position = c.a.position.Add(correction.Multiply(inverseMassA))
+	c.b.position = c.b.position.Subtract(correction.Multiply(inverseMassB))
+}
+
+//-----------------------------------------------------------------------------
+// Angle
+//-----------------------------------------------------------------------------
+
+// AngleConstraint keeps the angle at a middle particle between two others,
+// giving ropes and blobs resistance to bending. The ends are rotated about
+// the middle.
+type AngleConstraint struct {
+	a, center, b *Particle
+	angle        float64 // Rest angle from a to b about center (radians)
+	stiffness    float64
+}
+
+// NewAngleConstraint holds the current angle a, center, b.
+func NewAngleConstraint(a, center, b *Particle) *AngleConstraint {
+	c := &AngleConstraint{a: a, center: center, b: b, stiffness: 0.5}
+	c.angle = c.currentAngle()
+	return c
+}
+
+func (c *AngleConstraint) GetParticles() (*Particle, *Particle, *Particle) {
+	return c.a, c.center, c.b
+}
+
+// GetAngle returns the rest angle in radians.
+func (c *AngleConstraint) GetAngle() float64 {
+	return c.angle
+}
+
+func (c *AngleConstraint) GetStiffness() float64 {
+	return c.stiffness
+}
+
+// SetAngle sets the rest angle in radians.
+func (c *AngleConstraint) SetAngle(angle float64) {
+	c.angle = angle
+}
+
+// SetStiffness sets the fraction of the error corrected per iteration, from
+// 0 to 1.
+func (c *AngleConstraint) SetStiffness(stiffness float64) {
+	c.stiffness = math.Max(0, math.Min(stiffness, 1))
+}
+
+// IsBroken is always false: angle constraints do not tear.
+func (c *AngleConstraint) IsBroken() bool {
+	return false
+}
+
+func (c *AngleConstraint) Solve() {
+	inverseMassA, inverseMassB := c.a.GetInverseMass(), c.b.GetInverseMass()
+	total := inverseMassA + inverseMassB
+	if total == 0 {
+		return
+	}
+
+	difference := c.angle - c.currentAngle()
+	difference = math.Atan2(math.Sin(difference), math.Cos(difference))
+	difference *= c.stiffness
+
+	// Opening the angle turns a back and b forward.
+	center := c.center.position
+	moveA := center.Add(c.a.position.Subtract(center).Rotate(-difference * inverseMassA / total)).Subtract(c.a.position)
+	moveB := center.Add(c.b.position.Subtract(center).Rotate(difference * inverseMassB / total)).Subtract(c.b.position)
+
+	// Share out the net movement so the three particles' centre of mass stays
+	// put; otherwise the constraint would push the body along.
+	particles := [3]*Particle{c.a, c.center, c.b}
+	moves := [3]Vector2D{moveA, {}, moveB}
+	free := 0
+	var momentum Vector2D
+	for i, p := range particles {
+		if p.GetInverseMass() > 0 {
+			free++
+			momentum = momentum.Add(moves[i].Multiply(p.mass))
+		}
+	}
+	shift := momentum.Divide(float64(free))
+	for i, p := range particles {
+		if inverseMass := p.GetInverseMass(); inverseMass > 0 {
+			p.position = p.position.Add(moves[i]).Subtract(shift.Multiply(inverseMass))
+		}
+	}
+}
+
+// currentAngle returns the signed angle from a to b about center.
+func (c *AngleConstraint) currentAngle() float64 {
+	u := c.a.position.Subtract(c.center.position)
+	v := c.b.position.Subtract(c.center.position)
+	return math.Atan2(u.Cross(v), u.Dot(v))
+}
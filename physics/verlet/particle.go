@@ -0,0 +1,149 @@
+// Package verlet simulates soft bodies as particles joined by constraints:
+// ropes, cloth and jelly. Particles are integrated with position Verlet, so
+// velocity is implied by the distance moved last step and constraints can be
+// solved by moving particles directly. Particles collide with the shapes of a
+// physics.World but do not push its bodies.
+package verlet
+
+import (
+	"2d_game_engine/physics/geometry"
+)
+
+type Vector2D = geometry.Vector2D
+
+// Particle is a point mass.
+type Particle struct {
+	position     Vector2D
+	previous     Vector2D // Position at the start of the last step
+	acceleration Vector2D // Accumulated for the next step
+	mass         float64
+	inverseMass  float64
+	radius       float64 // Collision radius (pixels)
+	pinned       bool
+	pin          Vector2D
+}
+
+// NewParticle creates a resting particle with unit mass.
+func NewParticle(position Vector2D) *Particle {
+	return &Particle{
+		position:    position,
+		previous:    position,
+		mass:        1,
+		inverseMass: 1,
+		radius:      2,
+	}
+}
+
+// Getters
+
+func (p *Particle) GetPosition() Vector2D {
+	return p.position
+}
+
+// GetPreviousPosition returns where the particle was a step ago.
+func (p *Particle) GetPreviousPosition() Vector2D {
+	return p.previous
+}
+
+// GetVelocity returns the velocity implied by the last step of deltaTime
+// seconds.
+func (p *Particle) GetVelocity(deltaTime float64) Vector2D {
+	return p.position.Subtract(p.previous).Divide(deltaTime)
+}
+
+func (p *Particle) GetMass() float64 {
+	return p.mass
+}
+
+// GetInverseMass returns zero for pinned particles, which constraints cannot
+// move.
+func (p *Particle) GetInverseMass() float64 {
+	if p.pinned {
+		return 0
+	}
+	return p.inverseMass
+}
+
+func (p *Particle) GetRadius() float64 {
+	return p.radius
+}
+
+func (p *Particle) GetIsPinned() bool {
+	return p.pinned
+}
+
+// GetPin returns the point a pinned particle is held at.
+func (p *Particle) GetPin() Vector2D {
+	return p.pin
+}
+
+// Setters
+
+// SetPosition teleports the particle, keeping its velocity.
+func (p *Particle) SetPosition(position Vector2D) {
+	p.previous = p.previous.Add(position.Subtract(p.position))
+	p.position = position
+}
+
+// SetVelocity sets the velocity the particle carries into the next step of
+// deltaTime seconds.
+func (p *Particle) SetVelocity(velocity Vector2D, deltaTime float64) {
+	p.previous = p.position.Subtract(velocity.Multiply(deltaTime))
+}
+
+// SetMass sets the mass. Zero or less makes the particle immovable by
+// constraints, like a pin that can still be moved by hand.
+func (p *Particle) SetMass(mass float64) {
+	p.mass = mass
+	if mass > 0 {
+		p.inverseMass = 1 / mass
+	} else {
+		p.inverseMass = 0
+	}
+}
+
+func (p *Particle) SetRadius(radius float64) {
+	p.radius = radius
+}
+
+// Pin holds the particle where it is.
+func (p *Particle) Pin() {
+	p.PinTo(p.position)
+}
+
+// PinTo holds the particle at a point. Move the point every step to drag
+// the particle, for example to attach cloth to a moving body.
+func (p *Particle) PinTo(point Vector2D) {
+	p.pinned = true
+	p.pin = point
+	p.position = point
+	p.previous = point
+}
+
+// Unpin releases a pinned particle at rest.
+func (p *Particle) Unpin() {
+	p.pinned = false
+}
+
+// Dynamics
+
+// ApplyForce accumulates a force for the next step.
+func (p *Particle) ApplyForce(force Vector2D) {
+	p.acceleration = p.acceleration.Add(force.Multiply(p.inverseMass))
+}
+
+// integrate moves the particle by its implied velocity, damped, plus its
+// acceleration and gravity over deltaTime.
+func (p *Particle) integrate(deltaTime float64, gravity Vector2D, damping float64) {
+	if p.pinned {
+		p.previous = p.position
+		p.position = p.pin
+		p.acceleration = Vector2D{}
+		return
+	}
+	velocity := p.position.Subtract(p.previous).Multiply(damping)
+	p.previous = p.position
+	acceleration := p.acceleration.Add(gravity)
+	p.position = p.position.Add(velocity).Add(acceleration.Multiply(deltaTime * deltaTime))
+	p.acceleration = Vector2D{}
+}
@@ -0,0 +1,262 @@
+package verlet
+
+import (
+	"math"
+
+	"2d_game_engine/physics"
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/geometry"
+)
+
+// System owns particles and the constraints between them and steps them with
+// the engine's fixed timestep, alongside the rigid-body world.
+type System struct {
+	particles   []*Particle
+	constraints []Constraint
+	composites  []*Composite
+
+	gravity    Vector2D
+	damping    float64 // Fraction of velocity kept per 60 Hz tick
+	iterations int
+
+	// Rigid bodies the particles collide with, and how.
+	world           *physics.World
+	collisionFilter collision.Filter
+	friction        float64 // Fraction of sliding removed on contact, 0 to 1
+}
+
+// NewSystem creates an empty system whose particles collide with the bodies
+// of world, which may be nil.
+func NewSystem(world *physics.World) *System {
+	return &System{
+		particles:       make([]*Particle, 0),
+		constraints:     make([]Constraint, 0),
+		composites:      make([]*Composite, 0),
+		gravity:         physics.DefaultGravity,
+		damping:         0.99,
+		iterations:      8,
+		world:           world,
+		collisionFilter: collision.DefaultFilter,
+		friction:        0.3,
+	}
+}
+
+// Getters
+
+func (s *System) GetParticles() []*Particle {
+	return s.particles
+}
+
+func (s *System) GetConstraints() []Constraint {
+	return s.constraints
+}
+
+func (s *System) GetComposites() []*Composite {
+	return s.composites
+}
+
+func (s *System) GetGravity() Vector2D {
+	return s.gravity
+}
+
+func (s *System) GetDamping() float64 {
+	return s.damping
+}
+
+func (s *System) GetIterations() int {
+	return s.iterations
+}
+
+func (s *System) GetWorld() *physics.World {
+	return s.world
+}
+
+func (s *System) GetCollisionFilter() collision.Filter {
+	return s.collisionFilter
+}
+
+func (s *System) GetFriction() float64 {
+	return s.friction
+}
+
+// Setters
+
+func (s *System) SetGravity(gravity Vector2D) {
+	s.gravity = gravity
+}
+
+// SetDamping sets the fraction of velocity particles keep per 60 Hz tick.
+func (s *System) SetDamping(damping float64) {
+	s.damping = damping
+}
+
+// SetIterations sets how many times constraints are solved each step. More
+// iterations make ropes and cloth stiffer.
+func (s *System) SetIterations(iterations int) {
+	s.iterations = iterations
+}
+
+// SetWorld sets the rigid-body world particles collide with. Nil disables
+// collisions.
+func (s *System) SetWorld(world *physics.World) {
+	s.world = world
+}
+
+// SetCollisionFilter sets which bodies particles collide with.
+func (s *System) SetCollisionFilter(filter collision.Filter) {
+	s.collisionFilter = filter
+}
+
+// SetFriction sets the fraction of sliding removed when a particle touches a
+// body, from 0 (ice) to 1 (sticky).
+func (s *System) SetFriction(friction float64) {
+	s.friction = friction
+}
+
+//-----------------------------------------------------------------------------
+// Contents
+//-----------------------------------------------------------------------------
+
+func (s *System) AddParticle(p *Particle) {
+	s.particles = append(s.particles, p)
+}
+
+func (s *System) AddConstraint(c Constraint) {
+	s.constraints = append(s.constraints, c)
+}
+
+// AddComposite adds a composite's particles and constraints and keeps the
+// composite so it can be drawn.
+func (s *System) AddComposite(c *Composite) {
+	s.particles = append(s.particles, c.Particles...)
+	s.constraints = append(s.constraints, c.Constraints...)
+	s.composites = append(s.composites, c)
+}
+
+// RemoveParticle removes a particle and every constraint using it, returning
+// false if it was not found. A composite keeps the particle in its lists so
+// cloth grids stay intact, but loses the constraints.
+func (s *System) RemoveParticle(p *Particle) bool {
+	for i, other := range s.particles {
+		if other != p {
+			continue
+		}
+		s.particles = append(s.particles[:i], s.particles[i+1:]...)
+		s.filterConstraints(func(c Constraint) bool {
+			return !usesParticle(c, p)
+		})
+		return true
+	}
+	return false
+}
+
+// RemoveConstraint removes a constraint, returning false if it was not found.
+func (s *System) RemoveConstraint(c Constraint) bool {
+	for _, other := range s.constraints {
+		if other == c {
+			s.filterConstraints(func(other Constraint) bool {
+				return other != c
+			})
+			return true
+		}
+	}
+	return false
+}
+
+func usesParticle(c Constraint, p *Particle) bool {
+	switch c := c.(type) {
+	case *DistanceConstraint:
+		return c.a == p || c.b == p
+	case *AngleConstraint:
+		return c.a == p || c.center == p || c.b == p
+	}
+	return false
+}
+
+//-----------------------------------------------------------------------------
+// Step
+//-----------------------------------------------------------------------------
+
+// Step advances the particles by deltaTime seconds, solves the constraints,
+// pushes particles out of the world's bodies and drops torn constraints.
+func (s *System) Step(deltaTime float64) {
+	damping := 1.0
+	if s.damping < 1 {
+		damping = math.Pow(s.damping, deltaTime*60)
+	}
+	for _, p := range s.particles {
+		p.integrate(deltaTime, s.gravity, damping)
+	}
+
+	for i := 0; i < s.iterations; i++ {
+		for _, c := range s.constraints {
+			c.Solve()
+		}
+	}
+	s.collide()
+
+	s.removeBroken()
+}
+
+// collide pushes particles out of the world's bodies, removing some of their
+// sliding along the surface as friction.
+func (s *System) collide() {
+	if s.world == nil {
+		return
+	}
+	for _, p := range s.particles {
+		if p.pinned {
+			continue
+		}
+		circle := &geometry.Circle{Center: p.position, Radius: p.radius}
+		for _, b := range s.world.QueryShape(circle, s.collisionFilter) {
+			if b.GetIsSensor() {
+				continue
+			}
+			manifold, ok := collision.DetectCollision(circle, b.GetWorldShape())
+			if !ok || manifold.Depth <= 0 {
+				continue
+			}
+
+			// The manifold normal points from the particle into the body.
+			normal := manifold.Normal.Negate()
+			p.position = p.position.Add(normal.Multiply(manifold.Depth))
+			circle.Center = p.position
+
+			velocity := p.position.Subtract(p.previous)
+			sliding := velocity.Subtract(normal.Multiply(velocity.Dot(normal)))
+			p.previous = p.previous.Add(sliding.Multiply(s.friction))
+		}
+	}
+}
+
+// removeBroken drops torn constraints.
+func (s *System) removeBroken() {
+	for _, c := range s.constraints {
+		if c.IsBroken() {
+			s.filterConstraints(func(c Constraint) bool {
+				return !c.IsBroken()
+			})
+			return
+		}
+	}
+}
+
+// filterConstraints keeps the constraints, in the system and its composites,
+// for which keep returns true.
+func (s *System) filterConstraints(keep func(c Constraint) bool) {
+	s.constraints = filter(s.constraints, keep)
+	for _, composite := range s.composites {
+		composite.Constraints = filter(composite.Constraints, keep)
+	}
+}
+
+func filter(constraints []Constraint, keep func(c Constraint) bool) []Constraint {
+	kept := constraints[:0]
+	for _, c := range constraints {
+		if keep(c) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
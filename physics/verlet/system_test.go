@@ -0,0 +1,206 @@
+package verlet
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics"
+	"2d_game_engine/physics/body"
+	"2d_game_engine/physics/geometry"
+)
+
+const deltaTime = 1.0 / 60
+
+func stepFor(s *System, seconds float64) {
+	for i := 0; i < int(seconds*60); i++ {
+		s.Step(deltaTime)
+	}
+}
+
+func TestParticleFreeFall(t *testing.T) {
+	s := NewSystem(nil)
+	s.SetDamping(1)
+	p := NewParticle(Vector2D{})
+	s.AddParticle(p)
+
+	stepFor(s, 1)
+	// Position Verlet adds g·dt² per step: after n steps, g·dt²·n(n+1)/2.
+	if want := physics.DefaultGravity.Y * deltaTime * deltaTime * 60 * 61 / 2; math.Abs(p.GetPosition().Y-want) > 1e-6 {
+		t.Errorf("particle fell to y=%v, want %v", p.GetPosition().Y, want)
+	}
+	if speed := p.GetVelocity(deltaTime).Y; math.Abs(speed-physics.DefaultGravity.Y) > 1e-6 {
+		t.Errorf("particle falls at %v after a second, want %v", speed, physics.DefaultGravity.Y)
+	}
+}
+
+func TestPinnedParticles(t *testing.T) {
+	s := NewSystem(nil)
+	p := NewParticle(Vector2D{X: 10, Y: 10})
+	p.Pin()
+	s.AddParticle(p)
+	stepFor(s, 1)
+	if p.GetPosition() != (Vector2D{X: 10, Y: 10}) || p.GetInverseMass() != 0 {
+		t.Errorf("pinned particle moved to %v", p.GetPosition())
+	}
+
+	p.PinTo(Vector2D{X: 50, Y: 0})
+	s.Step(deltaTime)
+	if p.GetPosition() != (Vector2D{X: 50, Y: 0}) {
+		t.Errorf("particle pinned to {50 0} is at %v", p.GetPosition())
+	}
+	p.Unpin()
+	s.Step(deltaTime)
+	if p.GetPosition().Y <= 0 || p.GetPosition().X != 50 {
+		t.Errorf("unpinned particle at %v, want it falling straight down from rest", p.GetPosition())
+	}
+}
+
+func TestRopeHangs(t *testing.T) {
+	// Constraints are solved a few times a step, so hanging links stretch a
+	// little; more iterations stretch them less.
+	for _, test := range []struct {
+		iterations int
+		stretch    float64
+	}{{8, 0.5}, {64, 0.05}} {
+		s := NewSystem(nil)
+		s.SetIterations(test.iterations)
+		rope := NewRope(Vector2D{}, Vector2D{X: 100, Y: 0}, 10)
+		s.AddComposite(rope)
+
+		stepFor(s, 10)
+		for i, c := range rope.Constraints {
+			a, b := c.(*DistanceConstraint).GetParticles()
+			if length := b.GetPosition().Distance(a.GetPosition()); math.Abs(length-10) > test.stretch {
+				t.Errorf("%d iterations: link %d stretched to %v, want 10", test.iterations, i, length)
+			}
+		}
+		if rope.Particles[0].GetPosition() != (Vector2D{}) {
+			t.Errorf("rope's pinned end moved to %v", rope.Particles[0].GetPosition())
+		}
+		if end := rope.Particles[10].GetPosition(); end.Y < 95 || math.Abs(end.X) > 5 {
+			t.Errorf("%d iterations: rope's free end is at %v, want it hanging straight down", test.iterations, end)
+		}
+	}
+}
+
+func TestClothTears(t *testing.T) {
+	s := NewSystem(nil)
+	cloth := NewCloth(Vector2D{}, 100, 50, 5, 3)
+	cloth.SetTearRatio(1.5)
+	s.AddComposite(cloth)
+	total := len(cloth.Constraints)
+	if total != 4*3+5*2 {
+		t.Fatalf("cloth has %d constraints, want 22", total)
+	}
+
+	// Yank the bottom corner far away.
+	cloth.At(4, 2).PinTo(Vector2D{X: 1000, Y: 1000})
+	s.Step(deltaTime)
+	if len(cloth.Constraints) == total || len(s.GetConstraints()) != len(cloth.Constraints) {
+		t.Errorf("cloth kept %d of %d constraints, system %d; want some torn from both", len(cloth.Constraints), total, len(s.GetConstraints()))
+	}
+	for _, c := range s.GetConstraints() {
+		if c.IsBroken() {
+			t.Errorf("a torn constraint was left in the system")
+		}
+	}
+}
+
+func TestRemoveParticle(t *testing.T) {
+	s := NewSystem(nil)
+	rope := NewRope(Vector2D{}, Vector2D{X: 30, Y: 0}, 3)
+	s.AddComposite(rope)
+
+	if !s.RemoveParticle(rope.Particles[1]) || s.RemoveParticle(rope.Particles[1]) {
+		t.Fatalf("RemoveParticle() did not remove the particle exactly once")
+	}
+	if len(s.GetParticles()) != 3 || len(s.GetConstraints()) != 1 || len(rope.Constraints) != 1 {
+		t.Errorf("got %d particles and %d constraints, want 3 and 1", len(s.GetParticles()), len(s.GetConstraints()))
+	}
+	if !s.RemoveConstraint(rope.Constraints[0]) || len(s.GetConstraints()) != 0 {
+		t.Errorf("RemoveConstraint() left %v", s.GetConstraints())
+	}
+}
+
+func TestAngleConstraintBends(t *testing.T) {
+	s := NewSystem(nil)
+	s.SetGravity(Vector2D{})
+	a := NewParticle(Vector2D{X: -10, Y: 0})
+	center := NewParticle(Vector2D{})
+	b := NewParticle(Vector2D{X: 10, Y: 0})
+	for _, p := range []*Particle{a, center, b} {
+		s.AddParticle(p)
+	}
+	s.AddConstraint(NewDistanceConstraint(a, center))
+	s.AddConstraint(NewDistanceConstraint(center, b))
+	bend := NewAngleConstraint(a, center, b)
+	if math.Abs(math.Abs(bend.GetAngle())-math.Pi) > 1e-9 {
+		t.Fatalf("straight line has a rest angle of %v, want π", bend.GetAngle())
+	}
+	bend.SetAngle(math.Pi / 2)
+	s.AddConstraint(bend)
+
+	stepFor(s, 2)
+	u := a.GetPosition().Subtract(center.GetPosition())
+	v := b.GetPosition().Subtract(center.GetPosition())
+	if angle := u.AngleTo(v); math.Abs(angle-math.Pi/2) > 0.05 {
+		t.Errorf("angle settled at %v, want π/2", angle)
+	}
+}
+
+func TestParticlesCollideWithWorld(t *testing.T) {
+	world := physics.NewWorld()
+	floor := body.FromRectangle(Vector2D{X: 0, Y: 120}, 400, 40)
+	floor.SetIsStatic(true)
+	world.AddBody(floor)
+	sensor := body.FromRectangle(Vector2D{X: 0, Y: 50}, 400, 20)
+	sensor.SetIsStatic(true)
+	sensor.SetIsSensor(true)
+	world.AddBody(sensor)
+
+	s := NewSystem(world)
+	p := NewParticle(Vector2D{})
+	p.SetRadius(5)
+	s.AddParticle(p)
+
+	stepFor(s, 2)
+	// It passes through the sensor and rests on the floor's top at y=100.
+	if y := p.GetPosition().Y; math.Abs(y-95) > 0.5 {
+		t.Errorf("particle rests at y=%v, want 95", y)
+	}
+
+	s.SetWorld(nil)
+	stepFor(s, 0.5)
+	if y := p.GetPosition().Y; y < 100 {
+		t.Errorf("particle at y=%v, want it falling through with collisions off", y)
+	}
+}
+
+func TestBlobKeepsItsShape(t *testing.T) {
+	world := physics.NewWorld()
+	floor := body.FromRectangle(Vector2D{X: 0, Y: 120}, 400, 40)
+	floor.SetIsStatic(true)
+	world.AddBody(floor)
+
+	s := NewSystem(world)
+	blob := NewBlob(Vector2D{X: 0, Y: 0}, 30, 12)
+	s.AddComposite(blob)
+	outline := func() []Vector2D {
+		points := make([]Vector2D, len(blob.Outline))
+		for i, p := range blob.Outline {
+			points[i] = p.GetPosition()
+		}
+		return points
+	}
+	restArea := geometry.Area(outline())
+
+	stepFor(s, 3)
+	if area := geometry.Area(outline()); area < restArea/2 {
+		t.Errorf("blob squashed to %v of its %v area", area, restArea)
+	}
+	for _, p := range blob.Particles {
+		if y := p.GetPosition().Y; y > 100 {
+			t.Errorf("blob particle sank into the floor at y=%v", y)
+		}
+	}
+}
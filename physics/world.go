@@ -0,0 +1,372 @@
+package physics
+
+import (
+	"2d_game_engine/physics/body"
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/geometry"
+	"2d_game_engine/physics/joint"
+)
+
+type Vector2D = geometry.Vector2D
+type Body = body.Body
+
+// DefaultGravity is roughly 9.8 m/s² at a scale of 100 pixels per metre,
+// pointing down the screen.
+var DefaultGravity = Vector2D{X: 0, Y: 980}
+
+// World owns a set of bodies and advances them in fixed timesteps.
+type World struct {
+	bodies             []*Body
+	bodyByID           map[int]*Body
+	gravity            Vector2D
+	nextID             int
+	velocityIterations int
+	positionIterations int
+
+	// Broad phase proxies, keyed by body ID, as they were last inserted.
+	broadPhase collision.BroadPhase
+	proxies    map[int]proxyState
+
+	// Sleeping configuration and the island each sleeping body belongs to.
+	sleepingEnabled bool
+	sleepThreshold  float64
+	timeToSleep     float64
+	islands         map[int]*island
+
+	// Contacts found during the last step, in a stable order, and indexed by
+	// pair for warm starting. solverContacts excludes sensors and resting pairs.
+	contacts       []*contact
+	contactMap     map[pairKey]*contact
+	solverContacts []*contact
+
+	// Joints, and the joints attached to each body.
+	joints     []joint.Joint
+	jointEdges map[*Body][]joint.Joint
+
+	subscriptions      []collisionSubscription
+	nextSubscriptionID int
+
+	// Optional per-pair filter run after the collision filters.
+	contactFilter ContactFilter
+
+	// Area effectors and the sensor regions they push bodies inside.
+	effectors      []areaEffector
+	nextEffectorID int
+}
+
+// ContactFilter decides whether two bodies whose collision filters match
+// should be tested for collision. Returning false ignores the pair.
+type ContactFilter func(bodyA, bodyB *Body) bool
+
+// NewWorld creates an empty world with default gravity.
+func NewWorld() *World {
+	return &World{
+		bodies:             make([]*Body, 0),
+		bodyByID:           make(map[int]*Body),
+		gravity:            DefaultGravity,
+		nextID:             1,
+		velocityIterations: 8,
+		positionIterations: 3,
+		broadPhase:         collision.NewAABBTree(4),
+		proxies:            make(map[int]proxyState),
+		sleepingEnabled:    true,
+		sleepThreshold:     4,
+		timeToSleep:        0.5,
+		islands:            make(map[int]*island),
+		contacts:           make([]*contact, 0),
+		contactMap:         make(map[pairKey]*contact),
+		solverContacts:     make([]*contact, 0),
+		joints:             make([]joint.Joint, 0),
+		jointEdges:         make(map[*Body][]joint.Joint),
+		subscriptions:      make([]collisionSubscription, 0),
+		effectors:          make([]areaEffector, 0),
+	}
+}
+
+// AddBody adds a body to the world. Bodies without an ID are assigned one.
+func (w *World) AddBody(b *Body) {
+	if b.GetID() == 0 {
+		b.SetID(w.nextID)
+		w.nextID++
+	}
+	w.bodies = append(w.bodies, b)
+	w.bodyByID[b.GetID()] = b
+}
+
+// RemoveBody removes a body from the world together with its joints,
+// returning false if it was not found.
+func (w *World) RemoveBody(b *Body) bool {
+	for i, other := range w.bodies {
+		if other == b {
+			for _, j := range append([]joint.Joint(nil), w.jointEdges[b]...) {
+				w.RemoveJoint(j)
+			}
+			w.bodies = append(w.bodies[:i], w.bodies[i+1:]...)
+			delete(w.bodyByID, b.GetID())
+			w.wakeIsland(b)
+			if _, ok := w.proxies[b.GetID()]; ok {
+				w.broadPhase.Remove(b.GetID())
+				delete(w.proxies, b.GetID())
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (w *World) GetBodies() []*Body {
+	return w.bodies
+}
+
+func (w *World) GetGravity() Vector2D {
+	return w.gravity
+}
+
+func (w *World) SetGravity(gravity Vector2D) {
+	w.gravity = gravity
+}
+
+func (w *World) GetBroadPhase() collision.BroadPhase {
+	return w.broadPhase
+}
+
+// SetBroadPhase replaces the broad phase, moving every body into the new one.
+func (w *World) SetBroadPhase(broadPhase collision.BroadPhase) {
+	w.broadPhase = broadPhase
+	w.proxies = make(map[int]proxyState)
+	w.updateBroadPhase()
+}
+
+func (w *World) GetSleepingEnabled() bool {
+	return w.sleepingEnabled
+}
+
+// SetSleepingEnabled turns sleeping on or off. Disabling it wakes every body.
+func (w *World) SetSleepingEnabled(enabled bool) {
+	w.sleepingEnabled = enabled
+	if !enabled {
+		for _, b := range w.bodies {
+			if b.GetIsSleeping() {
+				w.wakeIsland(b)
+			}
+		}
+	}
+}
+
+func (w *World) GetSleepThreshold() float64 {
+	return w.sleepThreshold
+}
+
+// SetSleepThreshold sets the motion energy (squared pixels per second) below
+// which a body counts as resting.
+func (w *World) SetSleepThreshold(threshold float64) {
+	w.sleepThreshold = threshold
+}
+
+func (w *World) GetTimeToSleep() float64 {
+	return w.timeToSleep
+}
+
+// SetTimeToSleep sets how many seconds an island must rest before sleeping.
+func (w *World) SetTimeToSleep(seconds float64) {
+	w.timeToSleep = seconds
+}
+
+// SetContactFilter installs a custom per-pair filter. Pass nil to remove it.
+func (w *World) SetContactFilter(filter ContactFilter) {
+	w.contactFilter = filter
+}
+
+func (w *World) GetVelocityIterations() int {
+	return w.velocityIterations
+}
+
+func (w *World) SetVelocityIterations(iterations int) {
+	w.velocityIterations = iterations
+}
+
+func (w *World) GetPositionIterations() int {
+	return w.positionIterations
+}
+
+func (w *World) SetPositionIterations(iterations int) {
+	w.positionIterations = iterations
+}
+
+// Step advances the simulation by deltaTime seconds. It is meant to be called
+// with the engine's fixed physics timestep.
+func (w *World) Step(deltaTime float64) {
+	// Apply effector forces, then forces and gravity, to velocities.
+	w.applyEffectors()
+	for _, b := range w.bodies {
+		b.SetConstraintImpulse(Vector2D{X: 0, Y: 0}, 0)
+		b.IntegrateVelocity(deltaTime, w.gravity)
+	}
+
+	// Find touching pairs, waking any island an awake body runs into or is
+	// jointed to, and resolve their velocities. Joints are solved first so
+	// contacts get the final say on penetration.
+	previousContacts, previousContactMap := w.contacts, w.contactMap
+	w.detectContacts()
+	if w.wakeTouchedIslands() {
+		w.detectContacts()
+	}
+	joints := w.activeJoints()
+	for _, j := range joints {
+		j.InitVelocityConstraints(deltaTime)
+	}
+	for _, c := range w.solverContacts {
+		c.prepare()
+		c.warmStart()
+	}
+	for i := 0; i < w.velocityIterations; i++ {
+		for _, j := range joints {
+			j.SolveVelocityConstraints(deltaTime)
+		}
+		for _, c := range w.solverContacts {
+			c.solveVelocity()
+		}
+	}
+
+	// Move bodies with the solved velocities, sweeping bullets so they stop
+	// at the first body in their path.
+	bullets := w.beginContinuous()
+	for _, b := range w.bodies {
+		b.IntegratePosition(deltaTime)
+	}
+	w.solveContinuous(bullets, deltaTime)
+
+	// Remove any remaining penetration.
+	for _, b := range w.bodies {
+		b.SetPositionImpulse(Vector2D{X: 0, Y: 0})
+	}
+	for i := 0; i < w.positionIterations; i++ {
+		for _, j := range joints {
+			j.SolvePositionConstraints()
+		}
+		for _, c := range w.solverContacts {
+			c.solvePosition()
+		}
+	}
+
+	if w.sleepingEnabled {
+		w.updateSleeping(deltaTime)
+	}
+
+	// Forces only last for a single step.
+	for _, b := range w.bodies {
+		b.ClearForces()
+	}
+	w.quantizeState()
+
+	w.dispatchCollisionEvents(previousContacts, previousContactMap)
+}
+
+// proxyState is what a body's broad phase proxy was inserted with. A change
+// to either means reinserting it.
+type proxyState struct {
+	static bool
+	filter collision.Filter
+}
+
+// updateBroadPhase inserts new bodies into the broad phase and refreshes the
+// bounds of existing ones.
+func (w *World) updateBroadPhase() {
+	for _, b := range w.bodies {
+		if b.GetShape() == nil {
+			continue
+		}
+
+		id := b.GetID()
+		bounds := bodyBounds(b)
+		state := proxyState{static: b.GetIsStatic(), filter: b.GetCollisionFilter()}
+		previous, ok := w.proxies[id]
+		switch {
+		case !ok:
+			w.broadPhase.Insert(id, bounds, state.static, state.filter)
+		case previous != state:
+			w.broadPhase.Remove(id)
+			w.broadPhase.Insert(id, bounds, state.static, state.filter)
+		default:
+			w.broadPhase.Move(id, bounds)
+		}
+		w.proxies[id] = state
+	}
+}
+
+// detectContacts runs the narrow phase on the candidate pairs reported by the
+// broad phase and rebuilds the contact lists. Contacts between bodies that are
+// both at rest are carried over from the previous step.
+func (w *World) detectContacts() {
+	contacts := make([]*contact, 0, len(w.contacts))
+	contactMap := make(map[pairKey]*contact, len(w.contactMap))
+	solverContacts := make([]*contact, 0, len(w.solverContacts))
+
+	w.updateBroadPhase()
+	for _, pair := range w.broadPhase.Pairs() {
+		bodyA, bodyB := w.bodyByID[pair.A], w.bodyByID[pair.B]
+		if !w.shouldCollide(bodyA, bodyB) {
+			continue
+		}
+
+		key := newPairKey(bodyA, bodyB)
+		if !isActive(bodyA) && !isActive(bodyB) {
+			if previous, ok := w.contactMap[key]; ok {
+				contacts = append(contacts, previous)
+				contactMap[key] = previous
+			}
+			continue
+		}
+
+		sensor := bodyA.GetIsSensor() || bodyB.GetIsSensor()
+		if !sensor && !canRespond(bodyA, bodyB) {
+			continue
+		}
+
+		manifold, ok := narrowPhase(bodyA, bodyB)
+		if !ok {
+			continue
+		}
+
+		c := newContact(bodyA, bodyB, manifold, w.contactMap[key])
+		c.sensor = sensor
+		contacts = append(contacts, c)
+		contactMap[key] = c
+		if !sensor {
+			solverContacts = append(solverContacts, c)
+		}
+	}
+
+	w.contacts = contacts
+	w.contactMap = contactMap
+	w.solverContacts = solverContacts
+}
+
+// shouldCollide applies the bodies' collision filters, their joints and the
+// custom contact filter to a pair of bodies. Broad phase pairs have passed the
+// collision filters already, but other callers, such as continuous collision,
+// rely on this check.
+func (w *World) shouldCollide(bodyA, bodyB *Body) bool {
+	if bodyA.GetShape() == nil || bodyB.GetShape() == nil {
+		return false
+	}
+	if !collision.ShouldCollide(bodyA.GetCollisionFilter(), bodyB.GetCollisionFilter()) {
+		return false
+	}
+	if !w.jointsAllowCollision(bodyA, bodyB) {
+		return false
+	}
+	return w.contactFilter == nil || w.contactFilter(bodyA, bodyB)
+}
+
+// isActive reports whether a body is being simulated this step.
+func isActive(b *Body) bool {
+	return !b.GetIsStatic() && !b.GetIsSleeping()
+}
+
+// canRespond reports whether at least one of the bodies can be moved by a contact.
+func canRespond(bodyA, bodyB *Body) bool {
+	inverseMassA, _ := inverseMasses(bodyA)
+	inverseMassB, _ := inverseMasses(bodyB)
+	return inverseMassA != 0 || inverseMassB != 0
+}
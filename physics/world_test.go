@@ -0,0 +1,212 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"2d_game_engine/physics/body"
+	"2d_game_engine/physics/collision"
+	"2d_game_engine/physics/geometry"
+)
+
+// touching records which pairs of body IDs started touching.
+func touching(world *World) map[[2]int]bool {
+	started := make(map[[2]int]bool)
+	world.Subscribe(CollisionStart, func(event CollisionEvent) {
+		a, b := event.BodyA.GetID(), event.BodyB.GetID()
+		if a > b {
+			a, b = b, a
+		}
+		started[[2]int{a, b}] = true
+	})
+	return started
+}
+
+// newPlayersScene returns a floor and two players overlapping just above it.
+func newPlayersScene() (*World, *Body, *Body, *Body) {
+	world := NewWorld()
+	floor := body.FromRectangle(Vector2D{X: 0, Y: 20}, 400, 40)
+	floor.SetIsStatic(true)
+	world.AddBody(floor)
+
+	playerA := body.FromRectangle(Vector2D{X: 0, Y: -15}, 20, 20)
+	playerB := body.FromRectangle(Vector2D{X: 10, Y: -15}, 20, 20)
+	world.AddBody(playerA)
+	world.AddBody(playerB)
+	return world, floor, playerA, playerB
+}
+
+func pairOf(a, b *Body) [2]int {
+	if a.GetID() > b.GetID() {
+		a, b = b, a
+	}
+	return [2]int{a.GetID(), b.GetID()}
+}
+
+func TestCollisionFilterGroup(t *testing.T) {
+	world, floor, playerA, playerB := newPlayersScene()
+	players := collision.Filter{Category: 0x0002, Mask: 0xFFFFFFFF, Group: -1}
+	playerA.SetCollisionFilter(players)
+	playerB.SetCollisionFilter(players)
+	started := touching(world)
+
+	for i := 0; i < 30; i++ {
+		world.Step(1.0 / 60)
+	}
+	if started[pairOf(playerA, playerB)] {
+		t.Errorf("players in the same negative group collided")
+	}
+	if !started[pairOf(playerA, floor)] || !started[pairOf(playerB, floor)] {
+		t.Errorf("players did not land on the floor: %v", started)
+	}
+	if len(world.GetBroadPhase().Pairs()) != 2 {
+		t.Errorf("broad phase reported %v, want only the player-floor pairs", world.GetBroadPhase().Pairs())
+	}
+
+	// Changing a filter takes effect on the next step.
+	playerB.SetCollisionFilter(collision.DefaultFilter)
+	world.Step(1.0 / 60)
+	if !started[pairOf(playerA, playerB)] {
+		t.Errorf("players did not collide after leaving the group")
+	}
+}
+
+func TestCollisionFilterMask(t *testing.T) {
+	world, floor, playerA, playerB := newPlayersScene()
+	// Players are category 2 and only collide with category 1, the default.
+	players := collision.Filter{Category: 0x0002, Mask: 0x0001}
+	playerA.SetCollisionFilter(players)
+	playerB.SetCollisionFilter(players)
+	started := touching(world)
+
+	for i := 0; i < 30; i++ {
+		world.Step(1.0 / 60)
+	}
+	if started[pairOf(playerA, playerB)] {
+		t.Errorf("players whose masks exclude each other collided")
+	}
+	if !started[pairOf(playerA, floor)] {
+		t.Errorf("player did not land on the floor")
+	}
+}
+
+func TestContactFilter(t *testing.T) {
+	world, floor, playerA, playerB := newPlayersScene()
+	world.SetContactFilter(func(bodyA, bodyB *Body) bool {
+		return bodyA == floor || bodyB == floor
+	})
+	started := touching(world)
+
+	for i := 0; i < 30; i++ {
+		world.Step(1.0 / 60)
+	}
+	if started[pairOf(playerA, playerB)] {
+		t.Errorf("contact filter did not keep the players apart")
+	}
+	if !started[pairOf(playerB, floor)] {
+		t.Errorf("contact filter kept a player off the floor")
+	}
+}
+
+// integrationTolerance is how far integrated speeds and positions may be from
+// their exact values. Built with -tags fixedpoint, a step of 1/60 s is rounded
+// down to the Q32.32 grid, a few parts in a billion short, and a second of
+// falling builds that up to a few millionths of a pixel.
+func integrationTolerance() float64 {
+	if FixedPoint {
+		return 1e-5
+	}
+	return 1e-6
+}
+
+func TestWorldFreeFall(t *testing.T) {
+	world := NewWorld()
+	ball := body.FromCircle(Vector2D{X: 0, Y: 0}, 5)
+	ball.SetFrictionAir(0)
+	world.AddBody(ball)
+
+	// Semi-implicit Euler: after n steps the body has fallen g·dt²·n(n+1)/2.
+	const steps, dt = 60, 1.0 / 60
+	for i := 0; i < steps; i++ {
+		world.Step(dt)
+	}
+	if got, want := ball.GetVelocity().Y, DefaultGravity.Y; math.Abs(got-want) > integrationTolerance() {
+		t.Errorf("velocity after a second = %v, want %v", got, want)
+	}
+	if got, want := ball.GetPosition().Y, DefaultGravity.Y*dt*dt*steps*(steps+1)/2; math.Abs(got-want) > integrationTolerance() {
+		t.Errorf("fell %v in a second, want %v", got, want)
+	}
+	if ball.GetPosition().X != 0 {
+		t.Errorf("falling ball drifted sideways to %v", ball.GetPosition().X)
+	}
+}
+
+func TestWorldStaticBodyDoesNotMove(t *testing.T) {
+	world := NewWorld()
+	ground := body.FromRectangle(Vector2D{X: 10, Y: 20}, 100, 10)
+	ground.SetIsStatic(true)
+	world.AddBody(ground)
+	for i := 0; i < 10; i++ {
+		world.Step(1.0 / 60)
+	}
+	if got := ground.GetPosition(); got != (Vector2D{X: 10, Y: 20}) {
+		t.Errorf("static body moved to %v", got)
+	}
+}
+
+func TestWorldBoxRestsOnFloor(t *testing.T) {
+	world := NewWorld()
+	floor := body.FromRectangle(Vector2D{X: 0, Y: 20}, 400, 40)
+	floor.SetIsStatic(true)
+	world.AddBody(floor)
+	box := body.FromRectangle(Vector2D{X: 0, Y: -50}, 20, 20)
+	world.AddBody(box)
+
+	for i := 0; i < 180; i++ {
+		world.Step(1.0 / 60)
+	}
+	// The floor's top is at y=0, so the box's centre should settle at -10,
+	// give or take the slop.
+	if y := box.GetPosition().Y; y < -10.5 || y > -9 {
+		t.Errorf("box came to rest at y=%v, want about -10", y)
+	}
+	if speed := box.GetVelocity().Length(); speed > 1 {
+		t.Errorf("box still moving at %v after three seconds", speed)
+	}
+}
+
+func TestWorldAddRemoveBody(t *testing.T) {
+	world := NewWorld()
+	a := body.FromCircle(Vector2D{}, 5)
+	b := body.FromCircle(Vector2D{X: 100}, 5)
+	world.AddBody(a)
+	world.AddBody(b)
+	if a.GetID() == 0 || b.GetID() == 0 || a.GetID() == b.GetID() {
+		t.Fatalf("bodies were given IDs %d and %d, want distinct non-zero IDs", a.GetID(), b.GetID())
+	}
+	world.Step(1.0 / 60)
+
+	if !world.RemoveBody(a) {
+		t.Fatalf("RemoveBody() did not find a body in the world")
+	}
+	if world.RemoveBody(a) {
+		t.Errorf("RemoveBody() found a body twice")
+	}
+	if len(world.GetBodies()) != 1 || world.GetBodies()[0] != b {
+		t.Errorf("GetBodies() = %v after removing a, want only b", world.GetBodies())
+	}
+	if ids := queryBroadPhase(world); len(ids) != 1 || ids[0] != b.GetID() {
+		t.Errorf("broad phase still holds %v", ids)
+	}
+}
+
+// queryBroadPhase returns every proxy ID in the world's broad phase.
+func queryBroadPhase(world *World) []int {
+	everywhere := geometry.AABB{Min: Vector2D{X: -1e9, Y: -1e9}, Max: Vector2D{X: 1e9, Y: 1e9}}
+	ids := make([]int, 0)
+	world.GetBroadPhase().Query(everywhere, func(id int) bool {
+		ids = append(ids, id)
+		return true
+	})
+	return ids
+}
@@ -0,0 +1,59 @@
+package renderer
+
+import (
+	"2d_game_engine/physics/body"
+	"2d_game_engine/physics/geometry"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// IsInView reports whether a bounding box overlaps the renderer's viewport,
+// so anything outside it can be skipped.
+func IsInView(renderer *sdl.Renderer, bounds geometry.AABB) bool {
+	viewport := renderer.GetViewport()
+	view := geometry.AABB{
+		Min: Vector2D{X: 0, Y: 0},
+		Max: Vector2D{X: float64(viewport.W), Y: float64(viewport.H)},
+	}
+	return view.Overlaps(bounds)
+}
+
+// DrawBody renders the outline of a physics body at its current position and
+// angle, using the body's colour and opacity. Bodies outside the viewport are
+// culled.
+func DrawBody(renderer *sdl.Renderer, b *body.Body) {
+	if !b.GetVisible() || !IsInView(renderer, b.Bounds()) {
+		return
+	}
+
+	r, g, blue, a := b.GetColor()
+	renderer.SetDrawColor(r, g, blue, uint8(float64(a)*b.GetOpacity()))
+
+	switch shape := b.GetWorldShape().(type) {
+	case *geometry.Circle:
+		DrawCircle(renderer, shape.Center, int32(shape.Radius))
+	case *geometry.Polygon:
+		DrawPolygon(renderer, shape)
+	case *geometry.Compound:
+		DrawCompound(renderer, shape)
+	}
+}
+
+// DrawFilledBody renders a physics body as a filled shape.
+func DrawFilledBody(renderer *sdl.Renderer, b *body.Body) {
+	if !b.GetVisible() || !IsInView(renderer, b.Bounds()) {
+		return
+	}
+
+	r, g, blue, a := b.GetColor()
+	renderer.SetDrawColor(r, g, blue, uint8(float64(a)*b.GetOpacity()))
+
+	switch shape := b.GetWorldShape().(type) {
+	case *geometry.Circle:
+		DrawFilledCircle(renderer, shape.Center, shape.Radius)
+	case *geometry.Polygon:
+		DrawFilledPolygon(renderer, shape)
+	case *geometry.Compound:
+		DrawFilledCompound(renderer, shape)
+	}
+}
@@ -11,32 +11,24 @@ import (
 type Vector2D = geometry.Vector2D
 type Polygon = geometry.Polygon
 type Triangle = geometry.Triangle
-// drawPolygon renders the polygon by drawing lines between its vertices.
-// It translates the vertex coordinates based on the polygon's position.
-func DrawPolygon(renderer *sdl.Renderer,p *Polygon) {
+type Rectangle = geometry.Rectangle
+type Segment = geometry.Segment
+type Capsule = geometry.Capsule
+type Ellipse = geometry.Ellipse
+type Compound = geometry.Compound
+// DrawPolygon renders the polygon by drawing lines between its vertices,
+// placed by the polygon's position and rotation.
+func DrawPolygon(renderer *sdl.Renderer, p *Polygon) {
 	// Check for a valid polygon with at least 3 vertices.
 	if len(p.Vertices) < 3 {
 		return
 	}
-
-	// Iterate through the vertices to draw the edges.
-	for i := 0; i < len(p.Vertices); i++ {
-		p1 := p.Vertices[i]
-		// The next vertex, wrapping around to the first for the last edge.
-		p2 := p.Vertices[(i+1)%len(p.Vertices)]
-
-		// Translate the vertex coordinates by the polygon's position.
-		x1 := int32(p1.X + p.Position.X)
-		y1 := int32(p1.Y + p.Position.Y)
-		x2 := int32(p2.X + p.Position.X)
-		y2 := int32(p2.Y + p.Position.Y)
-
-		// Draw a line between the two vertices.
-		renderer.DrawLine(x1, y1, x2, y2)
-	}
+	drawOutline(renderer, p.WorldVertices())
 }
 
 // DrawFilledPolygon draws a filled polygon using a scanline fill algorithm.
+// The outline must be simple; check untrusted input once with
+// geometry.ValidatePolygon rather than every frame.
 func DrawFilledPolygon(renderer *sdl.Renderer, p *Polygon) {
 	if len(p.Vertices) < 3 {
 		return
@@ -46,10 +38,9 @@ func DrawFilledPolygon(renderer *sdl.Renderer, p *Polygon) {
 	minY := math.Inf(1)
 	maxY := math.Inf(-1)
 	
-	// Create a slice of absolute vertices.
-	absoluteVertices := make([]Vector2D, len(p.Vertices))
-	for i, v := range p.Vertices {
-		absoluteVertices[i] = Vector2D{X: v.X + p.Position.X, Y: v.Y + p.Position.Y}
+	// Place the vertices by the polygon's position and rotation.
+	absoluteVertices := p.WorldVertices()
+	for i := range absoluteVertices {
 		if absoluteVertices[i].Y < minY {
 			minY = absoluteVertices[i].Y
 		}
@@ -132,11 +123,9 @@ func DrawTriangle(renderer *sdl.Renderer,t *Triangle) {
 	}
 
 	points := make([]sdl.Point, 4)
-	for i := 0; i < 3; i++ {
-		// Calculate the absolute position of each vertex by adding the triangle's position.
-		absoluteX := t.Vertices[i].X + t.Position.X
-		absoluteY := t.Vertices[i].Y + t.Position.Y
-		points[i] = sdl.Point{X: int32(absoluteX), Y: int32(absoluteY)}
+	for i, v := range t.WorldVertices() {
+		// Each vertex is placed by the triangle's position and rotation.
+		points[i] = sdl.Point{X: int32(v.X), Y: int32(v.Y)}
 	}
 	points[3] = points[0] // Close the triangle.
 
@@ -150,10 +139,7 @@ func DrawFilledTriangle(renderer *sdl.Renderer, t *Triangle) {
 	}
 
 	// 1. Get absolute vertices and sort them by Y-coordinate.
-	absVertices := make([]Vector2D, 3)
-	for i, v := range t.Vertices {
-		absVertices[i] = Vector2D{X: v.X + t.Position.X, Y: v.Y + t.Position.Y}
-	}
+	absVertices := t.WorldVertices()
 	
 	// Sort vertices by Y-coordinate (top to bottom).
 	for i := 0; i < 2; i++ {
@@ -209,4 +195,129 @@ func DrawFilledCircle(renderer *sdl.Renderer, center Vector2D, radius float64) {
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+
+// outlineSegments is the number of directions sampled to outline curved shapes.
+const outlineSegments = 32
+
+// supportOutline approximates the outline of a convex shape by sampling its
+// support points in evenly spaced directions.
+func supportOutline(shape geometry.Shape) []Vector2D {
+	points := make([]Vector2D, 0, outlineSegments)
+	for i := 0; i < outlineSegments; i++ {
+		angle := 2 * math.Pi * float64(i) / outlineSegments
+		point := shape.Support(Vector2D{X: math.Cos(angle), Y: math.Sin(angle)})
+		if len(points) == 0 || point != points[len(points)-1] {
+			points = append(points, point)
+		}
+	}
+	return points
+}
+
+// drawOutline draws a closed loop through world-space points.
+func drawOutline(renderer *sdl.Renderer, points []Vector2D) {
+	if len(points) < 2 {
+		return
+	}
+	sdlPoints := make([]sdl.Point, len(points)+1)
+	for i, p := range points {
+		sdlPoints[i] = sdl.Point{X: int32(p.X), Y: int32(p.Y)}
+	}
+	sdlPoints[len(points)] = sdlPoints[0]
+	renderer.DrawLines(sdlPoints)
+}
+
+// DrawRectangle draws the outline of a rectangle at its rotation.
+func DrawRectangle(renderer *sdl.Renderer, r *Rectangle) {
+	drawOutline(renderer, r.WorldVertices())
+}
+
+// DrawFilledRectangle draws a filled rectangle at its rotation.
+func DrawFilledRectangle(renderer *sdl.Renderer, r *Rectangle) {
+	DrawFilledPolygon(renderer, &Polygon{Vertices: r.WorldVertices()})
+}
+
+// DrawSegment draws a line segment.
+func DrawSegment(renderer *sdl.Renderer, s *Segment) {
+	renderer.DrawLine(int32(s.Start.X), int32(s.Start.Y), int32(s.End.X), int32(s.End.Y))
+}
+
+// DrawCapsule draws the outline of a capsule.
+func DrawCapsule(renderer *sdl.Renderer, c *Capsule) {
+	drawOutline(renderer, supportOutline(c))
+}
+
+// DrawFilledCapsule draws a filled capsule.
+func DrawFilledCapsule(renderer *sdl.Renderer, c *Capsule) {
+	DrawFilledPolygon(renderer, &Polygon{Vertices: supportOutline(c)})
+}
+
+// DrawEllipse draws the outline of an ellipse.
+func DrawEllipse(renderer *sdl.Renderer, e *Ellipse) {
+	drawOutline(renderer, supportOutline(e))
+}
+
+// DrawFilledEllipse draws a filled ellipse.
+func DrawFilledEllipse(renderer *sdl.Renderer, e *Ellipse) {
+	DrawFilledPolygon(renderer, &Polygon{Vertices: supportOutline(e)})
+}
+
+// DrawCompound draws the outline of every child of a compound shape.
+func DrawCompound(renderer *sdl.Renderer, c *Compound) {
+	for _, child := range c.WorldChildren() {
+		DrawShape(renderer, child)
+	}
+}
+
+// DrawFilledCompound draws every child of a compound shape filled.
+func DrawFilledCompound(renderer *sdl.Renderer, c *Compound) {
+	for _, child := range c.WorldChildren() {
+		DrawFilledShape(renderer, child)
+	}
+}
+
+// DrawShape draws the outline of any shape in world space, including its
+// rotation. Shapes without a dedicated routine are outlined from their
+// support points.
+func DrawShape(renderer *sdl.Renderer, shape geometry.Shape) {
+	switch s := shape.(type) {
+	case *geometry.Circle:
+		DrawCircle(renderer, s.Center, int32(s.Radius))
+	case *Polygon:
+		DrawPolygon(renderer, s)
+	case *Triangle:
+		DrawTriangle(renderer, s)
+	case *Rectangle:
+		DrawRectangle(renderer, s)
+	case *Segment:
+		DrawSegment(renderer, s)
+	case *Capsule:
+		DrawCapsule(renderer, s)
+	case *Ellipse:
+		DrawEllipse(renderer, s)
+	case *Compound:
+		DrawCompound(renderer, s)
+	default:
+		drawOutline(renderer, supportOutline(shape))
+	}
+}
+
+// DrawFilledShape draws any shape filled, in world space.
+func DrawFilledShape(renderer *sdl.Renderer, shape geometry.Shape) {
+	switch s := shape.(type) {
+	case *geometry.Circle:
+		DrawFilledCircle(renderer, s.Center, s.Radius)
+	case *Polygon:
+		DrawFilledPolygon(renderer, s)
+	case *Triangle:
+		DrawFilledTriangle(renderer, s)
+	case *Rectangle:
+		DrawFilledRectangle(renderer, s)
+	case *Segment:
+		DrawSegment(renderer, s)
+	case *Compound:
+		DrawFilledCompound(renderer, s)
+	default:
+		DrawFilledPolygon(renderer, &Polygon{Vertices: supportOutline(shape)})
+	}
+}
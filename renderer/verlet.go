@@ -0,0 +1,50 @@
+package renderer
+
+import (
+	"2d_game_engine/physics/verlet"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// DrawVerletSystem draws every distance constraint in a Verlet system as a
+// line, in the current draw colour. Ropes and cloth show as their links.
+func DrawVerletSystem(renderer *sdl.Renderer, s *verlet.System) {
+	drawConstraints(renderer, s.GetConstraints())
+}
+
+// DrawComposite draws a rope, cloth or blob as the lines of its links.
+func DrawComposite(renderer *sdl.Renderer, c *verlet.Composite) {
+	drawConstraints(renderer, c.Constraints)
+}
+
+// DrawFilledComposite fills a closed composite, such as a blob, through its
+// outline. Composites without an outline are drawn as lines.
+func DrawFilledComposite(renderer *sdl.Renderer, c *verlet.Composite) {
+	if len(c.Outline) < 3 {
+		DrawComposite(renderer, c)
+		return
+	}
+	vertices := make([]Vector2D, len(c.Outline))
+	for i, p := range c.Outline {
+		vertices[i] = p.GetPosition()
+	}
+	DrawFilledPolygon(renderer, &Polygon{Vertices: vertices})
+}
+
+// DrawParticle draws a particle as a filled circle of its collision radius.
+func DrawParticle(renderer *sdl.Renderer, p *verlet.Particle) {
+	DrawFilledCircle(renderer, p.GetPosition(), p.GetRadius())
+}
+
+// drawConstraints draws the distance constraints in a list as lines.
+func drawConstraints(renderer *sdl.Renderer, constraints []verlet.Constraint) {
+	for _, constraint := range constraints {
+		distance, ok := constraint.(*verlet.DistanceConstraint)
+		if !ok {
+			continue
+		}
+		a, b := distance.GetParticles()
+		from, to := a.GetPosition(), b.GetPosition()
+		renderer.DrawLine(int32(from.X), int32(from.Y), int32(to.X), int32(to.Y))
+	}
+}